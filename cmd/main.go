@@ -10,6 +10,8 @@ import (
 
 	"net/http"
 	"os"
+	"strconv"
+	"strings"
 
 	"github.com/go-chi/chi/v5"
 	"github.com/go-chi/chi/v5/middleware"
@@ -18,13 +20,21 @@ import (
 	"github.com/Jonattas-21/loan-engine/internal/api/handlers"
 	"github.com/Jonattas-21/loan-engine/internal/api/middlewares"
 	"github.com/Jonattas-21/loan-engine/internal/domain/entities"
+	"github.com/Jonattas-21/loan-engine/internal/domain/interfaces"
 	"github.com/Jonattas-21/loan-engine/internal/infrastructure/cache"
+	"github.com/Jonattas-21/loan-engine/internal/infrastructure/calendar"
+	"github.com/Jonattas-21/loan-engine/internal/infrastructure/creditscore"
 	"github.com/Jonattas-21/loan-engine/internal/infrastructure/database"
+	"github.com/Jonattas-21/loan-engine/internal/infrastructure/eligibility"
 	"github.com/Jonattas-21/loan-engine/internal/infrastructure/email"
+	"github.com/Jonattas-21/loan-engine/internal/infrastructure/insurance"
 	"github.com/Jonattas-21/loan-engine/internal/infrastructure/logger"
+	"github.com/Jonattas-21/loan-engine/internal/infrastructure/pricing"
 	"github.com/Jonattas-21/loan-engine/internal/infrastructure/queue"
+	"github.com/Jonattas-21/loan-engine/internal/infrastructure/rateindex"
 	"github.com/Jonattas-21/loan-engine/internal/infrastructure/repositories"
 	"github.com/Jonattas-21/loan-engine/internal/usecases"
+	"github.com/Jonattas-21/loan-engine/package/money"
 
 	_ "github.com/Jonattas-21/loan-engine/docs"
 	httpSwagger "github.com/swaggo/http-swagger"
@@ -60,9 +70,9 @@ func main() {
 	}))
 
 	//Conecting to the database
-	database := database.DatabaseNosql{Logger: log}
+	databaseNosql := database.DatabaseNosql{Logger: log}
 	log.Infoln("Connecting to the database " + os.Getenv("MONGO_HOST"))
-	mdb := database.NewDatabase()
+	mdb := databaseNosql.NewDatabase()
 	log.Infoln("Connected to the database ")
 	dbName := os.Getenv("MONGO_DB")
 
@@ -70,18 +80,62 @@ func main() {
 	rdb := cache.NewCache()
 	cacheRepo := &repositories.RedisRepository{Redis: rdb, Logger: log}
 
+	//Ages covered by the tiers, 18 to 100 when not informed
+	var minSupportedAge, maxSupportedAge int
+	if os.Getenv("LOAN_MIN_AGE") != "" {
+		minSupportedAge, err = strconv.Atoi(os.Getenv("LOAN_MIN_AGE"))
+		if err != nil {
+			log.Fatalln("Error reading loan min age: ", err.Error())
+			panic(err)
+		}
+	}
+	if os.Getenv("LOAN_MAX_AGE") != "" {
+		maxSupportedAge, err = strconv.Atoi(os.Getenv("LOAN_MAX_AGE"))
+		if err != nil {
+			log.Fatalln("Error reading loan max age: ", err.Error())
+			panic(err)
+		}
+	}
+
 	//Creating the condition usecase
-	repoLoanCondition := &repositories.DefaultRepository[entities.LoanCondition]{Client: mdb, DatabaseName: dbName, CollectionName: "loan_conditions"}
+	repoLoanCondition := &repositories.DefaultRepository[entities.LoanCondition]{Client: mdb, DatabaseName: dbName, CollectionName: "loan_conditions", Logger: log}
 	loanCondition_usecase := usecases.LoanCondition_usecase{
 		LoanConditionRepository: repoLoanCondition,
 		CacheRepository:         cacheRepo,
 		Logger:                  log, //todo future: make this a logger interface
+		MinSupportedAge:         minSupportedAge,
+		MaxSupportedAge:         maxSupportedAge,
 	}
 
-	//Init the loan conditions tiers
-	err = loanCondition_usecase.InitLoanEngineConditionsData()
+	//Migrating the data, the seed tiers are only inserted in an empty database
+	loanConditionSeeds, err := database.LoadLoanConditionSeeds(os.Getenv("LOAN_CONDITIONS_SEED_FILE"))
 	if err != nil {
-		log.Fatalln("Error initializing loan conditions: ", err.Error())
+		log.Fatalln("Error loading loan condition seeds: ", err.Error())
+		panic(err)
+	}
+
+	//Creating the fee usecase, the catalog is kept alongside the conditions
+	repoLoanFee := &repositories.DefaultRepository[entities.LoanFee]{Client: mdb, DatabaseName: dbName, CollectionName: "loan_fees", Logger: log}
+	loanFee_usecase := usecases.LoanFee_usecase{
+		LoanFeeRepository: repoLoanFee,
+		CacheRepository:   cacheRepo,
+		Logger:            log,
+	}
+	loanFeeSeeds, err := database.LoadLoanFeeSeeds(os.Getenv("LOAN_FEES_SEED_FILE"))
+	if err != nil {
+		log.Fatalln("Error loading loan fee seeds: ", err.Error())
+		panic(err)
+	}
+
+	repoMigration := &repositories.DefaultRepository[entities.Migration]{Client: mdb, DatabaseName: dbName, CollectionName: "migrations", Logger: log}
+	migration_usecase := usecases.Migration_usecase{
+		MigrationRepository: repoMigration,
+		Migrations:          append(loanCondition_usecase.LoanConditionMigrations(loanConditionSeeds), loanFee_usecase.LoanFeeMigrations(loanFeeSeeds)...),
+		Logger:              log,
+	}
+	err = migration_usecase.Migrate()
+	if err != nil {
+		log.Fatalln("Error migrating the data: ", err.Error())
 		panic(err)
 	}
 
@@ -93,16 +147,149 @@ func main() {
 	// 	panic(err)
 	// }
 
+	//Rounding used in the money calculations, HALF_EVEN when not informed
+	roundingMode, err := money.ParseRoundingMode(os.Getenv("MONEY_ROUNDING"))
+	if err != nil {
+		log.Fatalln("Error reading money rounding mode: ", err.Error())
+		panic(err)
+	}
+
+	//Holiday calendar used to move the due dates to business days, only the weekends are skipped without it
+	var holidayCalendar interfaces.HolidayCalendar
+	if os.Getenv("HOLIDAY_CALENDAR_FILE") != "" {
+		holidayCalendar, err = calendar.LoadHolidayCalendar(os.Getenv("HOLIDAY_CALENDAR_FILE"))
+		if err != nil {
+			log.Fatalln("Error loading holiday calendar: ", err.Error())
+			panic(err)
+		}
+	}
+
+	//Longest term offered, used when solving the term of a simulation
+	var maxInstallments int
+	if os.Getenv("MAX_INSTALLMENTS") != "" {
+		maxInstallments, err = strconv.Atoi(os.Getenv("MAX_INSTALLMENTS"))
+		if err != nil {
+			log.Fatalln("Error reading max installments: ", err.Error())
+			panic(err)
+		}
+	}
+
+	//Policy pricing rules, matched with the age tiers by priority
+	pricingRules, err := pricing.LoadPricingRules(os.Getenv("PRICING_RULES_FILE"))
+	if err != nil {
+		log.Fatalln("Error loading pricing rules: ", err.Error())
+		panic(err)
+	}
+	if errs := usecases.ValidatePricingRules(pricingRules); errs != nil {
+		log.Fatalln("Invalid pricing rules: ", strings.Join(errs, ", "))
+		panic(errs)
+	}
+
+	//Eligibility policies, the limits a simulation must meet
+	eligibilityPolicies, err := eligibility.LoadEligibilityPolicies(os.Getenv("ELIGIBILITY_POLICIES_FILE"))
+	if err != nil {
+		log.Fatalln("Error loading eligibility policies: ", err.Error())
+		panic(err)
+	}
+	if errs := usecases.ValidateEligibilityPolicies(eligibilityPolicies); errs != nil {
+		log.Fatalln("Invalid eligibility policies: ", strings.Join(errs, ", "))
+		panic(errs)
+	}
+
+	//Debt to income limit of the affordability check, 35% declining the offers above it when not informed
+	var maxDebtToIncome float64
+	if os.Getenv("MAX_DEBT_TO_INCOME") != "" {
+		maxDebtToIncome, err = strconv.ParseFloat(os.Getenv("MAX_DEBT_TO_INCOME"), 64)
+		if err != nil {
+			log.Fatalln("Error reading max debt to income: ", err.Error())
+			panic(err)
+		}
+	}
+	affordabilityAction := strings.ToUpper(os.Getenv("AFFORDABILITY_ACTION"))
+	if affordabilityAction != "" && affordabilityAction != usecases.AffordabilityDecline && affordabilityAction != usecases.AffordabilityFlag {
+		log.Fatalln("Error reading affordability action, must be DECLINE or FLAG: ", affordabilityAction)
+		panic(affordabilityAction)
+	}
+
+	//Credit score of the clients and the spreads by score band, the stand-in provider reads the scores from a file
+	var creditScoreProvider interfaces.CreditScoreProvider
+	if os.Getenv("CREDIT_SCORE_FILE") != "" {
+		creditScoreProvider, err = creditscore.LoadCreditScores(os.Getenv("CREDIT_SCORE_FILE"))
+		if err != nil {
+			log.Fatalln("Error loading credit scores: ", err.Error())
+			panic(err)
+		}
+	}
+	scoreBands, err := pricing.LoadScoreBands(os.Getenv("SCORE_BANDS_FILE"))
+	if err != nil {
+		log.Fatalln("Error loading score bands: ", err.Error())
+		panic(err)
+	}
+	if errs := usecases.ValidateScoreBands(scoreBands); errs != nil {
+		log.Fatalln("Invalid score bands: ", strings.Join(errs, ", "))
+		panic(errs)
+	}
+
+	//Rule of the recommended offer of a ladder, the lowest CET when not informed
+	recommendationRule := strings.ToUpper(os.Getenv("RECOMMENDATION_RULE"))
+	if recommendationRule != "" && recommendationRule != usecases.RecommendLowestCET && recommendationRule != usecases.RecommendLowestTotal && recommendationRule != usecases.RecommendLowestInstallment {
+		log.Fatalln("Error reading recommendation rule, must be LOWEST_CET, LOWEST_TOTAL or LOWEST_INSTALLMENT: ", recommendationRule)
+		panic(recommendationRule)
+	}
+
+	//Insurance catalog the simulations may add
+	insuranceProducts, err := insurance.LoadInsuranceProducts(os.Getenv("INSURANCE_PRODUCTS_FILE"))
+	if err != nil {
+		log.Fatalln("Error loading insurance products: ", err.Error())
+		panic(err)
+	}
+	if errs := usecases.ValidateInsuranceProducts(insuranceProducts); errs != nil {
+		log.Fatalln("Invalid insurance products: ", strings.Join(errs, ", "))
+		panic(errs)
+	}
+
+	//Index registry of the post-fixed loans, the stand-in market data endpoint when informed, else the file
+	var rateIndexProvider interfaces.RateIndexProvider
+	if os.Getenv("RATE_INDEX_URL") != "" {
+		rateIndexProvider = rateindex.NewHTTPRateIndexProvider(os.Getenv("RATE_INDEX_URL"))
+	} else if os.Getenv("RATE_INDEXES_FILE") != "" {
+		rateIndexProvider, err = rateindex.LoadRateIndexes(os.Getenv("RATE_INDEXES_FILE"))
+		if err != nil {
+			log.Fatalln("Error loading rate indexes: ", err.Error())
+			panic(err)
+		}
+	}
+
 	//Creating the simulation usecase
-	repoLoanSimulation := &repositories.DefaultRepository[entities.LoanSimulation]{Client: mdb, DatabaseName: dbName, CollectionName: "loan_simulations"}
+	repoLoanSimulation := &repositories.LoanSimulationRepository{
+		DefaultRepository: repositories.DefaultRepository[entities.LoanSimulation]{Client: mdb, DatabaseName: dbName, CollectionName: "loan_simulations", Logger: log},
+	}
+	err = repoLoanSimulation.CreateIndexes()
+	if err != nil {
+		log.Fatalln("Error creating loan simulation indexes: ", err.Error())
+		panic(err)
+	}
 	emailSender := email.EmailSender{}
 	loanSimulation_usecase := usecases.LoanSimulation_usecase{
 		LoanCondition:            &loanCondition_usecase,
+		LoanFee:                  &loanFee_usecase,
 		LoanSimulationRepository: repoLoanSimulation,
 		CacheRepository:          cacheRepo,
 		EmailSender:              &emailSender,
 		Logger:                   log,
 		QueuePublisher:           &queue,
+		RoundingMode:             roundingMode,
+		HolidayCalendar:          holidayCalendar,
+		MaxInstallments:          maxInstallments,
+		PricingRules:             pricingRules,
+		EligibilityPolicies:      eligibilityPolicies,
+		MaxDebtToIncome:          maxDebtToIncome,
+		AffordabilityAction:      affordabilityAction,
+		CreditScoreProvider:      creditScoreProvider,
+		ScoreBands:               scoreBands,
+		RecommendationRule:       recommendationRule,
+		InsuranceProducts:        insuranceProducts,
+		RateIndexProvider:        rateIndexProvider,
 	}
 
 	//Creating the handlers
@@ -115,6 +302,10 @@ func main() {
 		LoanCondition_usecase: &loanCondition_usecase,
 		Logger:                log,
 	}
+	loanFee_handler := handlers.LoanFeeHandler{
+		LoanFee_usecase: &loanFee_usecase,
+		Logger:          log,
+	}
 	loanSimulation_handler := handlers.LoanSimulationHandler{
 		LoanSimulation_usecase: loanSimulation_usecase,
 		Logger:                 log,
@@ -137,13 +328,30 @@ func main() {
 		}
 		r.Post("/", loanCondition_handler.SetLoanCondition)
 		r.Get("/", loanCondition_handler.GetLoanConditions)
+		r.Put("/", loanCondition_handler.ReplaceLoanConditions)
+		r.Delete("/{name}", loanCondition_handler.DeleteLoanCondition)
+		r.Get("/{name}/versions", loanCondition_handler.GetLoanConditionVersions)
+	})
+
+	router.Route("/api/v1/loanfees/", func(r chi.Router) {
+		if useAuth == "true" {
+			r.Use(middlewares.Auth)
+		}
+		r.Post("/", loanFee_handler.SetLoanFee)
+		r.Get("/", loanFee_handler.GetLoanFees)
+		r.Delete("/{name}", loanFee_handler.DeleteLoanFee)
 	})
 
 	router.Route("/api/v1/loansimulations/", func(r chi.Router) {
 		if useAuth == "true" {
 			r.Use(middlewares.Auth)
 		}
-		r.Get("/", loanSimulation_handler.GetLoanSimulation)
+		r.Post("/", loanSimulation_handler.GetLoanSimulation)
+		r.Get("/", loanSimulation_handler.GetLoanSimulationDeprecated)
+		r.Get("/search", loanSimulation_handler.SearchLoanSimulations)
+		r.Post("/refinancing", loanSimulation_handler.CompareRefinancing)
+		r.Get("/{id}", loanSimulation_handler.GetLoanSimulationByID)
+		r.Post("/{id}/prepayments", loanSimulation_handler.PrepayLoanSimulation)
 	})
 
 	router.Get("/swagger/*", httpSwagger.WrapHandler)
@@ -74,6 +74,14 @@ const docTemplate = `{
                     "conditions"
                 ],
                 "summary": "Show the list of loan conditions, fees by age group",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Conditions in force at the date, YYYY-MM-DD or RFC3339, now when not informed",
+                        "name": "date",
+                        "in": "query"
+                    }
+                ],
                 "responses": {
                     "200": {
                         "description": "OK",
@@ -86,8 +94,29 @@ const docTemplate = `{
                     }
                 }
             },
+            "put": {
+                "description": "set every tier at once from effective_from, to split, merge and reshape the age bands.\nThe tiers left out are closed, nothing changes when the new set leaves a gap or has an overlap",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "conditions"
+                ],
+                "summary": "replace the whole set of loan conditions",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            },
             "post": {
-                "description": "update a loan condition by name tier1, tier2, tier3, tier4",
+                "description": "create a tier or update its interest rate and ages, the ages are kept when not informed.\nEvery update is a new version in force from effective_from, the previous versions are kept.\nThe tiers must cover the supported ages with no overlap and no gap, an invalid change is rejected",
                 "consumes": [
                     "application/json"
                 ],
@@ -97,20 +126,211 @@ const docTemplate = `{
                 "tags": [
                     "conditions"
                 ],
-                "summary": "update a loan condition by name",
+                "summary": "create or update a loan condition by name",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/v1/loanconditions/{name}": {
+            "delete": {
+                "description": "close the tier from effective_from, its ages go to the merge_into tier so no age is left out.\nThe previous versions are kept",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "conditions"
+                ],
+                "summary": "delete a loan condition by name",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Tier name",
+                        "name": "name",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Tier that takes the ages of the deleted one",
+                        "name": "merge_into",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Date of the deletion, YYYY-MM-DD or RFC3339, now when not informed, it can't be in the past",
+                        "name": "effective_from",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/v1/loanconditions/{name}/versions": {
+            "get": {
+                "description": "Get every version of the tier, the oldest first",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "conditions"
+                ],
+                "summary": "Show the versions of a loan condition",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Tier name",
+                        "name": "name",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/github_com_Jonattas-21_loan-engine_internal_domain_entities.LoanCondition"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/v1/loanfees": {
+            "get": {
+                "description": "Get all the fees charged to the simulations",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "fees"
+                ],
+                "summary": "Show the fee catalog",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/github_com_Jonattas-21_loan-engine_internal_domain_entities.LoanFee"
+                            }
+                        }
+                    }
+                }
+            },
+            "post": {
+                "description": "set an origination (TAC), registration or admin fee, flat or a percentage of the loan amount,\nfinanced or paid upfront. Admin fees are flat and paid with each installment.\nThe fees apply to the simulations of their products and currencies, any of them when not informed",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "fees"
+                ],
+                "summary": "create or update a fee of the catalog by name",
+                "parameters": [
+                    {
+                        "description": "Fee",
+                        "name": "fee",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/github_com_Jonattas-21_loan-engine_internal_api_dto.LoanFeeRequest_dto"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/v1/loanfees/{name}": {
+            "delete": {
+                "description": "the fee stops being charged, the saved simulations keep it",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "fees"
+                ],
+                "summary": "delete a fee of the catalog by name",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Fee name",
+                        "name": "name",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
                             "type": "string"
                         }
+                    },
+                    "404": {
+                        "description": "loan fee not found",
+                        "schema": {
+                            "type": "string"
+                        }
                     }
                 }
             }
         },
         "/v1/loansimulations": {
+            "get": {
+                "description": "The first verb of the simulation, kept for the current clients: use POST /v1/loansimulations",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "simulation"
+                ],
+                "summary": "Get a plenty of loan simulations, deprecated",
+                "deprecated": true,
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_Jonattas-21_loan-engine_internal_api_dto.LoanSimulationResponse_dto"
+                        }
+                    },
+                    "403": {
+                        "description": "simulations can only be requested for the authenticated email",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            },
             "post": {
-                "description": "Get a plenty of loan simulations",
+                "description": "Get a plenty of loan simulations\nA request with terms or a term range gets a ladder of offers in offer_ladders, with the recommended one flagged",
                 "consumes": [
                     "application/json"
                 ],
@@ -127,40 +347,585 @@ const docTemplate = `{
                         "schema": {
                             "$ref": "#/definitions/github_com_Jonattas-21_loan-engine_internal_api_dto.LoanSimulationResponse_dto"
                         }
+                    },
+                    "403": {
+                        "description": "simulations can only be requested for the authenticated email",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/v1/loansimulations/refinancing": {
+            "post": {
+                "description": "Simulate the offer paying off the outstanding balance of an external loan (portability) at our rate,\nand compare the installments, the total paid and the break-even month. The offer is saved as a simulation",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "simulation"
+                ],
+                "summary": "Compare an external loan with a refinancing offer",
+                "parameters": [
+                    {
+                        "description": "External loan and the request of the offer",
+                        "name": "refinancing",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/github_com_Jonattas-21_loan-engine_internal_api_dto.RefinancingRequest_dto"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_Jonattas-21_loan-engine_internal_domain_entities.RefinancingComparison"
+                        }
+                    },
+                    "400": {
+                        "description": "invalid request",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "403": {
+                        "description": "simulations can only be requested for the authenticated email",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "422": {
+                        "description": "offer declined",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_Jonattas-21_loan-engine_internal_domain_entities.SimulationError"
+                        }
+                    }
+                }
+            }
+        },
+        "/v1/loansimulations/search": {
+            "get": {
+                "description": "Search the saved simulations with filters and cursor pagination, an authenticated user only sees the own simulations",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "simulation"
+                ],
+                "summary": "Search the saved loan simulations",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Email of the simulations",
+                        "name": "email",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Simulation date from, YYYY-MM-DD or RFC3339",
+                        "name": "date_from",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Simulation date to, YYYY-MM-DD or RFC3339",
+                        "name": "date_to",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Currency, R$ or U$",
+                        "name": "currency",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Minimum loan amount",
+                        "name": "min_amount",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Maximum loan amount",
+                        "name": "max_amount",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Minimum number of installments",
+                        "name": "min_installments",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Maximum number of installments",
+                        "name": "max_installments",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "simulation_date (default), loan_amount or total_installments",
+                        "name": "sort",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "asc or desc, desc is the default for simulation_date",
+                        "name": "order",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "next_cursor of the previous page",
+                        "name": "cursor",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Page size, 20 by default and up to 100",
+                        "name": "limit",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_Jonattas-21_loan-engine_internal_api_dto.LoanSimulationSearchResponse_dto"
+                        }
+                    },
+                    "400": {
+                        "description": "invalid filters, or a cursor of another search",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/v1/loansimulations/{id}": {
+            "get": {
+                "description": "Get a saved loan simulation, only the user who requested it can see it",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "simulation"
+                ],
+                "summary": "Get a loan simulation by id",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Simulation id",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_Jonattas-21_loan-engine_internal_domain_entities.LoanSimulation"
+                        }
+                    },
+                    "404": {
+                        "description": "loan simulation not found",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/v1/loansimulations/{id}/prepayments": {
+            "post": {
+                "description": "Pay a saved simulation early, in total or in part, at an installment or at a date between the due dates\nThe payoff discounts the interest of the future installments, a partial prepayment recalculates the rest\nof the schedule reducing the installments (default) or the term",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "simulation"
+                ],
+                "summary": "Simulate a prepayment of a loan simulation",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Simulation id",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Prepayment event",
+                        "name": "prepayment",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/github_com_Jonattas-21_loan-engine_internal_api_dto.PrepaymentRequest_dto"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_Jonattas-21_loan-engine_internal_domain_entities.PrepaymentSimulation"
+                        }
+                    },
+                    "400": {
+                        "description": "invalid prepayment",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "404": {
+                        "description": "loan simulation not found",
+                        "schema": {
+                            "type": "string"
+                        }
                     }
                 }
             }
         }
     },
     "definitions": {
+        "github_com_Jonattas-21_loan-engine_internal_api_dto.LoanFeeRequest_dto": {
+            "type": "object",
+            "properties": {
+                "amount": {
+                    "$ref": "#/definitions/github_com_Jonattas-21_loan-engine_package_money.Money"
+                },
+                "calculation": {
+                    "description": "FLAT or PERCENTAGE of the loan amount",
+                    "type": "string"
+                },
+                "currencies": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "kind": {
+                    "description": "ORIGINATION, REGISTRATION or ADMIN",
+                    "type": "string"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "payment": {
+                    "description": "FINANCED (default) or UPFRONT",
+                    "type": "string"
+                },
+                "products": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "rate": {
+                    "type": "number"
+                }
+            }
+        },
         "github_com_Jonattas-21_loan-engine_internal_api_dto.LoanSimulationResponse_dto": {
             "type": "object",
             "properties": {
                 "errorSimulations": {
+                    "description": "declined requests with the reasons",
                     "type": "array",
-                    "items": {}
+                    "items": {
+                        "$ref": "#/definitions/github_com_Jonattas-21_loan-engine_internal_domain_entities.SimulationError"
+                    }
                 },
                 "loanSimulations": {
                     "type": "array",
                     "items": {
                         "$ref": "#/definitions/github_com_Jonattas-21_loan-engine_internal_domain_entities.LoanSimulation"
                     }
+                },
+                "offer_ladders": {
+                    "description": "offers of the requests over several terms",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/github_com_Jonattas-21_loan-engine_internal_domain_entities.OfferLadder"
+                    }
+                }
+            }
+        },
+        "github_com_Jonattas-21_loan-engine_internal_api_dto.LoanSimulationSearchResponse_dto": {
+            "type": "object",
+            "properties": {
+                "loan_simulations": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/github_com_Jonattas-21_loan-engine_internal_domain_entities.LoanSimulation"
+                    }
+                },
+                "next_cursor": {
+                    "description": "cursor of the next page, empty on the last one",
+                    "type": "string"
+                }
+            }
+        },
+        "github_com_Jonattas-21_loan-engine_internal_api_dto.PrepaymentRequest_dto": {
+            "type": "object",
+            "properties": {
+                "amount": {
+                    "description": "extra amount of a PARTIAL prepayment",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/github_com_Jonattas-21_loan-engine_package_money.Money"
+                        }
+                    ]
+                },
+                "at_installment": {
+                    "description": "paid with this installment, on its due date",
+                    "type": "integer"
+                },
+                "date": {
+                    "description": "or at this date, between the due dates",
+                    "type": "string"
+                },
+                "kind": {
+                    "description": "TOTAL pays off the loan, PARTIAL pays the amount",
+                    "type": "string"
+                },
+                "recalculation": {
+                    "description": "REDUCE_INSTALLMENT (default) keeps the term, REDUCE_TERM keeps the installments",
+                    "type": "string"
+                }
+            }
+        },
+        "github_com_Jonattas-21_loan-engine_internal_api_dto.RefinancingRequest_dto": {
+            "type": "object",
+            "properties": {
+                "current_installment": {
+                    "description": "calculated by PRICE at the current rate when not informed",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/github_com_Jonattas-21_loan-engine_package_money.Money"
+                        }
+                    ]
+                },
+                "current_interest_rate": {
+                    "description": "annual percentage of the external loan",
+                    "type": "number"
+                },
+                "offer": {
+                    "description": "client and terms of the new loan, the remaining installments when no term is informed",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/github_com_Jonattas-21_loan-engine_internal_api_dto.SimulationRequest_dto"
+                        }
+                    ]
+                },
+                "outstanding_balance": {
+                    "description": "balance of the external loan, the amount of the new one",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/github_com_Jonattas-21_loan-engine_package_money.Money"
+                        }
+                    ]
+                },
+                "remaining_installments": {
+                    "description": "installments left of the external loan",
+                    "type": "integer"
+                }
+            }
+        },
+        "github_com_Jonattas-21_loan-engine_internal_api_dto.SimulationRequest_dto": {
+            "type": "object",
+            "properties": {
+                "amortization_system": {
+                    "description": "PRICE (default), SAC or SACRE",
+                    "type": "string"
+                },
+                "assumed_index_rate": {
+                    "description": "annual index assumed for the whole term instead of the curve of the registry",
+                    "type": "number"
+                },
+                "bithDate": {
+                    "type": "string"
+                },
+                "currency": {
+                    "type": "string"
+                },
+                "disbursement_date": {
+                    "description": "today when not informed",
+                    "type": "string"
+                },
+                "email": {
+                    "type": "string"
+                },
+                "existing_debt_payments": {
+                    "description": "monthly payments of the other debts of the client",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/github_com_Jonattas-21_loan-engine_package_money.Money"
+                        }
+                    ]
+                },
+                "grace_interest": {
+                    "description": "CAPITALIZED (default) or PAID as interest only installments",
+                    "type": "string"
+                },
+                "grace_period": {
+                    "description": "length of the grace period (carência), none when zero",
+                    "type": "integer"
+                },
+                "grace_period_unit": {
+                    "description": "MONTHS (default) or DAYS",
+                    "type": "string"
+                },
+                "installment_amount": {
+                    "description": "desired installment in INSTALLMENT mode, maximum installment in TERM mode",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/github_com_Jonattas-21_loan-engine_package_money.Money"
+                        }
+                    ]
+                },
+                "installments": {
+                    "type": "integer"
+                },
+                "insurance_payment": {
+                    "description": "FINANCED (default) or INSTALLMENT",
+                    "type": "string"
+                },
+                "insurances": {
+                    "description": "codes of the insurance products of the catalog",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "iof_payment": {
+                    "description": "FINANCED (default) or UPFRONT",
+                    "type": "string"
+                },
+                "loanAmount": {
+                    "$ref": "#/definitions/github_com_Jonattas-21_loan-engine_package_money.Money"
+                },
+                "max_term": {
+                    "type": "integer"
+                },
+                "min_term": {
+                    "description": "ladder of offers from the min to the max term by the step",
+                    "type": "integer"
+                },
+                "monthly_income": {
+                    "description": "declared income, the affordability is checked when informed",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/github_com_Jonattas-21_loan-engine_package_money.Money"
+                        }
+                    ]
+                },
+                "payment_day": {
+                    "description": "preferred day of the month, the disbursement day when not informed",
+                    "type": "integer"
+                },
+                "product": {
+                    "description": "product the fees of the catalog may match, like PERSONAL or PAYROLL",
+                    "type": "string"
+                },
+                "rate_index": {
+                    "description": "CDI, IPCA or SELIC for a post-fixed loan, the rate of the pricing is the spread over it",
+                    "type": "string"
+                },
+                "segment": {
+                    "description": "customer segment the pricing rules may match, like RETAIL or PRIVATE",
+                    "type": "string"
+                },
+                "simulation_mode": {
+                    "description": "AMOUNT (default) simulates the loan amount, INSTALLMENT finds the maximum amount for the installment amount, TERM the shortest term under it",
+                    "type": "string"
+                },
+                "term_step": {
+                    "description": "12 when not informed",
+                    "type": "integer"
+                },
+                "terms": {
+                    "description": "ladder of offers over these terms instead of a single simulation, a repeated term is offered once",
+                    "type": "array",
+                    "items": {
+                        "type": "integer"
+                    }
+                }
+            }
+        },
+        "github_com_Jonattas-21_loan-engine_internal_api_dto.TokenResponse_dto": {
+            "type": "object",
+            "properties": {
+                "access_token": {
+                    "type": "string"
+                },
+                "expires_in": {
+                    "type": "integer"
+                },
+                "refresh_token": {
+                    "type": "string"
+                },
+                "token_type": {
+                    "type": "string"
+                }
+            }
+        },
+        "github_com_Jonattas-21_loan-engine_internal_domain_entities.DeclineReason": {
+            "type": "object",
+            "properties": {
+                "code": {
+                    "type": "string"
+                },
+                "limit": {
+                    "type": "string"
+                },
+                "message": {
+                    "type": "string"
+                },
+                "value": {
+                    "type": "string"
+                }
+            }
+        },
+        "github_com_Jonattas-21_loan-engine_internal_domain_entities.DeclinedOffer": {
+            "type": "object",
+            "properties": {
+                "installments": {
+                    "type": "integer"
+                },
+                "reasons": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/github_com_Jonattas-21_loan-engine_internal_domain_entities.DeclineReason"
+                    }
                 }
             }
         },
-        "github_com_Jonattas-21_loan-engine_internal_api_dto.TokenResponse_dto": {
+        "github_com_Jonattas-21_loan-engine_internal_domain_entities.FeeCharge": {
             "type": "object",
             "properties": {
-                "access_token": {
-                    "type": "string"
+                "amount": {
+                    "$ref": "#/definitions/github_com_Jonattas-21_loan-engine_package_money.Money"
                 },
-                "expires_in": {
-                    "type": "integer"
+                "kind": {
+                    "type": "string"
                 },
-                "refresh_token": {
+                "name": {
                     "type": "string"
                 },
-                "token_type": {
+                "payment": {
+                    "description": "FINANCED, UPFRONT or INSTALLMENT",
                     "type": "string"
                 }
             }
@@ -168,23 +933,114 @@ const docTemplate = `{
         "github_com_Jonattas-21_loan-engine_internal_domain_entities.Installment": {
             "type": "object",
             "properties": {
+                "admin_fee_amount": {
+                    "description": "admin fees paid with the installment",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/github_com_Jonattas-21_loan-engine_package_money.Money"
+                        }
+                    ]
+                },
+                "capitalized_interest": {
+                    "description": "grace interest added to the balance instead of paid",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/github_com_Jonattas-21_loan-engine_package_money.Money"
+                        }
+                    ]
+                },
+                "closing_balance": {
+                    "$ref": "#/definitions/github_com_Jonattas-21_loan-engine_package_money.Money"
+                },
                 "currency": {
                     "type": "string"
                 },
-                "installment_amount": {
+                "due_date": {
+                    "type": "string"
+                },
+                "estimated": {
+                    "description": "the index of the period is projected, not published",
+                    "type": "boolean"
+                },
+                "index_rate": {
+                    "description": "annual index of the period of a post-fixed loan",
                     "type": "number"
                 },
+                "installment_amount": {
+                    "$ref": "#/definitions/github_com_Jonattas-21_loan-engine_package_money.Money"
+                },
                 "installment_fee_amount": {
-                    "type": "number"
+                    "$ref": "#/definitions/github_com_Jonattas-21_loan-engine_package_money.Money"
                 },
                 "installment_number": {
                     "type": "integer"
+                },
+                "insurance_amount": {
+                    "description": "premiums paid with the installment",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/github_com_Jonattas-21_loan-engine_package_money.Money"
+                        }
+                    ]
+                },
+                "kind": {
+                    "type": "string"
+                },
+                "opening_balance": {
+                    "$ref": "#/definitions/github_com_Jonattas-21_loan-engine_package_money.Money"
+                },
+                "principal_amount": {
+                    "$ref": "#/definitions/github_com_Jonattas-21_loan-engine_package_money.Money"
+                }
+            }
+        },
+        "github_com_Jonattas-21_loan-engine_internal_domain_entities.InsuranceCharge": {
+            "type": "object",
+            "properties": {
+                "code": {
+                    "type": "string"
+                },
+                "monthly_premium": {
+                    "$ref": "#/definitions/github_com_Jonattas-21_loan-engine_package_money.Money"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "payment": {
+                    "description": "FINANCED or INSTALLMENT",
+                    "type": "string"
+                },
+                "premium_amount": {
+                    "description": "premium of the whole loan",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/github_com_Jonattas-21_loan-engine_package_money.Money"
+                        }
+                    ]
+                },
+                "premium_type": {
+                    "type": "string"
+                },
+                "rate": {
+                    "type": "number"
                 }
             }
         },
         "github_com_Jonattas-21_loan-engine_internal_domain_entities.LoanCondition": {
             "type": "object",
             "properties": {
+                "author": {
+                    "type": "string"
+                },
+                "effective_from": {
+                    "type": "string"
+                },
+                "effective_to": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string"
+                },
                 "interest_rate": {
                     "type": "number"
                 },
@@ -199,25 +1055,152 @@ const docTemplate = `{
                 },
                 "name": {
                     "type": "string"
+                },
+                "version": {
+                    "type": "integer"
+                }
+            }
+        },
+        "github_com_Jonattas-21_loan-engine_internal_domain_entities.LoanFee": {
+            "type": "object",
+            "properties": {
+                "amount": {
+                    "$ref": "#/definitions/github_com_Jonattas-21_loan-engine_package_money.Money"
+                },
+                "author": {
+                    "type": "string"
+                },
+                "calculation": {
+                    "description": "FLAT amount or PERCENTAGE of the loan amount, ADMIN fees are flat by installment",
+                    "type": "string"
+                },
+                "currencies": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "kind": {
+                    "description": "ORIGINATION, REGISTRATION or ADMIN",
+                    "type": "string"
+                },
+                "modified_date": {
+                    "type": "string"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "payment": {
+                    "description": "FINANCED or UPFRONT, ADMIN fees are paid with the installments",
+                    "type": "string"
+                },
+                "products": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "rate": {
+                    "description": "percentage of the loan amount",
+                    "type": "number"
                 }
             }
         },
         "github_com_Jonattas-21_loan-engine_internal_domain_entities.LoanSimulation": {
             "type": "object",
             "properties": {
+                "affordability_flag": {
+                    "description": "the ratio is above the limit",
+                    "type": "boolean"
+                },
+                "amortization_system": {
+                    "type": "string"
+                },
                 "amount_fee_to_be_paid": {
-                    "type": "number"
+                    "$ref": "#/definitions/github_com_Jonattas-21_loan-engine_package_money.Money"
                 },
                 "amount_to_be_paid": {
+                    "$ref": "#/definitions/github_com_Jonattas-21_loan-engine_package_money.Money"
+                },
+                "credit_score": {
+                    "type": "integer"
+                },
+                "credit_score_band": {
+                    "type": "string"
+                },
+                "credit_score_spread": {
+                    "description": "annual percentage points added to the rate of the pricing rule",
                     "type": "number"
                 },
                 "currency": {
                     "type": "string"
                 },
+                "debt_to_income_ratio": {
+                    "description": "percentage of the income taken by the highest installment and the other debts",
+                    "type": "number"
+                },
+                "disbursement_date": {
+                    "type": "string"
+                },
+                "effective_annual_rate": {
+                    "type": "number"
+                },
+                "effective_monthly_rate": {
+                    "description": "CET, percentage including every cost of the loan",
+                    "type": "number"
+                },
                 "email": {
                     "type": "string"
                 },
+                "estimated": {
+                    "description": "the amounts are projected over the index curve",
+                    "type": "boolean"
+                },
+                "existing_debt_payments": {
+                    "$ref": "#/definitions/github_com_Jonattas-21_loan-engine_package_money.Money"
+                },
                 "fee_amount_percentage": {
+                    "description": "annual interest rate, of the first period for a post-fixed loan",
+                    "type": "number"
+                },
+                "fees": {
+                    "description": "every charge of the loan itemized, the interest, IOF and insurances included",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/github_com_Jonattas-21_loan-engine_internal_domain_entities.FeeCharge"
+                    }
+                },
+                "fees_amount": {
+                    "description": "fees of the catalog, financed, upfront or with the installments",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/github_com_Jonattas-21_loan-engine_package_money.Money"
+                        }
+                    ]
+                },
+                "financed_amount": {
+                    "description": "loan amount plus the financed charges",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/github_com_Jonattas-21_loan-engine_package_money.Money"
+                        }
+                    ]
+                },
+                "grace_interest": {
+                    "type": "string"
+                },
+                "grace_period": {
+                    "type": "integer"
+                },
+                "grace_period_unit": {
+                    "type": "string"
+                },
+                "id": {
+                    "description": "ULID, sorts by the simulation date",
+                    "type": "string"
+                },
+                "index_spread": {
+                    "description": "annual percentage points over the index",
                     "type": "number"
                 },
                 "installments": {
@@ -226,16 +1209,278 @@ const docTemplate = `{
                         "$ref": "#/definitions/github_com_Jonattas-21_loan-engine_internal_domain_entities.Installment"
                     }
                 },
+                "insurance_amount": {
+                    "description": "premiums of the insurances, financed or with the installments",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/github_com_Jonattas-21_loan-engine_package_money.Money"
+                        }
+                    ]
+                },
+                "insurances": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/github_com_Jonattas-21_loan-engine_internal_domain_entities.InsuranceCharge"
+                    }
+                },
+                "iof_amount": {
+                    "$ref": "#/definitions/github_com_Jonattas-21_loan-engine_package_money.Money"
+                },
+                "iof_payment": {
+                    "type": "string"
+                },
                 "loan_amount": {
-                    "type": "number"
+                    "$ref": "#/definitions/github_com_Jonattas-21_loan-engine_package_money.Money"
+                },
+                "loan_condition_id": {
+                    "description": "version of the age tier of the client",
+                    "type": "string"
+                },
+                "loan_condition_name": {
+                    "type": "string"
+                },
+                "loan_condition_version": {
+                    "type": "integer"
+                },
+                "monthly_income": {
+                    "$ref": "#/definitions/github_com_Jonattas-21_loan-engine_package_money.Money"
+                },
+                "offer_ladder_id": {
+                    "description": "offers of the same request share it",
+                    "type": "string"
+                },
+                "payment_day": {
+                    "type": "integer"
+                },
+                "pricing_rule_id": {
+                    "description": "rule the rate came from, the age tier or a policy rule",
+                    "type": "string"
+                },
+                "pricing_rule_kind": {
+                    "description": "AGE_TIER or POLICY",
+                    "type": "string"
+                },
+                "pricing_rule_name": {
+                    "type": "string"
+                },
+                "product": {
+                    "type": "string"
+                },
+                "rate_index": {
+                    "description": "index of a post-fixed loan, empty for a fixed rate",
+                    "type": "string"
+                },
+                "recommended": {
+                    "description": "recommended offer of its ladder",
+                    "type": "boolean"
+                },
+                "remaining_margin": {
+                    "description": "income under the limit still free after the loan, negative above it",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/github_com_Jonattas-21_loan-engine_package_money.Money"
+                        }
+                    ]
+                },
+                "requested_installment": {
+                    "description": "desired installment the loan amount was solved for",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/github_com_Jonattas-21_loan-engine_package_money.Money"
+                        }
+                    ]
+                },
+                "segment": {
+                    "type": "string"
                 },
                 "simulation_date": {
                     "type": "string"
                 },
+                "simulation_mode": {
+                    "type": "string"
+                },
+                "total_installments": {
+                    "type": "integer"
+                },
+                "upfront_amount": {
+                    "description": "charges paid at the disbursement",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/github_com_Jonattas-21_loan-engine_package_money.Money"
+                        }
+                    ]
+                }
+            }
+        },
+        "github_com_Jonattas-21_loan-engine_internal_domain_entities.OfferLadder": {
+            "type": "object",
+            "properties": {
+                "declined_offers": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/github_com_Jonattas-21_loan-engine_internal_domain_entities.DeclinedOffer"
+                    }
+                },
+                "email": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "offers": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/github_com_Jonattas-21_loan-engine_internal_domain_entities.LoanSimulation"
+                    }
+                },
+                "recommendation_rule": {
+                    "type": "string"
+                },
+                "recommended_id": {
+                    "description": "empty when no offer meets the rule",
+                    "type": "string"
+                },
+                "request_index": {
+                    "type": "integer"
+                }
+            }
+        },
+        "github_com_Jonattas-21_loan-engine_internal_domain_entities.PrepaymentSimulation": {
+            "type": "object",
+            "properties": {
+                "accrued_interest": {
+                    "$ref": "#/definitions/github_com_Jonattas-21_loan-engine_package_money.Money"
+                },
+                "currency": {
+                    "type": "string"
+                },
+                "discounted_interest": {
+                    "description": "interest of the remaining installments not paid anymore",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/github_com_Jonattas-21_loan-engine_package_money.Money"
+                        }
+                    ]
+                },
+                "installments": {
+                    "description": "recalculated schedule of a partial prepayment",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/github_com_Jonattas-21_loan-engine_internal_domain_entities.Installment"
+                    }
+                },
+                "installments_paid": {
+                    "description": "installments due until the prepayment",
+                    "type": "integer"
+                },
+                "kind": {
+                    "type": "string"
+                },
+                "loan_simulation_id": {
+                    "type": "string"
+                },
+                "outstanding_balance": {
+                    "$ref": "#/definitions/github_com_Jonattas-21_loan-engine_package_money.Money"
+                },
+                "payoff_amount": {
+                    "$ref": "#/definitions/github_com_Jonattas-21_loan-engine_package_money.Money"
+                },
+                "prepayment_amount": {
+                    "$ref": "#/definitions/github_com_Jonattas-21_loan-engine_package_money.Money"
+                },
+                "prepayment_date": {
+                    "type": "string"
+                },
+                "recalculation": {
+                    "type": "string"
+                },
+                "remaining_balance": {
+                    "$ref": "#/definitions/github_com_Jonattas-21_loan-engine_package_money.Money"
+                },
                 "total_installments": {
+                    "description": "installments left after the prepayment",
+                    "type": "integer"
+                }
+            }
+        },
+        "github_com_Jonattas-21_loan-engine_internal_domain_entities.RefinancingComparison": {
+            "type": "object",
+            "properties": {
+                "break_even_installment": {
+                    "description": "from this month on the offer has cost less, 0 when it never does",
+                    "type": "integer"
+                },
+                "current_installment": {
+                    "$ref": "#/definitions/github_com_Jonattas-21_loan-engine_package_money.Money"
+                },
+                "current_interest_rate": {
+                    "type": "number"
+                },
+                "current_total": {
+                    "description": "installments left of the external loan",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/github_com_Jonattas-21_loan-engine_package_money.Money"
+                        }
+                    ]
+                },
+                "installment_savings": {
+                    "description": "by month, negative when the new installment is higher",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/github_com_Jonattas-21_loan-engine_package_money.Money"
+                        }
+                    ]
+                },
+                "new_installment": {
+                    "description": "highest installment of the offer",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/github_com_Jonattas-21_loan-engine_package_money.Money"
+                        }
+                    ]
+                },
+                "new_total": {
+                    "description": "installments of the offer plus the charges paid upfront",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/github_com_Jonattas-21_loan-engine_package_money.Money"
+                        }
+                    ]
+                },
+                "offer": {
+                    "$ref": "#/definitions/github_com_Jonattas-21_loan-engine_internal_domain_entities.LoanSimulation"
+                },
+                "outstanding_balance": {
+                    "$ref": "#/definitions/github_com_Jonattas-21_loan-engine_package_money.Money"
+                },
+                "remaining_installments": {
+                    "type": "integer"
+                },
+                "total_savings": {
+                    "$ref": "#/definitions/github_com_Jonattas-21_loan-engine_package_money.Money"
+                }
+            }
+        },
+        "github_com_Jonattas-21_loan-engine_internal_domain_entities.SimulationError": {
+            "type": "object",
+            "properties": {
+                "email": {
+                    "type": "string"
+                },
+                "reasons": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/github_com_Jonattas-21_loan-engine_internal_domain_entities.DeclineReason"
+                    }
+                },
+                "request_index": {
                     "type": "integer"
                 }
             }
+        },
+        "github_com_Jonattas-21_loan-engine_package_money.Money": {
+            "type": "object"
         }
     }
 }`
@@ -1,8 +1,17 @@
 package dto
 
+import "time"
+
 type LoanConditionRequest_dto struct {
-	Name         string 
-	InterestRate float64  
-	MinAge       int  
-	MaxAge       int  
+	Name          string
+	InterestRate  float64
+	MinAge        int
+	MaxAge        int
+	EffectiveFrom time.Time `json:"effective_from"` // now when not informed, it can't be in the past
+}
+
+// LoanConditionsRequest_dto replaces the whole set of tiers at once, the tiers left out stop being offered
+type LoanConditionsRequest_dto struct {
+	LoanConditions []LoanConditionRequest_dto `json:"loan_conditions"`
+	EffectiveFrom  time.Time                  `json:"effective_from"` // now when not informed, it can't be in the past
 }
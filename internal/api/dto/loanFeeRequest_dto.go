@@ -0,0 +1,14 @@
+package dto
+
+import "github.com/Jonattas-21/loan-engine/package/money"
+
+type LoanFeeRequest_dto struct {
+	Name        string      `json:"name"`
+	Kind        string      `json:"kind"`        // ORIGINATION, REGISTRATION or ADMIN
+	Calculation string      `json:"calculation"` // FLAT or PERCENTAGE of the loan amount
+	Amount      money.Money `json:"amount"`
+	Rate        float64     `json:"rate"`
+	Payment     string      `json:"payment"` // FINANCED (default) or UPFRONT
+	Products    []string    `json:"products"`
+	Currencies  []string    `json:"currencies"`
+}
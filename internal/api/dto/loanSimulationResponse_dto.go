@@ -4,5 +4,6 @@ import "github.com/Jonattas-21/loan-engine/internal/domain/entities"
 
 type LoanSimulationResponse_dto struct {
 	LoanSimulations  []entities.LoanSimulation
-	ErrorSimulations []string
+	OfferLadders     []entities.OfferLadder     `json:"offer_ladders"` // offers of the requests over several terms
+	ErrorSimulations []entities.SimulationError // declined requests with the reasons
 }
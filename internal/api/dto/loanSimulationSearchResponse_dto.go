@@ -0,0 +1,8 @@
+package dto
+
+import "github.com/Jonattas-21/loan-engine/internal/domain/entities"
+
+type LoanSimulationSearchResponse_dto struct {
+	LoanSimulations []entities.LoanSimulation `json:"loan_simulations"`
+	NextCursor      string                    `json:"next_cursor"` // cursor of the next page, empty on the last one
+}
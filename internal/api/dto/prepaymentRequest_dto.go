@@ -0,0 +1,15 @@
+package dto
+
+import (
+	"time"
+
+	"github.com/Jonattas-21/loan-engine/package/money"
+)
+
+type PrepaymentRequest_dto struct {
+	Kind          string      `json:"kind"`           // TOTAL pays off the loan, PARTIAL pays the amount
+	Amount        money.Money `json:"amount"`         // extra amount of a PARTIAL prepayment
+	AtInstallment int         `json:"at_installment"` // paid with this installment, on its due date
+	Date          time.Time   `json:"date"`           // or at this date, between the due dates
+	Recalculation string      `json:"recalculation"`  // REDUCE_INSTALLMENT (default) keeps the term, REDUCE_TERM keeps the installments
+}
@@ -0,0 +1,13 @@
+package dto
+
+import (
+	"github.com/Jonattas-21/loan-engine/package/money"
+)
+
+type RefinancingRequest_dto struct {
+	OutstandingBalance    money.Money           `json:"outstanding_balance"`    // balance of the external loan, the amount of the new one
+	CurrentInterestRate   float64               `json:"current_interest_rate"`  // annual percentage of the external loan
+	RemainingInstallments int                   `json:"remaining_installments"` // installments left of the external loan
+	CurrentInstallment    money.Money           `json:"current_installment"`    // calculated by PRICE at the current rate when not informed
+	Offer                 SimulationRequest_dto `json:"offer"`                  // client and terms of the new loan, the remaining installments when no term is informed
+}
@@ -2,12 +2,35 @@ package dto
 
 import (
 	"time"
+
+	"github.com/Jonattas-21/loan-engine/package/money"
 )
 
 type SimulationRequest_dto struct {
-	LoanAmount   float64
-	Installments int
-	BithDate     time.Time
-	Currency     string
-	Email        string
+	LoanAmount           money.Money
+	Installments         int
+	BithDate             time.Time
+	Currency             string
+	Email                string
+	AmortizationSystem   string      `json:"amortization_system"`    // PRICE (default), SAC or SACRE
+	IOFPayment           string      `json:"iof_payment"`            // FINANCED (default) or UPFRONT
+	DisbursementDate     time.Time   `json:"disbursement_date"`      // today when not informed
+	PaymentDay           int         `json:"payment_day"`            // preferred day of the month, the disbursement day when not informed
+	GracePeriod          int         `json:"grace_period"`           // length of the grace period (carência), none when zero
+	GracePeriodUnit      string      `json:"grace_period_unit"`      // MONTHS (default) or DAYS
+	GraceInterest        string      `json:"grace_interest"`         // CAPITALIZED (default) or PAID as interest only installments
+	SimulationMode       string      `json:"simulation_mode"`        // AMOUNT (default) simulates the loan amount, INSTALLMENT finds the maximum amount for the installment amount, TERM the shortest term under it
+	InstallmentAmount    money.Money `json:"installment_amount"`     // desired installment in INSTALLMENT mode, maximum installment in TERM mode
+	Segment              string      `json:"segment"`                // customer segment the pricing rules may match, like RETAIL or PRIVATE
+	Product              string      `json:"product"`                // product the fees of the catalog may match, like PERSONAL or PAYROLL
+	MonthlyIncome        money.Money `json:"monthly_income"`         // declared income, the affordability is checked when informed
+	ExistingDebtPayments money.Money `json:"existing_debt_payments"` // monthly payments of the other debts of the client
+	Terms                []int       `json:"terms"`                  // ladder of offers over these terms instead of a single simulation, a repeated term is offered once
+	MinTerm              int         `json:"min_term"`               // ladder of offers from the min to the max term by the step
+	MaxTerm              int         `json:"max_term"`
+	TermStep             int         `json:"term_step"`          // 12 when not informed
+	Insurances           []string    `json:"insurances"`         // codes of the insurance products of the catalog
+	InsurancePayment     string      `json:"insurance_payment"`  // FINANCED (default) or INSTALLMENT
+	RateIndex            string      `json:"rate_index"`         // CDI, IPCA or SELIC for a post-fixed loan, the rate of the pricing is the spread over it
+	AssumedIndexRate     float64     `json:"assumed_index_rate"` // annual index assumed for the whole term instead of the curve of the registry
 }
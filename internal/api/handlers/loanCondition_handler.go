@@ -4,11 +4,15 @@ import (
 	"encoding/json"
 	"net/http"
 
+	"github.com/go-chi/chi/v5"
+
 	"github.com/Jonattas-21/loan-engine/internal/api/dto"
+	"github.com/Jonattas-21/loan-engine/internal/api/middlewares"
 	_ "github.com/Jonattas-21/loan-engine/internal/domain/entities"
-	"github.com/Jonattas-21/loan-engine/internal/usecases"
 	"github.com/Jonattas-21/loan-engine/internal/domain/interfaces"
+	"github.com/Jonattas-21/loan-engine/internal/usecases"
 	"strings"
+	"time"
 )
 
 type LoanConditionHandler struct {
@@ -16,8 +20,10 @@ type LoanConditionHandler struct {
 	Logger                interfaces.Log
 }
 
-// @Summary update a loan condition by name
-// @Description update a loan condition by name tier1, tier2, tier3, tier4 and for now it's only possible to update the interest rate
+// @Summary create or update a loan condition by name
+// @Description create a tier or update its interest rate and ages, the ages are kept when not informed.
+// @Description Every update is a new version in force from effective_from, the previous versions are kept.
+// @Description The tiers must cover the supported ages with no overlap and no gap, an invalid change is rejected
 // @Tags conditions
 // @Accept  json
 // @Produce  json
@@ -33,7 +39,7 @@ func (h *LoanConditionHandler) SetLoanCondition(w http.ResponseWriter, r *http.R
 		return
 	}
 
-	err, validations := h.LoanCondition_usecase.SetLoanCondition(loanConditionDto)
+	err, validations := h.LoanCondition_usecase.SetLoanCondition(loanConditionDto, middlewares.UserEmail(r.Context()))
 	if err != nil {
 		h.Logger.Errorln("An internal error setting loan condition: ", err)
 		http.Error(w, err.Error(), http.StatusInternalServerError)
@@ -62,12 +68,22 @@ func (h *LoanConditionHandler) SetLoanCondition(w http.ResponseWriter, r *http.R
 // @Tags conditions
 // @Accept  json
 // @Produce  json
+// @Param date query string false "Conditions in force at the date, YYYY-MM-DD or RFC3339, now when not informed"
 // @Success 200 {array} entities.LoanCondition
 // @Router /v1/loanconditions [get]
 func (h *LoanConditionHandler) GetLoanConditions(w http.ResponseWriter, r *http.Request) {
 	w.Header().Set("Content-Type", "application/json")
 
-	conditions, err := h.LoanCondition_usecase.GetLoanConditions()
+	date, err := parseConditionDate(r.URL.Query().Get("date"))
+	if err != nil {
+		http.Error(w, "date must be a date YYYY-MM-DD or RFC3339", http.StatusBadRequest)
+		return
+	}
+	if date.IsZero() {
+		date = time.Now()
+	}
+
+	conditions, err := h.LoanCondition_usecase.GetLoanConditionsAt(date)
 	if err != nil {
 		h.Logger.Errorln("Error getting loan conditions: ", err.Error())
 		http.Error(w, err.Error(), http.StatusInternalServerError)
@@ -83,3 +99,109 @@ func (h *LoanConditionHandler) GetLoanConditions(w http.ResponseWriter, r *http.
 
 	w.WriteHeader(http.StatusOK)
 }
+
+// @Summary replace the whole set of loan conditions
+// @Description set every tier at once from effective_from, to split, merge and reshape the age bands.
+// @Description The tiers left out are closed, nothing changes when the new set leaves a gap or has an overlap
+// @Tags conditions
+// @Accept  json
+// @Produce  json
+// @Success 200 {object} string
+// @Router /v1/loanconditions [put]
+func (h *LoanConditionHandler) ReplaceLoanConditions(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+	var loanConditionsDto dto.LoanConditionsRequest_dto
+
+	if err := json.NewDecoder(r.Body).Decode(&loanConditionsDto); err != nil {
+		h.Logger.Errorln("Error decoding loan conditions: ", err.Error())
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	err, validations := h.LoanCondition_usecase.ReplaceLoanConditions(loanConditionsDto, middlewares.UserEmail(r.Context()))
+	h.writeConditionResult(w, err, validations, "Loan conditions replaced successfully")
+}
+
+// @Summary delete a loan condition by name
+// @Description close the tier from effective_from, its ages go to the merge_into tier so no age is left out.
+// @Description The previous versions are kept
+// @Tags conditions
+// @Produce  json
+// @Param name path string true "Tier name"
+// @Param merge_into query string false "Tier that takes the ages of the deleted one"
+// @Param effective_from query string false "Date of the deletion, YYYY-MM-DD or RFC3339, now when not informed, it can't be in the past"
+// @Success 200 {object} string
+// @Router /v1/loanconditions/{name} [delete]
+func (h *LoanConditionHandler) DeleteLoanCondition(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+
+	effectiveFrom, err := parseConditionDate(r.URL.Query().Get("effective_from"))
+	if err != nil {
+		http.Error(w, "effective_from must be a date YYYY-MM-DD or RFC3339", http.StatusBadRequest)
+		return
+	}
+
+	err, validations := h.LoanCondition_usecase.DeleteLoanCondition(chi.URLParam(r, "name"), r.URL.Query().Get("merge_into"), effectiveFrom, middlewares.UserEmail(r.Context()))
+	h.writeConditionResult(w, err, validations, "Loan condition deleted successfully")
+}
+
+// @Summary Show the versions of a loan condition
+// @Description Get every version of the tier, the oldest first
+// @Tags conditions
+// @Produce  json
+// @Param name path string true "Tier name"
+// @Success 200 {array} entities.LoanCondition
+// @Router /v1/loanconditions/{name}/versions [get]
+func (h *LoanConditionHandler) GetLoanConditionVersions(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+
+	name := chi.URLParam(r, "name")
+	versions, err := h.LoanCondition_usecase.GetLoanConditionVersions(name)
+	if err != nil {
+		h.Logger.Errorln("Error getting loan condition versions: ", err.Error())
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	if len(versions) == 0 {
+		http.Error(w, "loan condition "+name+" not found", http.StatusNotFound)
+		return
+	}
+
+	err = json.NewEncoder(w).Encode(versions)
+	if err != nil {
+		h.Logger.Errorln("Error encoding loan condition versions: ", err.Error())
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+}
+
+// writeConditionResult answers a change of the conditions, the validations are a bad request
+func (h *LoanConditionHandler) writeConditionResult(w http.ResponseWriter, err error, validations []string, message string) {
+	if err != nil {
+		h.Logger.Errorln("An internal error changing loan conditions: ", err)
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	if validations != nil {
+		http.Error(w, strings.Join(validations, ", "), http.StatusBadRequest)
+		return
+	}
+
+	err = json.NewEncoder(w).Encode(message)
+	if err != nil {
+		h.Logger.Errorln("Error encoding loan condition: ", err.Error())
+	}
+}
+
+// parseConditionDate reads a date YYYY-MM-DD or RFC3339, the zero time when empty
+func parseConditionDate(value string) (time.Time, error) {
+	if value == "" {
+		return time.Time{}, nil
+	}
+	date, err := time.Parse(time.RFC3339, value)
+	if err != nil {
+		date, err = time.Parse("2006-01-02", value)
+	}
+	return date, err
+}
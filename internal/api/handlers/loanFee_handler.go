@@ -0,0 +1,109 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strings"
+
+	"github.com/go-chi/chi/v5"
+
+	"github.com/Jonattas-21/loan-engine/internal/api/dto"
+	"github.com/Jonattas-21/loan-engine/internal/api/middlewares"
+	"github.com/Jonattas-21/loan-engine/internal/domain/interfaces"
+	"github.com/Jonattas-21/loan-engine/internal/usecases"
+)
+
+type LoanFeeHandler struct {
+	LoanFee_usecase usecases.LoanFee
+	Logger          interfaces.Log
+}
+
+// @Summary create or update a fee of the catalog by name
+// @Description set an origination (TAC), registration or admin fee, flat or a percentage of the loan amount,
+// @Description financed or paid upfront. Admin fees are flat and paid with each installment.
+// @Description The fees apply to the simulations of their products and currencies, any of them when not informed
+// @Tags fees
+// @Accept  json
+// @Produce  json
+// @Param fee body dto.LoanFeeRequest_dto true "Fee"
+// @Success 200 {object} string
+// @Router /v1/loanfees [post]
+func (h *LoanFeeHandler) SetLoanFee(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+	var loanFeeDto dto.LoanFeeRequest_dto
+
+	if err := json.NewDecoder(r.Body).Decode(&loanFeeDto); err != nil {
+		h.Logger.Errorln("Error decoding loan fee: ", err.Error())
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	err, validations := h.LoanFee_usecase.SetLoanFee(loanFeeDto, middlewares.UserEmail(r.Context()))
+	if err != nil {
+		h.Logger.Errorln("An internal error setting loan fee: ", err)
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	if validations != nil {
+		http.Error(w, strings.Join(validations, ", "), http.StatusBadRequest)
+		return
+	}
+
+	err = json.NewEncoder(w).Encode("Loan fee set successfully")
+	if err != nil {
+		h.Logger.Errorln("Error encoding loan fee: ", err.Error())
+	}
+}
+
+// @Summary Show the fee catalog
+// @Description Get all the fees charged to the simulations
+// @Tags fees
+// @Produce  json
+// @Success 200 {array} github_com_Jonattas-21_loan-engine_internal_domain_entities.LoanFee
+// @Router /v1/loanfees [get]
+func (h *LoanFeeHandler) GetLoanFees(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+
+	loanFees, err := h.LoanFee_usecase.GetLoanFees()
+	if err != nil {
+		h.Logger.Errorln("Error getting loan fees: ", err.Error())
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	err = json.NewEncoder(w).Encode(loanFees)
+	if err != nil {
+		h.Logger.Errorln("Error encoding loan fees: ", err.Error())
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+}
+
+// @Summary delete a fee of the catalog by name
+// @Description the fee stops being charged, the saved simulations keep it
+// @Tags fees
+// @Produce  json
+// @Param name path string true "Fee name"
+// @Success 200 {object} string
+// @Failure 404 {string} string "loan fee not found"
+// @Router /v1/loanfees/{name} [delete]
+func (h *LoanFeeHandler) DeleteLoanFee(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+
+	err := h.LoanFee_usecase.DeleteLoanFee(chi.URLParam(r, "name"))
+	if errors.Is(err, usecases.ErrLoanFeeNotFound) {
+		http.Error(w, err.Error(), http.StatusNotFound)
+		return
+	}
+	if err != nil {
+		h.Logger.Errorln("An internal error deleting loan fee: ", err)
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	err = json.NewEncoder(w).Encode("Loan fee deleted successfully")
+	if err != nil {
+		h.Logger.Errorln("Error encoding loan fee: ", err.Error())
+	}
+}
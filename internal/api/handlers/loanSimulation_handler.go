@@ -2,12 +2,22 @@ package handlers
 
 import (
 	"encoding/json"
+	"errors"
+	"fmt"
 	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
 
 	"github.com/Jonattas-21/loan-engine/internal/api/dto"
-	_ "github.com/Jonattas-21/loan-engine/internal/domain/entities"
+	"github.com/Jonattas-21/loan-engine/internal/api/middlewares"
+	"github.com/Jonattas-21/loan-engine/internal/domain/entities"
 	"github.com/Jonattas-21/loan-engine/internal/domain/interfaces"
 	"github.com/Jonattas-21/loan-engine/internal/usecases"
+	"github.com/Jonattas-21/loan-engine/package/money"
+	"github.com/Jonattas-21/loan-engine/package/pagination"
+	"github.com/go-chi/chi/v5"
 )
 
 type LoanSimulationHandler struct {
@@ -17,10 +27,12 @@ type LoanSimulationHandler struct {
 
 // @Summary  Get a plenty of loan simulations
 // @Description Get a plenty of loan simulations
+// @Description A request with terms or a term range gets a ladder of offers in offer_ladders, with the recommended one flagged
 // @Tags simulation
 // @Accept  json
 // @Produce  json
 // @Success 200 {object} dto.LoanSimulationResponse_dto
+// @Failure 403 {string} string "simulations can only be requested for the authenticated email"
 // @Router /v1/loansimulations [post]
 func (h *LoanSimulationHandler) GetLoanSimulation(w http.ResponseWriter, r *http.Request) {
 	w.Header().Set("Content-Type", "application/json")
@@ -34,11 +46,17 @@ func (h *LoanSimulationHandler) GetLoanSimulation(w http.ResponseWriter, r *http
 		return
 	}
 
+	if err := h.LoanSimulation_usecase.SimulationOwner(loanSimulationDto, middlewares.UserEmail(r.Context())); err != nil {
+		http.Error(w, err.Error(), http.StatusForbidden)
+		return
+	}
+
 	h.Logger.Infoln("Calculating loan simulation: ", loanSimulationDto)
-	responseSimulation, errs := h.LoanSimulation_usecase.GetLoanSimulation(loanSimulationDto)
+	responseSimulation, offerLadders, errs := h.LoanSimulation_usecase.GetLoanSimulation(loanSimulationDto)
 
 	reponse := dto.LoanSimulationResponse_dto{
-		LoanSimulations: responseSimulation,
+		LoanSimulations:  responseSimulation,
+		OfferLadders:     offerLadders,
 		ErrorSimulations: errs,
 	}
 
@@ -51,3 +69,294 @@ func (h *LoanSimulationHandler) GetLoanSimulation(w http.ResponseWriter, r *http
 
 	w.WriteHeader(http.StatusOK)
 }
+
+// @Summary  Get a loan simulation by id
+// @Description Get a saved loan simulation, only the user who requested it can see it
+// @Tags simulation
+// @Produce  json
+// @Param id path string true "Simulation id"
+// @Success 200 {object} entities.LoanSimulation
+// @Failure 404 {string} string "loan simulation not found"
+// @Router /v1/loansimulations/{id} [get]
+func (h *LoanSimulationHandler) GetLoanSimulationByID(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+
+	id := chi.URLParam(r, "id")
+	h.Logger.Infoln("Received request to get loan simulation: ", id)
+
+	loanSimulation, err := h.LoanSimulation_usecase.GetLoanSimulationByID(id, middlewares.UserEmail(r.Context()))
+	if errors.Is(err, usecases.ErrSimulationNotFound) {
+		http.Error(w, err.Error(), http.StatusNotFound)
+		return
+	}
+	if err != nil {
+		h.Logger.Errorln("Error getting loan simulation: ", err.Error())
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	err = json.NewEncoder(w).Encode(loanSimulation)
+	if err != nil {
+		h.Logger.Errorln("Error encoding loan simulation: ", err.Error())
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+}
+
+// @Summary  Simulate a prepayment of a loan simulation
+// @Description Pay a saved simulation early, in total or in part, at an installment or at a date between the due dates
+// @Description The payoff discounts the interest of the future installments, a partial prepayment recalculates the rest
+// @Description of the schedule reducing the installments (default) or the term
+// @Tags simulation
+// @Accept  json
+// @Produce  json
+// @Param id path string true "Simulation id"
+// @Param prepayment body dto.PrepaymentRequest_dto true "Prepayment event"
+// @Success 200 {object} entities.PrepaymentSimulation
+// @Failure 400 {string} string "invalid prepayment"
+// @Failure 404 {string} string "loan simulation not found"
+// @Router /v1/loansimulations/{id}/prepayments [post]
+func (h *LoanSimulationHandler) PrepayLoanSimulation(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+
+	id := chi.URLParam(r, "id")
+	h.Logger.Infoln("Received request to prepay loan simulation: ", id)
+
+	var prepaymentDto dto.PrepaymentRequest_dto
+	if err := json.NewDecoder(r.Body).Decode(&prepaymentDto); err != nil {
+		h.Logger.Errorln("Error decoding prepayment: ", err.Error())
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	if validations := h.LoanSimulation_usecase.ValidatePrepaymentRequest(prepaymentDto); validations != nil {
+		http.Error(w, strings.Join(validations, ", "), http.StatusBadRequest)
+		return
+	}
+
+	prepayment, validations, err := h.LoanSimulation_usecase.SimulatePrepayment(id, middlewares.UserEmail(r.Context()), prepaymentDto)
+	if errors.Is(err, usecases.ErrSimulationNotFound) {
+		http.Error(w, err.Error(), http.StatusNotFound)
+		return
+	}
+	if err != nil {
+		h.Logger.Errorln("Error simulating prepayment: ", err.Error())
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	if validations != nil {
+		http.Error(w, strings.Join(validations, ", "), http.StatusBadRequest)
+		return
+	}
+
+	err = json.NewEncoder(w).Encode(prepayment)
+	if err != nil {
+		h.Logger.Errorln("Error encoding prepayment: ", err.Error())
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+}
+
+// @Summary  Compare an external loan with a refinancing offer
+// @Description Simulate the offer paying off the outstanding balance of an external loan (portability) at our rate,
+// @Description and compare the installments, the total paid and the break-even month. The offer is saved as a simulation
+// @Tags simulation
+// @Accept  json
+// @Produce  json
+// @Param refinancing body dto.RefinancingRequest_dto true "External loan and the request of the offer"
+// @Success 200 {object} entities.RefinancingComparison
+// @Failure 400 {string} string "invalid request"
+// @Failure 403 {string} string "simulations can only be requested for the authenticated email"
+// @Failure 422 {object} entities.SimulationError "offer declined"
+// @Router /v1/loansimulations/refinancing [post]
+func (h *LoanSimulationHandler) CompareRefinancing(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+
+	var refinancingDto dto.RefinancingRequest_dto
+	if err := json.NewDecoder(r.Body).Decode(&refinancingDto); err != nil {
+		h.Logger.Errorln("Error decoding refinancing: ", err.Error())
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	offers := []dto.SimulationRequest_dto{refinancingDto.Offer}
+	if err := h.LoanSimulation_usecase.SimulationOwner(offers, middlewares.UserEmail(r.Context())); err != nil {
+		http.Error(w, err.Error(), http.StatusForbidden)
+		return
+	}
+	refinancingDto.Offer = offers[0]
+
+	if validations := h.LoanSimulation_usecase.ValidateRefinancingRequest(refinancingDto); validations != nil {
+		http.Error(w, strings.Join(validations, ", "), http.StatusBadRequest)
+		return
+	}
+
+	comparison, err := h.LoanSimulation_usecase.CompareRefinancing(refinancingDto)
+	var declined *usecases.DeclinedError
+	if errors.As(err, &declined) {
+		w.WriteHeader(http.StatusUnprocessableEntity)
+		json.NewEncoder(w).Encode(h.LoanSimulation_usecase.SimulationError(0, refinancingDto.Offer.Email, err))
+		return
+	}
+	if err != nil {
+		h.Logger.Errorln("Error comparing refinancing: ", err.Error())
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	err = json.NewEncoder(w).Encode(comparison)
+	if err != nil {
+		h.Logger.Errorln("Error encoding refinancing: ", err.Error())
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+}
+
+// @Summary  Get a plenty of loan simulations, deprecated
+// @Description The first verb of the simulation, kept for the current clients: use POST /v1/loansimulations
+// @Tags simulation
+// @Accept  json
+// @Produce  json
+// @Success 200 {object} dto.LoanSimulationResponse_dto
+// @Failure 403 {string} string "simulations can only be requested for the authenticated email"
+// @Deprecated
+// @Router /v1/loansimulations [get]
+func (h *LoanSimulationHandler) GetLoanSimulationDeprecated(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Deprecation", "true")
+	w.Header().Set("Link", `</api/v1/loansimulations>; rel="successor-version"`)
+	h.GetLoanSimulation(w, r)
+}
+
+// @Summary  Search the saved loan simulations
+// @Description Search the saved simulations with filters and cursor pagination, an authenticated user only sees the own simulations
+// @Tags simulation
+// @Produce  json
+// @Param email query string false "Email of the simulations"
+// @Param date_from query string false "Simulation date from, YYYY-MM-DD or RFC3339"
+// @Param date_to query string false "Simulation date to, YYYY-MM-DD or RFC3339"
+// @Param currency query string false "Currency, R$ or U$"
+// @Param min_amount query string false "Minimum loan amount"
+// @Param max_amount query string false "Maximum loan amount"
+// @Param min_installments query int false "Minimum number of installments"
+// @Param max_installments query int false "Maximum number of installments"
+// @Param sort query string false "simulation_date (default), loan_amount or total_installments"
+// @Param order query string false "asc or desc, desc is the default for simulation_date"
+// @Param cursor query string false "next_cursor of the previous page"
+// @Param limit query int false "Page size, 20 by default and up to 100"
+// @Success 200 {object} dto.LoanSimulationSearchResponse_dto
+// @Failure 400 {string} string "invalid filters, or a cursor of another search"
+// @Router /v1/loansimulations/search [get]
+func (h *LoanSimulationHandler) SearchLoanSimulations(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+
+	query, validations := parseSimulationQuery(r.URL.Query())
+	if validations == nil {
+		validations = h.LoanSimulation_usecase.ValidateSearchQuery(query)
+	}
+	if validations != nil {
+		http.Error(w, strings.Join(validations, ", "), http.StatusBadRequest)
+		return
+	}
+
+	page, err := h.LoanSimulation_usecase.SearchLoanSimulations(query, middlewares.UserEmail(r.Context()))
+	if errors.Is(err, pagination.ErrInvalidCursor) {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	if err != nil {
+		h.Logger.Errorln("Error searching loan simulations: ", err.Error())
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	response := dto.LoanSimulationSearchResponse_dto{
+		LoanSimulations: page.LoanSimulations,
+		NextCursor:      page.NextCursor,
+	}
+	if response.LoanSimulations == nil {
+		response.LoanSimulations = []entities.LoanSimulation{}
+	}
+
+	err = json.NewEncoder(w).Encode(response)
+	if err != nil {
+		h.Logger.Errorln("Error encoding loan simulations: ", err.Error())
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+}
+
+// parseSimulationQuery reads the search filters of the query string, the messages list the invalid ones
+func parseSimulationQuery(values url.Values) (interfaces.LoanSimulationQuery, []string) {
+	var validations []string
+	query := interfaces.LoanSimulationQuery{
+		Email:    values.Get("email"),
+		Currency: values.Get("currency"),
+		SortBy:   values.Get("sort"),
+		Cursor:   values.Get("cursor"),
+	}
+
+	parseDate := func(name string, endOfDay bool) time.Time {
+		value := values.Get(name)
+		if value == "" {
+			return time.Time{}
+		}
+		if date, err := time.Parse(time.RFC3339, value); err == nil {
+			return date
+		}
+		date, err := time.Parse("2006-01-02", value)
+		if err != nil {
+			validations = append(validations, fmt.Sprintf("%v must be a date YYYY-MM-DD or RFC3339", name))
+			return time.Time{}
+		}
+		if endOfDay {
+			// the whole day is included
+			return date.AddDate(0, 0, 1).Add(-time.Nanosecond)
+		}
+		return date
+	}
+
+	parseAmount := func(name string) money.Money {
+		value := values.Get(name)
+		if value == "" {
+			return money.Zero()
+		}
+		amount, err := money.Parse(value)
+		if err != nil {
+			validations = append(validations, fmt.Sprintf("%v must be an amount", name))
+		}
+		return amount
+	}
+
+	parseInt := func(name string) int {
+		value := values.Get(name)
+		if value == "" {
+			return 0
+		}
+		number, err := strconv.Atoi(value)
+		if err != nil {
+			validations = append(validations, fmt.Sprintf("%v must be a number", name))
+		}
+		return number
+	}
+
+	query.SimulationDateFrom = parseDate("date_from", false)
+	query.SimulationDateTo = parseDate("date_to", true)
+	query.MinLoanAmount = parseAmount("min_amount")
+	query.MaxLoanAmount = parseAmount("max_amount")
+	query.MinInstallments = parseInt("min_installments")
+	query.MaxInstallments = parseInt("max_installments")
+	query.Limit = parseInt("limit")
+
+	switch strings.ToLower(values.Get("order")) {
+	case "":
+		query.Descending = query.SortBy == "" || query.SortBy == interfaces.SortBySimulationDate
+	case "asc":
+		query.Descending = false
+	case "desc":
+		query.Descending = true
+	default:
+		validations = append(validations, "order must be asc or desc")
+	}
+
+	return query, validations
+}
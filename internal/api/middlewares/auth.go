@@ -10,9 +10,17 @@ import (
 
 type ValidationFunc func(token string, ctx context.Context) (string, error)
 type contextKey string
+
 var ValidateToken ValidationFunc = auth.ValidationToken
+
 const userKey contextKey = "user"
 
+// UserEmail is the email of the authenticated user, empty when the route is not protected
+func UserEmail(ctx context.Context) string {
+	email, _ := ctx.Value(userKey).(string)
+	return email
+}
+
 func Auth(next http.Handler) http.Handler {
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 
@@ -0,0 +1,42 @@
+package entities
+
+import (
+	"github.com/Jonattas-21/loan-engine/package/money"
+)
+
+// Codes of the reasons a simulation request is declined
+const (
+	DeclineInvalidRequest          = "INVALID_REQUEST"
+	DeclineNoPricingRule           = "NO_PRICING_RULE"
+	DeclineAmountBelowMinimum      = "AMOUNT_BELOW_MINIMUM"
+	DeclineAmountAboveMaximum      = "AMOUNT_ABOVE_MAXIMUM"
+	DeclineAgeAtMaturityAboveLimit = "AGE_AT_MATURITY_ABOVE_MAXIMUM"
+	DeclineInstallmentBelowMinimum = "INSTALLMENT_BELOW_MINIMUM"
+	DeclineDebtToIncomeAboveLimit  = "DEBT_TO_INCOME_ABOVE_MAXIMUM"
+	DeclineSimulationError         = "SIMULATION_ERROR" // not a policy, the simulation failed
+)
+
+// EligibilityPolicy limits the loans of a currency, a policy without currency applies to every loan.
+// A limit left empty is not checked.
+type EligibilityPolicy struct {
+	Currency             string      `json:"currency"`
+	MinLoanAmount        money.Money `json:"min_loan_amount"`
+	MaxLoanAmount        money.Money `json:"max_loan_amount"`
+	MaxAgeAtMaturity     int         `json:"max_age_at_maturity"` // age of the client at the last installment
+	MinInstallmentAmount money.Money `json:"min_installment_amount"`
+}
+
+// DeclineReason tells why a simulation request was declined, the limit of the policy and the value of the request when there are
+type DeclineReason struct {
+	Code    string `json:"code"`
+	Message string `json:"message"`
+	Limit   string `json:"limit,omitempty"`
+	Value   string `json:"value,omitempty"`
+}
+
+// SimulationError is a declined simulation request, by its position in the requests
+type SimulationError struct {
+	RequestIndex int             `json:"request_index"`
+	Email        string          `json:"email"`
+	Reasons      []DeclineReason `json:"reasons"`
+}
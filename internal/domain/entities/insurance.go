@@ -0,0 +1,32 @@
+package entities
+
+import (
+	"github.com/Jonattas-21/loan-engine/package/money"
+)
+
+// How the premium of an insurance product is priced: a percentage of the balance or a fixed premium, both monthly
+const (
+	InsurancePremiumBalance = "BALANCE"
+	InsurancePremiumMonthly = "MONTHLY"
+)
+
+// InsuranceProduct is an optional insurance of the catalog the simulations may add, like credit life (prestamista)
+type InsuranceProduct struct {
+	Code           string      `json:"code"`
+	Name           string      `json:"name"`
+	PremiumType    string      `json:"premium_type"`    // BALANCE or MONTHLY
+	Rate           float64     `json:"rate"`            // monthly percentage of the balance of a BALANCE premium
+	MonthlyPremium money.Money `json:"monthly_premium"` // premium of each month of a MONTHLY premium
+	Currency       string      `json:"currency"`        // offered in every currency when empty
+}
+
+// InsuranceCharge is an insurance product added to a simulation, financed or paid with each installment
+type InsuranceCharge struct {
+	Code           string      `json:"code"`
+	Name           string      `json:"name"`
+	PremiumType    string      `json:"premium_type"`
+	Rate           float64     `json:"rate"`
+	MonthlyPremium money.Money `json:"monthly_premium"`
+	Payment        string      `json:"payment"`        // FINANCED or INSTALLMENT
+	PremiumAmount  money.Money `json:"premium_amount"` // premium of the whole loan
+}
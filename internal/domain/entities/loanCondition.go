@@ -4,10 +4,22 @@ import (
 	"time"
 )
 
+// LoanCondition is one version of an age tier, a change creates a new version and closes the previous one.
+// The version is in force from EffectiveFrom until EffectiveTo, the current one has no EffectiveTo.
 type LoanCondition struct {
-	Name         string    `json:"name"`
-	InterestRate float64   `json:"interest_rate"`
-	MinAge       int       `json:"min_age"`
-	MaxAge       int       `json:"max_age"`
-	ModifiedDate time.Time `json:"modified_date"`
+	ID            string     `json:"id"`
+	Name          string     `json:"name"`
+	Version       int        `json:"version"`
+	InterestRate  float64    `json:"interest_rate"`
+	MinAge        int        `json:"min_age"`
+	MaxAge        int        `json:"max_age"`
+	EffectiveFrom time.Time  `json:"effective_from"`
+	EffectiveTo   *time.Time `json:"effective_to"`
+	Author        string     `json:"author"`
+	ModifiedDate  time.Time  `json:"modified_date"`
+}
+
+// InForce tells if the version applies at the date
+func (c LoanCondition) InForce(date time.Time) bool {
+	return !date.Before(c.EffectiveFrom) && (c.EffectiveTo == nil || date.Before(*c.EffectiveTo))
 }
@@ -0,0 +1,48 @@
+package entities
+
+import (
+	"time"
+
+	"github.com/Jonattas-21/loan-engine/package/money"
+)
+
+// Kind of a fee of the catalog: charged at the origination, for the registration of the contract or with each installment
+const (
+	FeeOrigination  = "ORIGINATION" // TAC
+	FeeRegistration = "REGISTRATION"
+	FeeAdmin        = "ADMIN"
+
+	FeeFlat       = "FLAT"
+	FeePercentage = "PERCENTAGE"
+)
+
+// Kind of the other charges of a simulation listed with the fees
+const (
+	ChargeInterest  = "INTEREST"
+	ChargeIOF       = "IOF"
+	ChargeInsurance = "INSURANCE"
+)
+
+// LoanFee is a fee of the catalog, charged to the simulations of its products and currencies.
+// A criterion left empty matches any simulation.
+type LoanFee struct {
+	Name         string      `json:"name"`
+	Kind         string      `json:"kind"`        // ORIGINATION, REGISTRATION or ADMIN
+	Calculation  string      `json:"calculation"` // FLAT amount or PERCENTAGE of the loan amount, ADMIN fees are flat by installment
+	Amount       money.Money `json:"amount"`
+	Rate         float64     `json:"rate"`    // percentage of the loan amount
+	Payment      string      `json:"payment"` // FINANCED or UPFRONT, ADMIN fees are paid with the installments
+	Products     []string    `json:"products"`
+	Currencies   []string    `json:"currencies"`
+	Author       string      `json:"author"`
+	ModifiedDate time.Time   `json:"modified_date"`
+}
+
+// FeeCharge is one item of what a simulation charges above the loan amount: the interest, the IOF,
+// the insurances and the fees of the catalog
+type FeeCharge struct {
+	Name    string      `json:"name"`
+	Kind    string      `json:"kind"`
+	Payment string      `json:"payment"` // FINANCED, UPFRONT or INSTALLMENT
+	Amount  money.Money `json:"amount"`
+}
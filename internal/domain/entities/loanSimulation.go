@@ -2,23 +2,90 @@ package entities
 
 import (
 	"time"
+
+	"github.com/Jonattas-21/loan-engine/package/money"
+)
+
+// How a charge, like a tax, is paid: added to the principal or at the disbursement
+const (
+	PaymentFinanced    = "FINANCED"
+	PaymentUpfront     = "UPFRONT"
+	PaymentInstallment = "INSTALLMENT" // with each installment, like an insurance premium
+)
+
+// Kind of a schedule line: a grace period line or an installment amortizing the principal
+const (
+	InstallmentGrace        = "GRACE"
+	InstallmentAmortization = "AMORTIZATION"
 )
 
 type LoanSimulation struct {
-	LoanAmount          float64       `json:"loan_amount"`
-	AmountTobePaid      float64       `json:"amount_to_be_paid"`
-	AmountFeeTobePaid   float64       `json:"amount_fee_to_be_paid"`
-	FeeAmountPercentage float64       `json:"fee_amount_percentage"`
-	TotalInstallments   int           `json:"total_installments"`
-	SimulationDate      time.Time     `json:"simulation_date"`
-	Currency            string        `json:"currency"`
-	Installments        []Installment `json:"installments"`
-	Email               string        `json:"email"`
+	ID                   string            `json:"id"` // ULID, sorts by the simulation date
+	LoanAmount           money.Money       `json:"loan_amount"`
+	FinancedAmount       money.Money       `json:"financed_amount"` // loan amount plus the financed charges
+	UpfrontAmount        money.Money       `json:"upfront_amount"`  // charges paid at the disbursement
+	AmountTobePaid       money.Money       `json:"amount_to_be_paid"`
+	AmountFeeTobePaid    money.Money       `json:"amount_fee_to_be_paid"`
+	IOFAmount            money.Money       `json:"iof_amount"`
+	IOFPayment           string            `json:"iof_payment"`
+	InsuranceAmount      money.Money       `json:"insurance_amount"` // premiums of the insurances, financed or with the installments
+	Insurances           []InsuranceCharge `json:"insurances"`
+	FeesAmount           money.Money       `json:"fees_amount"`           // fees of the catalog, financed, upfront or with the installments
+	Fees                 []FeeCharge       `json:"fees"`                  // every charge of the loan itemized, the interest, IOF and insurances included
+	FeeAmountPercentage  float64           `json:"fee_amount_percentage"` // annual interest rate, of the first period for a post-fixed loan
+	RateIndex            string            `json:"rate_index"`            // index of a post-fixed loan, empty for a fixed rate
+	IndexSpread          float64           `json:"index_spread"`          // annual percentage points over the index
+	Estimated            bool              `json:"estimated"`             // the amounts are projected over the index curve
+	LoanConditionID      string            `json:"loan_condition_id"`     // version of the age tier of the client
+	LoanConditionName    string            `json:"loan_condition_name"`
+	LoanConditionVersion int               `json:"loan_condition_version"`
+	PricingRuleID        string            `json:"pricing_rule_id"` // rule the rate came from, the age tier or a policy rule
+	PricingRuleName      string            `json:"pricing_rule_name"`
+	PricingRuleKind      string            `json:"pricing_rule_kind"` // AGE_TIER or POLICY
+	Segment              string            `json:"segment"`
+	Product              string            `json:"product"`
+	CreditScore          int               `json:"credit_score"`
+	CreditScoreBand      string            `json:"credit_score_band"`
+	CreditScoreSpread    float64           `json:"credit_score_spread"` // annual percentage points added to the rate of the pricing rule
+	MonthlyIncome        money.Money       `json:"monthly_income"`
+	ExistingDebtPayments money.Money       `json:"existing_debt_payments"`
+	DebtToIncomeRatio    float64           `json:"debt_to_income_ratio"` // percentage of the income taken by the highest installment and the other debts
+	RemainingMargin      money.Money       `json:"remaining_margin"`     // income under the limit still free after the loan, negative above it
+	AffordabilityFlag    bool              `json:"affordability_flag"`   // the ratio is above the limit
+	AmortizationSystem   string            `json:"amortization_system"`
+	EffectiveMonthlyRate float64           `json:"effective_monthly_rate"` // CET, percentage including every cost of the loan
+	EffectiveAnnualRate  float64           `json:"effective_annual_rate"`
+	TotalInstallments    int               `json:"total_installments"`
+	SimulationDate       time.Time         `json:"simulation_date"`
+	DisbursementDate     time.Time         `json:"disbursement_date"`
+	PaymentDay           int               `json:"payment_day"`
+	GracePeriod          int               `json:"grace_period"`
+	GracePeriodUnit      string            `json:"grace_period_unit"`
+	GraceInterest        string            `json:"grace_interest"`
+	SimulationMode       string            `json:"simulation_mode"`
+	RequestedInstallment money.Money       `json:"requested_installment"` // desired installment the loan amount was solved for
+	OfferLadderID        string            `json:"offer_ladder_id"`       // offers of the same request share it
+	Recommended          bool              `json:"recommended"`           // recommended offer of its ladder
+	Currency             string            `json:"currency"`
+	Installments         []Installment     `json:"installments"`
+	Email                string            `json:"email"`
 }
 
+// Installment is one line of the amortization schedule, InstallmentFeeAmount is the interest portion.
+// The installment amount is the principal plus the interest plus the insurance premiums and admin fees paid with it
 type Installment struct {
-	InstallmentNumber    int     `json:"installment_number"`
-	InstallmentAmount    float64 `json:"installment_amount"`
-	InstallmentFeeAmount float64 `json:"installment_fee_amount"`
-	Currency             string  `json:"currency"`
+	InstallmentNumber    int         `json:"installment_number"`
+	Kind                 string      `json:"kind"`
+	DueDate              time.Time   `json:"due_date"`
+	InstallmentAmount    money.Money `json:"installment_amount"`
+	InstallmentFeeAmount money.Money `json:"installment_fee_amount"`
+	PrincipalAmount      money.Money `json:"principal_amount"`
+	OpeningBalance       money.Money `json:"opening_balance"`
+	ClosingBalance       money.Money `json:"closing_balance"`
+	CapitalizedInterest  money.Money `json:"capitalized_interest"` // grace interest added to the balance instead of paid
+	InsuranceAmount      money.Money `json:"insurance_amount"`     // premiums paid with the installment
+	AdminFeeAmount       money.Money `json:"admin_fee_amount"`     // admin fees paid with the installment
+	IndexRate            float64     `json:"index_rate"`           // annual index of the period of a post-fixed loan
+	Estimated            bool        `json:"estimated"`            // the index of the period is projected, not published
+	Currency             string      `json:"currency"`
 }
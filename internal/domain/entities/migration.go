@@ -0,0 +1,10 @@
+package entities
+
+import "time"
+
+// Migration records a data migration applied to the database
+type Migration struct {
+	Version   int       `json:"version"`
+	Name      string    `json:"name"`
+	AppliedAt time.Time `json:"applied_at"`
+}
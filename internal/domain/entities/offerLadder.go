@@ -0,0 +1,18 @@
+package entities
+
+// OfferLadder groups the offers of a request over several terms, one of them may be recommended
+type OfferLadder struct {
+	ID                 string           `json:"id"`
+	RequestIndex       int              `json:"request_index"`
+	Email              string           `json:"email"`
+	RecommendationRule string           `json:"recommendation_rule"`
+	RecommendedID      string           `json:"recommended_id"` // empty when no offer meets the rule
+	Offers             []LoanSimulation `json:"offers"`
+	DeclinedOffers     []DeclinedOffer  `json:"declined_offers"`
+}
+
+// DeclinedOffer is a term of the ladder that was not offered, with the reasons
+type DeclinedOffer struct {
+	Installments int             `json:"installments"`
+	Reasons      []DeclineReason `json:"reasons"`
+}
@@ -0,0 +1,35 @@
+package entities
+
+import (
+	"time"
+
+	"github.com/Jonattas-21/loan-engine/package/money"
+)
+
+// Kind of a prepayment and how a partial one recalculates the schedule
+const (
+	PrepaymentTotal   = "TOTAL"
+	PrepaymentPartial = "PARTIAL"
+
+	RecalculationReduceInstallment = "REDUCE_INSTALLMENT"
+	RecalculationReduceTerm        = "REDUCE_TERM"
+)
+
+// PrepaymentSimulation is an early payment of a saved simulation. The payoff is the balance plus the interest
+// accrued since the last due date, the interest of the future installments is discounted.
+type PrepaymentSimulation struct {
+	LoanSimulationID   string        `json:"loan_simulation_id"`
+	Kind               string        `json:"kind"`
+	Recalculation      string        `json:"recalculation"`
+	PrepaymentDate     time.Time     `json:"prepayment_date"`
+	InstallmentsPaid   int           `json:"installments_paid"` // installments due until the prepayment
+	OutstandingBalance money.Money   `json:"outstanding_balance"`
+	AccruedInterest    money.Money   `json:"accrued_interest"`
+	PayoffAmount       money.Money   `json:"payoff_amount"`
+	DiscountedInterest money.Money   `json:"discounted_interest"` // interest of the remaining installments not paid anymore
+	PrepaymentAmount   money.Money   `json:"prepayment_amount"`
+	RemainingBalance   money.Money   `json:"remaining_balance"`
+	TotalInstallments  int           `json:"total_installments"` // installments left after the prepayment
+	Installments       []Installment `json:"installments"`       // recalculated schedule of a partial prepayment
+	Currency           string        `json:"currency"`
+}
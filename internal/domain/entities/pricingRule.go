@@ -0,0 +1,29 @@
+package entities
+
+import (
+	"github.com/Jonattas-21/loan-engine/package/money"
+)
+
+// Kind of a pricing rule: an age tier of the loan conditions, or a policy rule matching on several criteria
+const (
+	PricingRuleAgeTier = "AGE_TIER"
+	PricingRulePolicy  = "POLICY"
+)
+
+// PricingRule sets the interest rate of the loans meeting all of its criteria, a criterion left empty matches any loan.
+// The matching rule of the highest priority prices the loan, the age tiers have priority 0.
+type PricingRule struct {
+	ID              string      `json:"id"`
+	Name            string      `json:"name"`
+	Kind            string      `json:"kind"`
+	Priority        int         `json:"priority"`
+	InterestRate    float64     `json:"interest_rate"` // annual percentage
+	MinAge          int         `json:"min_age"`
+	MaxAge          int         `json:"max_age"`
+	MinLoanAmount   money.Money `json:"min_loan_amount"`
+	MaxLoanAmount   money.Money `json:"max_loan_amount"`
+	MinInstallments int         `json:"min_installments"`
+	MaxInstallments int         `json:"max_installments"`
+	Currencies      []string    `json:"currencies"`
+	Segments        []string    `json:"segments"` // customer segments, like RETAIL or PRIVATE
+}
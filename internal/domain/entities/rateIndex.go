@@ -0,0 +1,17 @@
+package entities
+
+import "time"
+
+// Indexes a post-fixed loan may be quoted over, plus the spread of its pricing
+const (
+	RateIndexCDI   = "CDI"
+	RateIndexIPCA  = "IPCA"
+	RateIndexSELIC = "SELIC"
+)
+
+// IndexValue is the annual rate of an index in a month, published or projected
+type IndexValue struct {
+	Month     time.Time `json:"month"`     // first day of the month
+	Rate      float64   `json:"rate"`      // annual percentage
+	Projected bool      `json:"projected"` // expected value of the curve, not published yet
+}
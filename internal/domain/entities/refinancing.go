@@ -0,0 +1,20 @@
+package entities
+
+import (
+	"github.com/Jonattas-21/loan-engine/package/money"
+)
+
+// RefinancingComparison compares the installments left of an external loan with the offer paying off its balance
+type RefinancingComparison struct {
+	OutstandingBalance    money.Money    `json:"outstanding_balance"`
+	CurrentInterestRate   float64        `json:"current_interest_rate"`
+	RemainingInstallments int            `json:"remaining_installments"`
+	CurrentInstallment    money.Money    `json:"current_installment"`
+	CurrentTotal          money.Money    `json:"current_total"`       // installments left of the external loan
+	NewInstallment        money.Money    `json:"new_installment"`     // highest installment of the offer
+	NewTotal              money.Money    `json:"new_total"`           // installments of the offer plus the charges paid upfront
+	InstallmentSavings    money.Money    `json:"installment_savings"` // by month, negative when the new installment is higher
+	TotalSavings          money.Money    `json:"total_savings"`
+	BreakEvenInstallment  int            `json:"break_even_installment"` // from this month on the offer has cost less, 0 when it never does
+	Offer                 LoanSimulation `json:"offer"`
+}
@@ -0,0 +1,9 @@
+package entities
+
+// ScoreBand adds a spread to the interest rate of the clients with a credit score in the band
+type ScoreBand struct {
+	Name     string  `json:"name"`
+	MinScore int     `json:"min_score"`
+	MaxScore int     `json:"max_score"`
+	Spread   float64 `json:"spread"` // annual percentage points added to the rate, negative for a discount
+}
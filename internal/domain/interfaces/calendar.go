@@ -0,0 +1,7 @@
+package interfaces
+
+import "time"
+
+type HolidayCalendar interface {
+	IsHoliday(date time.Time) bool
+}
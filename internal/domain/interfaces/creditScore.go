@@ -0,0 +1,11 @@
+package interfaces
+
+import "errors"
+
+// ErrCreditScoreNotFound is returned when the client has no credit score
+var ErrCreditScoreNotFound = errors.New("credit score not found")
+
+// CreditScoreProvider gives the credit score of a client, from 0 to 1000
+type CreditScoreProvider interface {
+	GetCreditScore(email string) (int, error)
+}
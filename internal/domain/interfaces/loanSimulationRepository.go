@@ -0,0 +1,44 @@
+package interfaces
+
+import (
+	"time"
+
+	"github.com/Jonattas-21/loan-engine/internal/domain/entities"
+	"github.com/Jonattas-21/loan-engine/package/money"
+)
+
+// Fields the simulations can be sorted by
+const (
+	SortBySimulationDate    = "simulation_date"
+	SortByLoanAmount        = "loan_amount"
+	SortByTotalInstallments = "total_installments"
+)
+
+// LoanSimulationQuery filters the saved simulations, zero values are not filtered.
+// Cursor is the NextCursor of the previous page, empty for the first one, a pagination.Cursor of the sort field.
+type LoanSimulationQuery struct {
+	Email              string
+	SimulationDateFrom time.Time
+	SimulationDateTo   time.Time
+	Currency           string
+	MinLoanAmount      money.Money
+	MaxLoanAmount      money.Money
+	MinInstallments    int
+	MaxInstallments    int
+	SortBy             string
+	Descending         bool
+	Cursor             string
+	Limit              int
+}
+
+// LoanSimulationPage is one page of a search, NextCursor is empty on the last page
+type LoanSimulationPage struct {
+	LoanSimulations []entities.LoanSimulation
+	NextCursor      string
+}
+
+type LoanSimulationRepository interface {
+	Repository[entities.LoanSimulation]
+	SearchLoanSimulations(query LoanSimulationQuery) (LoanSimulationPage, error)
+	CreateIndexes() error
+}
@@ -0,0 +1,85 @@
+package interfaces
+
+// Operators of a filter condition
+const (
+	OperatorEq  = "eq"
+	OperatorNe  = "ne"
+	OperatorGt  = "gt"
+	OperatorGte = "gte"
+	OperatorLt  = "lt"
+	OperatorLte = "lte"
+	OperatorIn  = "in" // the value is a slice
+)
+
+// Condition compares a field of the stored item with a value, fields are named as they are persisted
+type Condition struct {
+	Field    string
+	Operator string
+	Value    interface{}
+}
+
+// Filter matches the items meeting all the conditions and, when Any is informed, at least one of its filters.
+// The zero Filter matches every item.
+type Filter struct {
+	Conditions []Condition
+	Any        []Filter
+}
+
+type SortField struct {
+	Field      string
+	Descending bool
+}
+
+// Query selects the items of a filter, in the sort order, skipping the first Skip ones and up to Limit of them.
+// Zero Skip and Limit return all the items.
+type Query struct {
+	Filter Filter
+	Sort   []SortField
+	Skip   int
+	Limit  int
+}
+
+func Eq(field string, value interface{}) Condition {
+	return Condition{Field: field, Operator: OperatorEq, Value: value}
+}
+
+func Ne(field string, value interface{}) Condition {
+	return Condition{Field: field, Operator: OperatorNe, Value: value}
+}
+
+func Gt(field string, value interface{}) Condition {
+	return Condition{Field: field, Operator: OperatorGt, Value: value}
+}
+
+func Gte(field string, value interface{}) Condition {
+	return Condition{Field: field, Operator: OperatorGte, Value: value}
+}
+
+func Lt(field string, value interface{}) Condition {
+	return Condition{Field: field, Operator: OperatorLt, Value: value}
+}
+
+func Lte(field string, value interface{}) Condition {
+	return Condition{Field: field, Operator: OperatorLte, Value: value}
+}
+
+func In(field string, values interface{}) Condition {
+	return Condition{Field: field, Operator: OperatorIn, Value: values}
+}
+
+// Where is the filter of all the conditions
+func Where(conditions ...Condition) Filter {
+	return Filter{Conditions: conditions}
+}
+
+// And adds conditions to the filter
+func (f Filter) And(conditions ...Condition) Filter {
+	f.Conditions = append(append([]Condition{}, f.Conditions...), conditions...)
+	return f
+}
+
+// Or requires at least one of the filters to match too
+func (f Filter) Or(filters ...Filter) Filter {
+	f.Any = append(append([]Filter{}, f.Any...), filters...)
+	return f
+}
@@ -0,0 +1,15 @@
+package interfaces
+
+import (
+	"errors"
+
+	"github.com/Jonattas-21/loan-engine/internal/domain/entities"
+)
+
+// ErrRateIndexNotFound is returned when the registry has no values for the index
+var ErrRateIndexNotFound = errors.New("rate index not found")
+
+// RateIndexProvider gives the historical and projected values of an index, sorted by month
+type RateIndexProvider interface {
+	GetIndexValues(index string) ([]entities.IndexValue, error)
+}
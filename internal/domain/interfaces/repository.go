@@ -1,8 +1,19 @@
 package interfaces
 
+import "errors"
+
+// ErrItemNotFound is returned when no item of the collection matches the key or the filter
+var ErrItemNotFound = errors.New("item not found")
+
 type Repository[T any] interface {
 	SaveItemCollection(itemToSave T) error
 	GetItemsCollection(itemId string) ([]T, error)
+	Find(query Query) ([]T, error)
+	FindOne(filter Filter) (T, error)
+	FindByID(id string) (T, error)
+	Count(filter Filter) (int64, error)
+	Upsert(filter Filter, item T) error
+	Delete(filter Filter) error
 	DeleteItemCollection(collectionItemKey string) error
 	UpdateItemCollection(collectionItemKey string, fields map[string]interface{}) error
 	Ping() error
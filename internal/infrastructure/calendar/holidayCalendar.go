@@ -0,0 +1,65 @@
+package calendar
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"time"
+)
+
+type Holiday struct {
+	Date string `json:"date"` // YYYY-MM-DD, or MM-DD for the ones repeating every year
+	Name string `json:"name"`
+}
+
+type holidayFile struct {
+	Name     string    `json:"name"`
+	Holidays []Holiday `json:"holidays"`
+}
+
+// FileHolidayCalendar is a holiday calendar loaded from a json file, like holidays/br_national.json
+type FileHolidayCalendar struct {
+	Name      string
+	holidays  map[string]string
+	recurring map[string]string
+}
+
+func LoadHolidayCalendar(path string) (*FileHolidayCalendar, error) {
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("error reading holiday calendar %v: %w", path, err)
+	}
+
+	var file holidayFile
+	err = json.Unmarshal(content, &file)
+	if err != nil {
+		return nil, fmt.Errorf("error parsing holiday calendar %v: %w", path, err)
+	}
+
+	calendar := &FileHolidayCalendar{
+		Name:      file.Name,
+		holidays:  make(map[string]string),
+		recurring: make(map[string]string),
+	}
+	for _, holiday := range file.Holidays {
+		if _, err := time.Parse("2006-01-02", holiday.Date); err == nil {
+			calendar.holidays[holiday.Date] = holiday.Name
+			continue
+		}
+		if _, err := time.Parse("01-02", holiday.Date); err == nil {
+			calendar.recurring[holiday.Date] = holiday.Name
+			continue
+		}
+		return nil, fmt.Errorf("invalid date %v for holiday %v in calendar %v", holiday.Date, holiday.Name, path)
+	}
+
+	return calendar, nil
+}
+
+func (c *FileHolidayCalendar) IsHoliday(date time.Time) bool {
+	if _, ok := c.holidays[date.Format("2006-01-02")]; ok {
+		return true
+	}
+	_, ok := c.recurring[date.Format("01-02")]
+	return ok
+}
@@ -0,0 +1,63 @@
+package creditscore
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/Jonattas-21/loan-engine/internal/domain/interfaces"
+)
+
+// InMemoryCreditScoreProvider is a stand-in for a credit bureau, the scores are kept by email
+type InMemoryCreditScoreProvider struct {
+	scores map[string]int
+}
+
+type creditScore struct {
+	Email string `json:"email"`
+	Score int    `json:"score"`
+}
+
+type creditScoreFile struct {
+	CreditScores []creditScore `json:"credit_scores"`
+}
+
+func NewInMemoryCreditScoreProvider(scores map[string]int) *InMemoryCreditScoreProvider {
+	provider := &InMemoryCreditScoreProvider{scores: make(map[string]int)}
+	for email, score := range scores {
+		provider.scores[strings.ToLower(strings.TrimSpace(email))] = score
+	}
+	return provider
+}
+
+// LoadCreditScores reads the scores of a json file, like scores/credit_scores.json
+func LoadCreditScores(path string) (*InMemoryCreditScoreProvider, error) {
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("error reading credit scores %v: %w", path, err)
+	}
+
+	var file creditScoreFile
+	err = json.Unmarshal(content, &file)
+	if err != nil {
+		return nil, fmt.Errorf("error parsing credit scores %v: %w", path, err)
+	}
+
+	scores := make(map[string]int)
+	for _, score := range file.CreditScores {
+		if score.Score < 0 || score.Score > 1000 {
+			return nil, fmt.Errorf("invalid score %v for %v in credit scores %v", score.Score, score.Email, path)
+		}
+		scores[score.Email] = score.Score
+	}
+	return NewInMemoryCreditScoreProvider(scores), nil
+}
+
+func (p *InMemoryCreditScoreProvider) GetCreditScore(email string) (int, error) {
+	score, ok := p.scores[strings.ToLower(strings.TrimSpace(email))]
+	if !ok {
+		return 0, interfaces.ErrCreditScoreNotFound
+	}
+	return score, nil
+}
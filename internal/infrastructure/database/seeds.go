@@ -0,0 +1,55 @@
+package database
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+
+	"github.com/Jonattas-21/loan-engine/internal/domain/entities"
+)
+
+type loanConditionSeedFile struct {
+	LoanConditions []entities.LoanCondition `json:"loan_conditions"`
+}
+
+// LoadLoanConditionSeeds reads the tiers inserted in an empty database, like seeds/loan_conditions.json
+func LoadLoanConditionSeeds(path string) ([]entities.LoanCondition, error) {
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("error reading loan condition seeds %v: %w", path, err)
+	}
+
+	var file loanConditionSeedFile
+	err = json.Unmarshal(content, &file)
+	if err != nil {
+		return nil, fmt.Errorf("error parsing loan condition seeds %v: %w", path, err)
+	}
+
+	if len(file.LoanConditions) == 0 {
+		return nil, fmt.Errorf("no loan conditions in the seeds %v", path)
+	}
+	return file.LoanConditions, nil
+}
+
+type loanFeeSeedFile struct {
+	LoanFees []entities.LoanFee `json:"loan_fees"`
+}
+
+// LoadLoanFeeSeeds reads the fees inserted in an empty catalog, like seeds/loan_fees_sample.json. Without a file the catalog starts empty.
+func LoadLoanFeeSeeds(path string) ([]entities.LoanFee, error) {
+	if path == "" {
+		return nil, nil
+	}
+
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("error reading loan fee seeds %v: %w", path, err)
+	}
+
+	var file loanFeeSeedFile
+	err = json.Unmarshal(content, &file)
+	if err != nil {
+		return nil, fmt.Errorf("error parsing loan fee seeds %v: %w", path, err)
+	}
+	return file.LoanFees, nil
+}
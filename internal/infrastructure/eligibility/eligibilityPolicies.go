@@ -0,0 +1,33 @@
+package eligibility
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+
+	"github.com/Jonattas-21/loan-engine/internal/domain/entities"
+)
+
+type eligibilityPolicyFile struct {
+	EligibilityPolicies []entities.EligibilityPolicy `json:"eligibility_policies"`
+}
+
+// LoadEligibilityPolicies reads the eligibility policies from a json file, like policies/eligibility_policies_sample.json.
+// Without a file no policy is checked.
+func LoadEligibilityPolicies(path string) ([]entities.EligibilityPolicy, error) {
+	if path == "" {
+		return nil, nil
+	}
+
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("error reading eligibility policies %v: %w", path, err)
+	}
+
+	var file eligibilityPolicyFile
+	err = json.Unmarshal(content, &file)
+	if err != nil {
+		return nil, fmt.Errorf("error parsing eligibility policies %v: %w", path, err)
+	}
+	return file.EligibilityPolicies, nil
+}
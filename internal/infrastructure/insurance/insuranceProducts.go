@@ -0,0 +1,33 @@
+package insurance
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+
+	"github.com/Jonattas-21/loan-engine/internal/domain/entities"
+)
+
+type insuranceProductFile struct {
+	InsuranceProducts []entities.InsuranceProduct `json:"insurance_products"`
+}
+
+// LoadInsuranceProducts reads the insurance catalog from a json file, like products/insurance_products.json.
+// Without a file no insurance is offered.
+func LoadInsuranceProducts(path string) ([]entities.InsuranceProduct, error) {
+	if path == "" {
+		return nil, nil
+	}
+
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("error reading insurance products %v: %w", path, err)
+	}
+
+	var file insuranceProductFile
+	err = json.Unmarshal(content, &file)
+	if err != nil {
+		return nil, fmt.Errorf("error parsing insurance products %v: %w", path, err)
+	}
+	return file.InsuranceProducts, nil
+}
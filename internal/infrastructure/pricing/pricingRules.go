@@ -0,0 +1,64 @@
+package pricing
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+
+	"github.com/Jonattas-21/loan-engine/internal/domain/entities"
+)
+
+type pricingRuleFile struct {
+	PricingRules []entities.PricingRule `json:"pricing_rules"`
+}
+
+// LoadPricingRules reads the policy pricing rules from a json file, like rules/pricing_rules.json.
+// Without a file only the age tiers price the loans.
+func LoadPricingRules(path string) ([]entities.PricingRule, error) {
+	if path == "" {
+		return nil, nil
+	}
+
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("error reading pricing rules %v: %w", path, err)
+	}
+
+	var file pricingRuleFile
+	err = json.Unmarshal(content, &file)
+	if err != nil {
+		return nil, fmt.Errorf("error parsing pricing rules %v: %w", path, err)
+	}
+
+	for i := range file.PricingRules {
+		file.PricingRules[i].Kind = entities.PricingRulePolicy
+		if file.PricingRules[i].ID == "" {
+			file.PricingRules[i].ID = file.PricingRules[i].Name
+		}
+	}
+	return file.PricingRules, nil
+}
+
+type scoreBandFile struct {
+	ScoreBands []entities.ScoreBand `json:"score_bands"`
+}
+
+// LoadScoreBands reads the spreads by credit score from a json file, like rules/score_bands.json.
+// Without a file the score does not change the rate.
+func LoadScoreBands(path string) ([]entities.ScoreBand, error) {
+	if path == "" {
+		return nil, nil
+	}
+
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("error reading score bands %v: %w", path, err)
+	}
+
+	var file scoreBandFile
+	err = json.Unmarshal(content, &file)
+	if err != nil {
+		return nil, fmt.Errorf("error parsing score bands %v: %w", path, err)
+	}
+	return file.ScoreBands, nil
+}
@@ -0,0 +1,139 @@
+package rateindex
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/Jonattas-21/loan-engine/internal/domain/entities"
+	"github.com/Jonattas-21/loan-engine/internal/domain/interfaces"
+)
+
+type indexValue struct {
+	Month     string  `json:"month"` // YYYY-MM
+	Rate      float64 `json:"rate"`
+	Projected bool    `json:"projected"`
+}
+
+type rateIndex struct {
+	Index  string       `json:"index"`
+	Values []indexValue `json:"values"`
+}
+
+type rateIndexFile struct {
+	RateIndexes []rateIndex `json:"rate_indexes"`
+}
+
+// InMemoryRateIndexProvider is the index registry kept in memory, the values are kept by index
+type InMemoryRateIndexProvider struct {
+	indexes map[string][]entities.IndexValue
+}
+
+func NewInMemoryRateIndexProvider(indexes map[string][]entities.IndexValue) *InMemoryRateIndexProvider {
+	provider := &InMemoryRateIndexProvider{indexes: make(map[string][]entities.IndexValue)}
+	for index, values := range indexes {
+		sorted := append([]entities.IndexValue{}, values...)
+		sort.SliceStable(sorted, func(i, j int) bool { return sorted[i].Month.Before(sorted[j].Month) })
+		provider.indexes[strings.ToUpper(strings.TrimSpace(index))] = sorted
+	}
+	return provider
+}
+
+// LoadRateIndexes reads the historical and projected values of the indexes of a json file, like indexes/rate_indexes.json.
+// The file is a snapshot, it must be refreshed as the values are published: a projected month that passed stays an estimate
+// and the dates before the first month or after the last one carry its nearest value as an estimate.
+func LoadRateIndexes(path string) (*InMemoryRateIndexProvider, error) {
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("error reading rate indexes %v: %w", path, err)
+	}
+
+	var file rateIndexFile
+	err = json.Unmarshal(content, &file)
+	if err != nil {
+		return nil, fmt.Errorf("error parsing rate indexes %v: %w", path, err)
+	}
+
+	indexes := make(map[string][]entities.IndexValue)
+	for _, index := range file.RateIndexes {
+		values, err := parseIndexValues(index)
+		if err != nil {
+			return nil, fmt.Errorf("%v in rate indexes %v", err.Error(), path)
+		}
+		indexes[index.Index] = values
+	}
+	return NewInMemoryRateIndexProvider(indexes), nil
+}
+
+func (p *InMemoryRateIndexProvider) GetIndexValues(index string) ([]entities.IndexValue, error) {
+	values, ok := p.indexes[strings.ToUpper(strings.TrimSpace(index))]
+	if !ok || len(values) == 0 {
+		return nil, interfaces.ErrRateIndexNotFound
+	}
+	return values, nil
+}
+
+// HTTPRateIndexProvider is a stand-in for a market data service, GET {BaseURL}/{index} answers
+// the values of one index in the format of the file: {"index": "CDI", "values": [...]}
+type HTTPRateIndexProvider struct {
+	BaseURL string
+	Client  *http.Client
+}
+
+func NewHTTPRateIndexProvider(baseURL string) *HTTPRateIndexProvider {
+	return &HTTPRateIndexProvider{
+		BaseURL: strings.TrimRight(baseURL, "/"),
+		Client:  &http.Client{Timeout: 10 * time.Second},
+	}
+}
+
+func (p *HTTPRateIndexProvider) GetIndexValues(index string) ([]entities.IndexValue, error) {
+	resp, err := p.Client.Get(fmt.Sprintf("%v/%v", p.BaseURL, url.PathEscape(strings.ToUpper(strings.TrimSpace(index)))))
+	if err != nil {
+		return nil, fmt.Errorf("error requesting rate index %v: %w", index, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusNotFound {
+		return nil, interfaces.ErrRateIndexNotFound
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("error requesting rate index %v: status %v", index, resp.StatusCode)
+	}
+
+	var body rateIndex
+	err = json.NewDecoder(resp.Body).Decode(&body)
+	if err != nil {
+		return nil, fmt.Errorf("error parsing rate index %v: %w", index, err)
+	}
+
+	values, err := parseIndexValues(body)
+	if err != nil {
+		return nil, err
+	}
+	if len(values) == 0 {
+		return nil, interfaces.ErrRateIndexNotFound
+	}
+	sort.SliceStable(values, func(i, j int) bool { return values[i].Month.Before(values[j].Month) })
+	return values, nil
+}
+
+func parseIndexValues(index rateIndex) ([]entities.IndexValue, error) {
+	values := make([]entities.IndexValue, 0, len(index.Values))
+	for _, value := range index.Values {
+		month, err := time.Parse("2006-01", value.Month)
+		if err != nil {
+			return nil, fmt.Errorf("invalid month %v for index %v", value.Month, index.Index)
+		}
+		if value.Rate <= -100 {
+			return nil, fmt.Errorf("invalid rate %v in %v for index %v", value.Rate, value.Month, index.Index)
+		}
+		values = append(values, entities.IndexValue{Month: month, Rate: value.Rate, Projected: value.Projected})
+	}
+	return values, nil
+}
@@ -0,0 +1,168 @@
+package repositories
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/Jonattas-21/loan-engine/internal/domain/entities"
+	"github.com/Jonattas-21/loan-engine/internal/domain/interfaces"
+	"github.com/Jonattas-21/loan-engine/package/money"
+	"github.com/Jonattas-21/loan-engine/package/pagination"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// sortFields maps the sort options to the document fields
+var sortFields = map[string]string{
+	interfaces.SortBySimulationDate:    "simulationdate",
+	interfaces.SortByLoanAmount:        "loanamount",
+	interfaces.SortByTotalInstallments: "totalinstallments",
+}
+
+// LoanSimulationRepository adds the search of the saved simulations to the default repository
+type LoanSimulationRepository struct {
+	DefaultRepository[entities.LoanSimulation]
+}
+
+// SearchLoanSimulations returns one page of the simulations matching the query, sorted by the field and the id
+func (r *LoanSimulationRepository) SearchLoanSimulations(query interfaces.LoanSimulationQuery) (interfaces.LoanSimulationPage, error) {
+	sortField, ok := sortFields[query.SortBy]
+	if !ok {
+		return interfaces.LoanSimulationPage{}, fmt.Errorf("invalid sort field %v", query.SortBy)
+	}
+
+	filter, err := simulationFilter(query, sortField)
+	if err != nil {
+		return interfaces.LoanSimulationPage{}, err
+	}
+
+	// one more item tells if there is a next page
+	items, err := r.Find(interfaces.Query{
+		Filter: filter,
+		Sort:   []interfaces.SortField{{Field: sortField, Descending: query.Descending}, {Field: "id", Descending: query.Descending}},
+		Limit:  query.Limit + 1,
+	})
+	if err != nil {
+		return interfaces.LoanSimulationPage{}, err
+	}
+
+	page := interfaces.LoanSimulationPage{LoanSimulations: items}
+	if len(items) > query.Limit {
+		page.LoanSimulations = items[:query.Limit]
+		page.NextCursor = simulationCursor(query.SortBy, page.LoanSimulations[query.Limit-1])
+	}
+	return page, nil
+}
+
+// CreateIndexes creates the indexes used by the reads by id and by the searches, it does nothing when they exist.
+// The simulations saved before the ids have no id field, the unique index leaves them out instead of failing on them.
+func (r *LoanSimulationRepository) CreateIndexes() error {
+	collection := r.Client.Database(r.DatabaseName).Collection(r.CollectionName)
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	withID := bson.D{{Key: "id", Value: bson.D{{Key: "$exists", Value: true}, {Key: "$type", Value: "string"}}}}
+	_, err := collection.Indexes().CreateMany(ctx, []mongo.IndexModel{
+		{Keys: bson.D{{Key: "id", Value: 1}}, Options: options.Index().SetUnique(true).SetPartialFilterExpression(withID)},
+		{Keys: bson.D{{Key: "email", Value: 1}, {Key: "simulationdate", Value: -1}, {Key: "id", Value: -1}}},
+		{Keys: bson.D{{Key: "simulationdate", Value: -1}, {Key: "id", Value: -1}}},
+		{Keys: bson.D{{Key: "loanamount", Value: 1}, {Key: "id", Value: 1}}},
+		{Keys: bson.D{{Key: "totalinstallments", Value: 1}, {Key: "id", Value: 1}}},
+	})
+	if err != nil {
+		r.Logger.Errorln(fmt.Printf("Error creating indexes in DB: %v", err.Error()))
+		return err
+	}
+
+	return nil
+}
+
+func simulationFilter(query interfaces.LoanSimulationQuery, sortField string) (interfaces.Filter, error) {
+	filter := interfaces.Filter{}
+
+	if query.Email != "" {
+		filter = filter.And(interfaces.Eq("email", query.Email))
+	}
+	if query.Currency != "" {
+		filter = filter.And(interfaces.Eq("currency", query.Currency))
+	}
+	if !query.SimulationDateFrom.IsZero() {
+		filter = filter.And(interfaces.Gte("simulationdate", query.SimulationDateFrom))
+	}
+	if !query.SimulationDateTo.IsZero() {
+		filter = filter.And(interfaces.Lte("simulationdate", query.SimulationDateTo))
+	}
+	if query.MinLoanAmount.IsPositive() {
+		filter = filter.And(interfaces.Gte("loanamount", query.MinLoanAmount))
+	}
+	if query.MaxLoanAmount.IsPositive() {
+		filter = filter.And(interfaces.Lte("loanamount", query.MaxLoanAmount))
+	}
+	if query.MinInstallments > 0 {
+		filter = filter.And(interfaces.Gte("totalinstallments", query.MinInstallments))
+	}
+	if query.MaxInstallments > 0 {
+		filter = filter.And(interfaces.Lte("totalinstallments", query.MaxInstallments))
+	}
+
+	if query.Cursor != "" {
+		value, id, err := simulationCursorValue(query.SortBy, query.Cursor)
+		if err != nil {
+			return interfaces.Filter{}, err
+		}
+
+		// after the cursor: a greater sort value, or the same value and a greater id
+		after := interfaces.Gt
+		if query.Descending {
+			after = interfaces.Lt
+		}
+		filter = filter.Or(
+			interfaces.Where(after(sortField, value)),
+			interfaces.Where(interfaces.Eq(sortField, value), after("id", id)),
+		)
+	}
+
+	return filter, nil
+}
+
+// simulationCursor is the cursor of the page after the last simulation, for the sort field
+func simulationCursor(sortBy string, last entities.LoanSimulation) string {
+	cursor := pagination.Cursor{Sort: sortBy, ID: last.ID}
+	switch sortBy {
+	case interfaces.SortByLoanAmount:
+		cursor.Value = last.LoanAmount.String()
+	case interfaces.SortByTotalInstallments:
+		cursor.Value = strconv.Itoa(last.TotalInstallments)
+	default:
+		cursor.Value = last.SimulationDate.UTC().Format(time.RFC3339Nano)
+	}
+	return pagination.Encode(cursor)
+}
+
+// simulationCursorValue reads the sort value and the id of a cursor, it fails when the cursor was not made for the sort field
+func simulationCursorValue(sortBy string, encoded string) (interface{}, string, error) {
+	cursor, err := pagination.Decode(encoded)
+	if err != nil {
+		return nil, "", err
+	}
+	if cursor.Sort != sortBy {
+		return nil, "", fmt.Errorf("%w, made for the sort %v", pagination.ErrInvalidCursor, cursor.Sort)
+	}
+
+	var value interface{}
+	switch sortBy {
+	case interfaces.SortByLoanAmount:
+		value, err = money.Parse(cursor.Value)
+	case interfaces.SortByTotalInstallments:
+		value, err = strconv.Atoi(cursor.Value)
+	default:
+		value, err = time.Parse(time.RFC3339Nano, cursor.Value)
+	}
+	if err != nil {
+		return nil, "", fmt.Errorf("%w, %v", pagination.ErrInvalidCursor, err.Error())
+	}
+	return value, cursor.ID, nil
+}
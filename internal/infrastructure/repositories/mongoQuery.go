@@ -0,0 +1,79 @@
+package repositories
+
+import (
+	"fmt"
+
+	"github.com/Jonattas-21/loan-engine/internal/domain/interfaces"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+var mongoOperators = map[string]string{
+	interfaces.OperatorEq:  "$eq",
+	interfaces.OperatorNe:  "$ne",
+	interfaces.OperatorGt:  "$gt",
+	interfaces.OperatorGte: "$gte",
+	interfaces.OperatorLt:  "$lt",
+	interfaces.OperatorLte: "$lte",
+	interfaces.OperatorIn:  "$in",
+}
+
+// MongoFilter translates a filter to a mongo one, the conditions of the same field are merged in a single document
+func MongoFilter(filter interfaces.Filter) (bson.D, error) {
+	mongoFilter := bson.D{}
+	fieldPositions := make(map[string]int)
+
+	for _, condition := range filter.Conditions {
+		operator, ok := mongoOperators[condition.Operator]
+		if !ok {
+			return nil, fmt.Errorf("invalid operator %v for field %v", condition.Operator, condition.Field)
+		}
+
+		position, ok := fieldPositions[condition.Field]
+		if !ok {
+			fieldPositions[condition.Field] = len(mongoFilter)
+			mongoFilter = append(mongoFilter, bson.E{Key: condition.Field, Value: bson.D{}})
+			position = len(mongoFilter) - 1
+		}
+		mongoFilter[position].Value = append(mongoFilter[position].Value.(bson.D), bson.E{Key: operator, Value: condition.Value})
+	}
+
+	if len(filter.Any) > 0 {
+		anyFilters := bson.A{}
+		for _, anyFilter := range filter.Any {
+			mongoAnyFilter, err := MongoFilter(anyFilter)
+			if err != nil {
+				return nil, err
+			}
+			anyFilters = append(anyFilters, mongoAnyFilter)
+		}
+		mongoFilter = append(mongoFilter, bson.E{Key: "$or", Value: anyFilters})
+	}
+
+	return mongoFilter, nil
+}
+
+// MongoFindOptions translates the sort, skip and limit of a query
+func MongoFindOptions(query interfaces.Query) *options.FindOptions {
+	findOptions := options.Find()
+
+	if len(query.Sort) > 0 {
+		sort := bson.D{}
+		for _, sortField := range query.Sort {
+			direction := 1
+			if sortField.Descending {
+				direction = -1
+			}
+			sort = append(sort, bson.E{Key: sortField.Field, Value: direction})
+		}
+		findOptions.SetSort(sort)
+	}
+	if query.Skip > 0 {
+		findOptions.SetSkip(int64(query.Skip))
+	}
+	if query.Limit > 0 {
+		findOptions.SetLimit(int64(query.Limit))
+	}
+
+	return findOptions
+}
@@ -0,0 +1,55 @@
+package repositories_test
+
+import (
+	"testing"
+
+	"github.com/Jonattas-21/loan-engine/internal/domain/interfaces"
+	"github.com/Jonattas-21/loan-engine/internal/infrastructure/repositories"
+	"github.com/stretchr/testify/assert"
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+func TestMongoFilter(t *testing.T) {
+	assert := assert.New(t)
+
+	filter := interfaces.Where(interfaces.Eq("email", "test@example.com"), interfaces.Gte("totalinstallments", 12)).
+		And(interfaces.Lte("totalinstallments", 24)).
+		Or(interfaces.Where(interfaces.Gt("id", "b")), interfaces.Where(interfaces.In("currency", []string{"R$", "U$"})))
+
+	mongoFilter, err := repositories.MongoFilter(filter)
+
+	assert.NoError(err)
+	assert.Equal(bson.D{
+		{Key: "email", Value: bson.D{{Key: "$eq", Value: "test@example.com"}}},
+		{Key: "totalinstallments", Value: bson.D{{Key: "$gte", Value: 12}, {Key: "$lte", Value: 24}}},
+		{Key: "$or", Value: bson.A{
+			bson.D{{Key: "id", Value: bson.D{{Key: "$gt", Value: "b"}}}},
+			bson.D{{Key: "currency", Value: bson.D{{Key: "$in", Value: []string{"R$", "U$"}}}}},
+		}},
+	}, mongoFilter)
+}
+
+func TestMongoFilter_empty(t *testing.T) {
+	assert := assert.New(t)
+
+	mongoFilter, err := repositories.MongoFilter(interfaces.Filter{})
+	assert.NoError(err)
+	assert.Equal(bson.D{}, mongoFilter)
+
+	_, err = repositories.MongoFilter(interfaces.Where(interfaces.Condition{Field: "name", Operator: "like", Value: "tier"}))
+	assert.EqualError(err, "invalid operator like for field name")
+}
+
+func TestMongoFindOptions(t *testing.T) {
+	assert := assert.New(t)
+
+	findOptions := repositories.MongoFindOptions(interfaces.Query{
+		Sort:  []interfaces.SortField{{Field: "simulationdate", Descending: true}, {Field: "id"}},
+		Skip:  20,
+		Limit: 10,
+	})
+
+	assert.Equal(bson.D{{Key: "simulationdate", Value: -1}, {Key: "id", Value: 1}}, findOptions.Sort)
+	assert.Equal(int64(20), *findOptions.Skip)
+	assert.Equal(int64(10), *findOptions.Limit)
+}
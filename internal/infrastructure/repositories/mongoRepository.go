@@ -2,13 +2,17 @@ package repositories
 
 import (
 	"context"
+	"errors"
 	"time"
 
 	"fmt"
 
+	"github.com/Jonattas-21/loan-engine/internal/domain/interfaces"
+
 	"github.com/sirupsen/logrus"
 	"go.mongodb.org/mongo-driver/bson"
 	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
 	"os"
 )
 
@@ -61,6 +65,118 @@ func (d *DefaultRepository[T]) GetItemsCollection(itemId string) ([]T, error) {
 	return items, nil
 }
 
+// Find returns the items of the query, in its order
+func (d *DefaultRepository[T]) Find(query interfaces.Query) ([]T, error) {
+	collection := d.Client.Database(d.DatabaseName).Collection(d.CollectionName)
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	filter, err := MongoFilter(query.Filter)
+	if err != nil {
+		return nil, err
+	}
+
+	cursor, err := collection.Find(ctx, filter, MongoFindOptions(query))
+	if err != nil {
+		d.Logger.Errorln(fmt.Printf("Error during find items in DB: %v", err.Error()))
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	var items []T
+	err = cursor.All(ctx, &items)
+	if err != nil {
+		d.Logger.Errorln(fmt.Printf("Error during decode item in DB: %v", err.Error()))
+		return nil, err
+	}
+	return items, nil
+}
+
+// FindOne returns the first item of the filter, interfaces.ErrItemNotFound when there is none
+func (d *DefaultRepository[T]) FindOne(filter interfaces.Filter) (T, error) {
+	collection := d.Client.Database(d.DatabaseName).Collection(d.CollectionName)
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	var item T
+	mongoFilter, err := MongoFilter(filter)
+	if err != nil {
+		return item, err
+	}
+
+	err = collection.FindOne(ctx, mongoFilter).Decode(&item)
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		return item, interfaces.ErrItemNotFound
+	}
+	if err != nil {
+		d.Logger.Errorln(fmt.Printf("Error during find item in DB: %v", err.Error()))
+		return item, err
+	}
+
+	return item, nil
+}
+
+// FindByID returns the item with the id field, interfaces.ErrItemNotFound when there is none
+func (d *DefaultRepository[T]) FindByID(id string) (T, error) {
+	return d.FindOne(interfaces.Where(interfaces.Eq("id", id)))
+}
+
+func (d *DefaultRepository[T]) Count(filter interfaces.Filter) (int64, error) {
+	collection := d.Client.Database(d.DatabaseName).Collection(d.CollectionName)
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	mongoFilter, err := MongoFilter(filter)
+	if err != nil {
+		return 0, err
+	}
+
+	count, err := collection.CountDocuments(ctx, mongoFilter)
+	if err != nil {
+		d.Logger.Errorln(fmt.Printf("Error during count items in DB: %v", err.Error()))
+		return 0, err
+	}
+	return count, nil
+}
+
+// Upsert replaces the item matching the filter, or inserts it when there is none
+func (d *DefaultRepository[T]) Upsert(filter interfaces.Filter, item T) error {
+	collection := d.Client.Database(d.DatabaseName).Collection(d.CollectionName)
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	mongoFilter, err := MongoFilter(filter)
+	if err != nil {
+		return err
+	}
+
+	_, err = collection.ReplaceOne(ctx, mongoFilter, item, options.Replace().SetUpsert(true))
+	if err != nil {
+		d.Logger.Errorln(fmt.Printf("Error during upsert item in DB: %v", err.Error()))
+		return err
+	}
+	return nil
+}
+
+// Delete removes every item matching the filter
+func (d *DefaultRepository[T]) Delete(filter interfaces.Filter) error {
+	collection := d.Client.Database(d.DatabaseName).Collection(d.CollectionName)
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	mongoFilter, err := MongoFilter(filter)
+	if err != nil {
+		return err
+	}
+
+	_, err = collection.DeleteMany(ctx, mongoFilter)
+	if err != nil {
+		d.Logger.Errorln(fmt.Printf("Error during delete items in DB: %v", err.Error()))
+		return err
+	}
+	return nil
+}
+
 func (d *DefaultRepository[T]) UpdateItemCollection(collectionItemKey string, fields map[string]interface{}) error {
 	collection := d.Client.Database(d.DatabaseName).Collection(d.CollectionName)
 	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
@@ -89,7 +205,7 @@ func (d *DefaultRepository[T]) DeleteItemCollection(collectionItemKey string) er
 	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
 	defer cancel()
 
-	_, err := collection.DeleteOne(ctx, collectionItemKey)
+	_, err := collection.DeleteOne(ctx, bson.M{"name": collectionItemKey})
 	if err != nil {
 		d.Logger.Errorln(fmt.Printf("Error during delete item in DB: %v", err.Error()))
 		return err
@@ -0,0 +1,66 @@
+package usecases
+
+import (
+	"fmt"
+	"math/big"
+	"strings"
+
+	"github.com/Jonattas-21/loan-engine/internal/api/dto"
+	"github.com/Jonattas-21/loan-engine/internal/domain/entities"
+	"github.com/Jonattas-21/loan-engine/package/money"
+)
+
+// What happens to an offer above the debt to income limit
+const (
+	AffordabilityFlag    = "FLAG"
+	AffordabilityDecline = "DECLINE"
+
+	// payroll margin used when no limit is configured, percentage of the income
+	defaultMaxDebtToIncome = 35
+)
+
+// CheckAffordability compares the highest installment plus the other debts with the declared income,
+// the ratio and the remaining margin are kept in the simulation. Above the limit the offer is flagged,
+// or declined with the reason when so configured. Without income nothing is checked.
+func (l *LoanSimulation_usecase) CheckAffordability(loanSimulation *entities.LoanSimulation, simulationRequest dto.SimulationRequest_dto) []entities.DeclineReason {
+	income := simulationRequest.MonthlyIncome
+	if !income.IsPositive() {
+		return nil
+	}
+
+	limit := l.maxDebtToIncome()
+	commitment := MaxInstallment(loanSimulation.Installments).Add(simulationRequest.ExistingDebtPayments)
+	maxCommitment := income.Mul(new(big.Rat).Quo(money.ExactRat(limit), big.NewRat(100, 1)), l.RoundingMode)
+
+	ratio, _ := new(big.Rat).Quo(new(big.Rat).Mul(commitment.Rat(), big.NewRat(100, 1)), income.Rat()).Float64()
+	loanSimulation.MonthlyIncome = income
+	loanSimulation.ExistingDebtPayments = simulationRequest.ExistingDebtPayments
+	loanSimulation.DebtToIncomeRatio = roundPercentage(ratio)
+	loanSimulation.RemainingMargin = maxCommitment.Sub(commitment)
+	loanSimulation.AffordabilityFlag = loanSimulation.RemainingMargin.IsNegative()
+
+	if !loanSimulation.AffordabilityFlag || l.affordabilityAction() != AffordabilityDecline {
+		return nil
+	}
+	return []entities.DeclineReason{{
+		Code:    entities.DeclineDebtToIncomeAboveLimit,
+		Message: fmt.Sprintf("Installments and other debts must be up to %v%% of the monthly income", limit),
+		Limit:   fmt.Sprint(limit),
+		Value:   fmt.Sprint(loanSimulation.DebtToIncomeRatio),
+	}}
+}
+
+func (l *LoanSimulation_usecase) maxDebtToIncome() float64 {
+	if l.MaxDebtToIncome > 0 {
+		return l.MaxDebtToIncome
+	}
+	return defaultMaxDebtToIncome
+}
+
+// affordabilityAction normalizes the configured action, offers above the limit are declined by default
+func (l *LoanSimulation_usecase) affordabilityAction() string {
+	if strings.TrimSpace(l.AffordabilityAction) == "" {
+		return AffordabilityDecline
+	}
+	return strings.ToUpper(strings.TrimSpace(l.AffordabilityAction))
+}
@@ -0,0 +1,89 @@
+package usecases_test
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/Jonattas-21/loan-engine/internal/api/dto"
+	"github.com/Jonattas-21/loan-engine/internal/domain/entities"
+	"github.com/Jonattas-21/loan-engine/internal/usecases"
+	"github.com/Jonattas-21/loan-engine/package/money"
+	"github.com/stretchr/testify/assert"
+)
+
+func affordabilityRequest(income string) dto.SimulationRequest_dto {
+	return dto.SimulationRequest_dto{
+		LoanAmount:           money.MustParse("10000"),
+		Installments:         12,
+		BithDate:             time.Date(1990, 1, 1, 0, 0, 0, 0, time.UTC),
+		Currency:             "R$",
+		MonthlyIncome:        money.MustParse(income),
+		ExistingDebtPayments: money.MustParse("500"),
+	}
+}
+
+func TestCalculateLoan_affordable(t *testing.T) {
+	assert := assert.New(t)
+	setupSimulation()
+	mockLoanConditions()
+
+	result, err := loanSimulationUsecase.CalculateLoan(affordabilityRequest("10000"))
+
+	// 35% of the income less the other debts and the installment, the financed IOF included
+	assert.NoError(err)
+	installment := usecases.MaxInstallment(result.Installments)
+	assert.Equal(money.MustParse("3000").Sub(installment), result.RemainingMargin)
+	assert.InDelta(13.64, result.DebtToIncomeRatio, 0.01)
+	assert.False(result.AffordabilityFlag)
+	assert.Equal("10000.00", result.MonthlyIncome.String())
+}
+
+func TestCalculateLoan_affordabilityFlag(t *testing.T) {
+	assert := assert.New(t)
+	setupSimulation()
+	mockLoanConditions()
+	loanSimulationUsecase.AffordabilityAction = "flag"
+
+	result, err := loanSimulationUsecase.CalculateLoan(affordabilityRequest("3000"))
+
+	// the offer is kept, flagged above the limit
+	assert.NoError(err)
+	assert.True(result.AffordabilityFlag)
+	assert.True(result.RemainingMargin.IsNegative())
+	assert.InDelta(45.48, result.DebtToIncomeRatio, 0.01)
+}
+
+func TestCalculateLoan_affordabilityDecline(t *testing.T) {
+	assert := assert.New(t)
+	setupSimulation()
+	mockLoanConditions()
+	loanSimulationUsecase.MaxDebtToIncome = 30
+
+	_, err := loanSimulationUsecase.CalculateLoan(affordabilityRequest("4000"))
+
+	var declined *usecases.DeclinedError
+	assert.True(errors.As(err, &declined))
+	assert.Equal(entities.DeclineDebtToIncomeAboveLimit, declined.Reasons[0].Code)
+	assert.Equal("Installments and other debts must be up to 30% of the monthly income", declined.Reasons[0].Message)
+	assert.Equal("30", declined.Reasons[0].Limit)
+
+	// without income there is nothing to check
+	simulationRequest := affordabilityRequest("0")
+	simulationRequest.ExistingDebtPayments = money.Zero()
+	result, err := loanSimulationUsecase.CalculateLoan(simulationRequest)
+	assert.NoError(err)
+	assert.Zero(result.DebtToIncomeRatio)
+	assert.False(result.AffordabilityFlag)
+}
+
+func TestValidateSimulationRequest_affordability(t *testing.T) {
+	assert := assert.New(t)
+	setupSimulation()
+
+	simulationRequest := affordabilityRequest("0")
+	assert.Contains(loanSimulationUsecase.ValidateSimulationRequest(simulationRequest), "Monthly income is required with existing debt payments")
+
+	simulationRequest.MonthlyIncome = money.MustParse("-1")
+	assert.Contains(loanSimulationUsecase.ValidateSimulationRequest(simulationRequest), "Monthly income and existing debt payments must not be negative")
+}
@@ -0,0 +1,82 @@
+package usecases
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/Jonattas-21/loan-engine/internal/domain/entities"
+)
+
+// ages covered by the tiers when no span is configured
+const (
+	defaultMinSupportedAge = 18
+	defaultMaxSupportedAge = 100
+)
+
+// ValidateAgeBands checks that the tiers cover every age of the supported span once,
+// with no band overlapping another and no age left out
+func (l *LoanCondition_usecase) ValidateAgeBands(conditions []entities.LoanCondition) []string {
+	errs := []string{}
+	minAge, maxAge := l.supportedAges()
+
+	if len(conditions) == 0 {
+		return []string{fmt.Sprintf("At least one tier is required to cover the ages %v to %v", minAge, maxAge)}
+	}
+
+	bands := append([]entities.LoanCondition{}, conditions...)
+	sort.SliceStable(bands, func(i, j int) bool { return bands[i].MinAge < bands[j].MinAge })
+
+	names := map[string]bool{}
+	for _, band := range bands {
+		if names[band.Name] {
+			errs = append(errs, fmt.Sprintf("Tier %v is informed more than once", band.Name))
+		}
+		names[band.Name] = true
+
+		if band.MinAge > band.MaxAge {
+			errs = append(errs, fmt.Sprintf("Tier %v MinAge must not be above MaxAge", band.Name))
+		}
+		if band.MinAge < minAge || band.MaxAge > maxAge {
+			errs = append(errs, fmt.Sprintf("Tier %v ages must be between %v and %v", band.Name, minAge, maxAge))
+		}
+	}
+
+	if bands[0].MinAge > minAge {
+		errs = append(errs, fmt.Sprintf("Ages %v to %v are not covered by any tier", minAge, bands[0].MinAge-1))
+	}
+
+	// the bands are sorted by the first age, each one must start right after the end of the previous one
+	coveredUntil := bands[0].MaxAge
+	for i := 1; i < len(bands); i++ {
+		previous, band := bands[i-1], bands[i]
+		if band.MinAge <= coveredUntil {
+			errs = append(errs, fmt.Sprintf("Tiers %v (%v-%v) and %v (%v-%v) overlap", previous.Name, previous.MinAge, previous.MaxAge, band.Name, band.MinAge, band.MaxAge))
+		} else if band.MinAge > coveredUntil+1 {
+			errs = append(errs, fmt.Sprintf("Ages %v to %v are not covered by any tier", coveredUntil+1, band.MinAge-1))
+		}
+		if band.MaxAge > coveredUntil {
+			coveredUntil = band.MaxAge
+		}
+	}
+
+	if coveredUntil < maxAge {
+		errs = append(errs, fmt.Sprintf("Ages %v to %v are not covered by any tier", coveredUntil+1, maxAge))
+	}
+
+	if len(errs) > 0 {
+		return errs
+	}
+
+	return nil
+}
+
+func (l *LoanCondition_usecase) supportedAges() (int, int) {
+	minAge, maxAge := l.MinSupportedAge, l.MaxSupportedAge
+	if minAge <= 0 {
+		minAge = defaultMinSupportedAge
+	}
+	if maxAge <= 0 {
+		maxAge = defaultMaxSupportedAge
+	}
+	return minAge, maxAge
+}
@@ -0,0 +1,177 @@
+package usecases
+
+import (
+	"math/big"
+	"time"
+
+	"github.com/Jonattas-21/loan-engine/internal/domain/entities"
+	"github.com/Jonattas-21/loan-engine/package/money"
+)
+
+const (
+	AmortizationPrice = "PRICE"
+	AmortizationSAC   = "SAC"
+	AmortizationSACRE = "SACRE"
+)
+
+// AmortizationInput carries everything a calculator needs to build a schedule
+type AmortizationInput struct {
+	LoanAmount          money.Money
+	MonthlyInterestRate *big.Rat
+	FirstPeriodRate     *big.Rat   // interest of an irregular first period, nil when it is a full month
+	MonthlyRates        []*big.Rat // rate of each installment of a post-fixed loan, the monthly interest rate when empty
+	TotalInstallments   int
+	DueDates            []time.Time
+	Currency            string
+	Rounding            money.RoundingMode
+}
+
+// periodRate is the interest rate of the installment number
+func (a AmortizationInput) periodRate(number int) *big.Rat {
+	if number == 1 && a.FirstPeriodRate != nil {
+		return a.FirstPeriodRate
+	}
+	return a.monthlyRate(number)
+}
+
+// monthlyRate is the rate of a full month at the installment number
+func (a AmortizationInput) monthlyRate(number int) *big.Rat {
+	if number <= len(a.MonthlyRates) {
+		return a.MonthlyRates[number-1]
+	}
+	return a.MonthlyInterestRate
+}
+
+// AmortizationCalculator builds the installment schedule for one amortization system
+type AmortizationCalculator interface {
+	CreateInstallments(input AmortizationInput) []entities.Installment
+}
+
+// DefaultAmortizationCalculators returns the calculators for all the supported systems, keyed by the system name
+func DefaultAmortizationCalculators() map[string]AmortizationCalculator {
+	return map[string]AmortizationCalculator{
+		AmortizationPrice: &PriceCalculator{},
+		AmortizationSAC:   &SacCalculator{},
+		AmortizationSACRE: &SacreCalculator{RecalculationPeriod: 12},
+	}
+}
+
+// PriceCalculator is the french system, every installment has the same value: PV*r*(1+r)^n / ((1+r)^n-1).
+// An irregular first period grows the principal by its own rate before the formula, keeping the installments equal.
+// When the rate of a post-fixed loan changes the installment is recalculated over the balance and the remaining term.
+type PriceCalculator struct{}
+
+func (p *PriceCalculator) CreateInstallments(input AmortizationInput) []entities.Installment {
+	installmentAmount := priceInstallment(input.LoanAmount, input.monthlyRate(1), input.periodRate(1), input.TotalInstallments, input.Rounding)
+
+	return buildSchedule(input, func(number int, balance money.Money, interest money.Money) money.Money {
+		if number > 1 && input.monthlyRate(number).Cmp(input.monthlyRate(number-1)) != 0 {
+			installmentAmount = priceInstallment(balance, input.monthlyRate(number), input.monthlyRate(number), input.TotalInstallments-number+1, input.Rounding)
+		}
+		return installmentAmount.Sub(interest)
+	})
+}
+
+// priceInstallment is the level installment of the loan amount over the installments at the monthly rate,
+// the first of them at the first period rate
+func priceInstallment(loanAmount money.Money, monthlyRate *big.Rat, firstPeriodRate *big.Rat, installments int, rounding money.RoundingMode) money.Money {
+	if monthlyRate.Sign() <= 0 {
+		return loanAmount.Div(int64(installments), rounding)
+	}
+
+	// with r = a/b and 1 + the first period rate = p/q, the factor is p*a*(a+b)^(n-1) / (q*((a+b)^n - b^n)),
+	// kept in integers so no fraction is reduced. For a regular first period p/q = (a+b)/b and it is PV*r*(1+r)^n / ((1+r)^n-1)
+	a := monthlyRate.Num()
+	b := monthlyRate.Denom()
+	firstPeriodFactor := new(big.Rat).Add(big.NewRat(1, 1), firstPeriodRate)
+	n := big.NewInt(int64(installments))
+
+	// (1 + r)^n
+	aPlusB := new(big.Int).Add(a, b)
+	ratePowerNumerator := new(big.Int).Exp(aPlusB, n, nil)
+	ratePowerDenominator := new(big.Int).Exp(b, n, nil)
+
+	numerator := new(big.Int).Exp(aPlusB, new(big.Int).Sub(n, big.NewInt(1)), nil)
+	numerator.Mul(numerator, a)
+	numerator.Mul(numerator, firstPeriodFactor.Num())
+
+	// (1 + r)^n - 1
+	denominator := new(big.Int).Sub(ratePowerNumerator, ratePowerDenominator)
+	denominator.Mul(denominator, firstPeriodFactor.Denom())
+	return loanAmount.MulFraction(numerator, denominator, rounding)
+}
+
+// SacCalculator is the constant amortization system, the principal is the same and the installments decrease
+type SacCalculator struct{}
+
+func (s *SacCalculator) CreateInstallments(input AmortizationInput) []entities.Installment {
+	principalAmount := input.LoanAmount.Div(int64(input.TotalInstallments), input.Rounding)
+
+	return buildSchedule(input, func(number int, balance money.Money, interest money.Money) money.Money {
+		return principalAmount
+	})
+}
+
+// SacreCalculator is the increasing amortization system, the installment is recalculated as in SAC
+// every RecalculationPeriod months and kept fixed in between, so the principal grows over time
+type SacreCalculator struct {
+	RecalculationPeriod int
+}
+
+func (s *SacreCalculator) CreateInstallments(input AmortizationInput) []entities.Installment {
+	period := s.RecalculationPeriod
+	if period <= 0 {
+		period = 12
+	}
+
+	var installmentAmount money.Money
+	return buildSchedule(input, func(number int, balance money.Money, interest money.Money) money.Money {
+		if (number-1)%period == 0 {
+			remaining := input.TotalInstallments - number + 1
+			installmentAmount = balance.Div(int64(remaining), input.Rounding).Add(interest)
+		}
+		return installmentAmount.Sub(interest)
+	})
+}
+
+// buildSchedule walks the balance month by month, principalFor gives the principal of each installment.
+// The last installment absorbs the rounding so the principal sums exactly to the loan amount.
+func buildSchedule(input AmortizationInput, principalFor func(number int, balance money.Money, interest money.Money) money.Money) []entities.Installment {
+	var installments []entities.Installment
+	balance := input.LoanAmount
+
+	for i := 0; i < input.TotalInstallments; i++ {
+		interestAmount := balance.Mul(input.periodRate(i+1), input.Rounding)
+		principalAmount := principalFor(i+1, balance, interestAmount)
+
+		// last installment pays whatever is left of the balance
+		if i == input.TotalInstallments-1 || principalAmount.Cmp(balance) > 0 {
+			principalAmount = balance
+		}
+
+		installment := entities.Installment{
+			InstallmentNumber:    i + 1,
+			Kind:                 entities.InstallmentAmortization,
+			InstallmentAmount:    principalAmount.Add(interestAmount),
+			InstallmentFeeAmount: interestAmount,
+			PrincipalAmount:      principalAmount,
+			OpeningBalance:       balance,
+			ClosingBalance:       balance.Sub(principalAmount),
+			Currency:             input.Currency,
+		}
+		if i < len(input.DueDates) {
+			installment.DueDate = input.DueDates[i]
+		}
+		installments = append(installments, installment)
+		balance = installment.ClosingBalance
+	}
+	return installments
+}
+
+// calculatePower raises numerator and denominator separately, so the fraction is reduced only once
+func calculatePower(base *big.Rat, exponent int) *big.Rat {
+	power := big.NewInt(int64(exponent))
+	numerator := new(big.Int).Exp(base.Num(), power, nil)
+	denominator := new(big.Int).Exp(base.Denom(), power, nil)
+	return new(big.Rat).SetFrac(numerator, denominator)
+}
@@ -0,0 +1,112 @@
+package usecases_test
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/Jonattas-21/loan-engine/internal/domain/entities"
+	"github.com/Jonattas-21/loan-engine/internal/usecases"
+	"github.com/Jonattas-21/loan-engine/package/money"
+	"github.com/stretchr/testify/assert"
+)
+
+func sumPrincipal(installments []entities.Installment) money.Money {
+	var principal money.Money
+	for _, installment := range installments {
+		principal = principal.Add(installment.PrincipalAmount)
+	}
+	return principal
+}
+
+func TestPriceCalculator_constantInstallments(t *testing.T) {
+	assert := assert.New(t)
+	calculator := &usecases.PriceCalculator{}
+
+	installments := calculator.CreateInstallments(usecases.AmortizationInput{
+		LoanAmount:          money.MustParse("10000"),
+		MonthlyInterestRate: big.NewRat(1, 400),
+		TotalInstallments:   6,
+		Currency:            "R$",
+	})
+
+	assert.Len(installments, 6)
+	for _, installment := range installments[:5] {
+		assert.Equal("1681.28", installment.InstallmentAmount.String())
+	}
+	assert.Equal(money.MustParse("10000"), sumPrincipal(installments))
+	assert.True(installments[5].ClosingBalance.IsZero())
+}
+
+func TestPriceCalculator_zeroRate(t *testing.T) {
+	assert := assert.New(t)
+	calculator := &usecases.PriceCalculator{}
+
+	installments := calculator.CreateInstallments(usecases.AmortizationInput{
+		LoanAmount:          money.MustParse("1000"),
+		MonthlyInterestRate: new(big.Rat),
+		TotalInstallments:   3,
+		Currency:            "R$",
+	})
+
+	assert.Equal("333.33", installments[0].InstallmentAmount.String())
+	assert.Equal("333.34", installments[2].InstallmentAmount.String())
+	assert.Equal(money.MustParse("1000"), sumPrincipal(installments))
+}
+
+func TestSacCalculator_decreasingInstallments(t *testing.T) {
+	assert := assert.New(t)
+	calculator := &usecases.SacCalculator{}
+
+	installments := calculator.CreateInstallments(usecases.AmortizationInput{
+		LoanAmount:          money.MustParse("12000"),
+		MonthlyInterestRate: big.NewRat(1, 100),
+		TotalInstallments:   12,
+		Currency:            "R$",
+	})
+
+	assert.Len(installments, 12)
+	assert.Equal("1000.00", installments[0].PrincipalAmount.String())
+	assert.Equal("120.00", installments[0].InstallmentFeeAmount.String())
+	assert.Equal("1120.00", installments[0].InstallmentAmount.String())
+	assert.Equal("1010.00", installments[11].InstallmentAmount.String())
+	for i := 1; i < len(installments); i++ {
+		assert.Equal("1000.00", installments[i].PrincipalAmount.String())
+		assert.Equal(-1, installments[i].InstallmentAmount.Cmp(installments[i-1].InstallmentAmount))
+	}
+	assert.True(installments[11].ClosingBalance.IsZero())
+}
+
+func TestSacreCalculator_increasingPrincipal(t *testing.T) {
+	assert := assert.New(t)
+	calculator := &usecases.SacreCalculator{RecalculationPeriod: 12}
+
+	installments := calculator.CreateInstallments(usecases.AmortizationInput{
+		LoanAmount:          money.MustParse("24000"),
+		MonthlyInterestRate: big.NewRat(1, 100),
+		TotalInstallments:   24,
+		Currency:            "R$",
+	})
+
+	assert.Len(installments, 24)
+
+	// the installment is fixed inside each recalculation period
+	assert.Equal("1240.00", installments[0].InstallmentAmount.String())
+	assert.Equal(installments[0].InstallmentAmount, installments[11].InstallmentAmount)
+	assert.NotEqual(installments[11].InstallmentAmount, installments[12].InstallmentAmount)
+
+	for i := 1; i < 12; i++ {
+		assert.Equal(1, installments[i].PrincipalAmount.Cmp(installments[i-1].PrincipalAmount))
+	}
+	assert.Equal(money.MustParse("24000"), sumPrincipal(installments))
+	assert.True(installments[23].ClosingBalance.IsZero())
+}
+
+func TestDefaultAmortizationCalculators(t *testing.T) {
+	assert := assert.New(t)
+
+	calculators := usecases.DefaultAmortizationCalculators()
+
+	assert.Contains(calculators, usecases.AmortizationPrice)
+	assert.Contains(calculators, usecases.AmortizationSAC)
+	assert.Contains(calculators, usecases.AmortizationSACRE)
+}
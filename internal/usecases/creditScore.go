@@ -0,0 +1,69 @@
+package usecases
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/Jonattas-21/loan-engine/internal/domain/entities"
+	"github.com/Jonattas-21/loan-engine/internal/domain/interfaces"
+)
+
+// ScoreBandFor is the band covering the score, false when none does
+func ScoreBandFor(bands []entities.ScoreBand, score int) (entities.ScoreBand, bool) {
+	for _, band := range bands {
+		if score >= band.MinScore && score <= band.MaxScore {
+			return band, true
+		}
+	}
+	return entities.ScoreBand{}, false
+}
+
+// ValidateScoreBands checks the configured bands, from 0 to 1000 with no overlap
+func ValidateScoreBands(bands []entities.ScoreBand) []string {
+	errs := []string{}
+	for _, band := range bands {
+		if strings.TrimSpace(band.Name) == "" {
+			errs = append(errs, "Score band name is required")
+		}
+		if band.MinScore < 0 || band.MaxScore > 1000 || band.MinScore > band.MaxScore {
+			errs = append(errs, fmt.Sprintf("Score band %v must go from a min score to a max score between 0 and 1000", band.Name))
+		}
+	}
+
+	sorted := append([]entities.ScoreBand{}, bands...)
+	sort.SliceStable(sorted, func(i, j int) bool { return sorted[i].MinScore < sorted[j].MinScore })
+	for i := 1; i < len(sorted); i++ {
+		if sorted[i].MinScore <= sorted[i-1].MaxScore {
+			errs = append(errs, fmt.Sprintf("Score bands %v and %v overlap", sorted[i-1].Name, sorted[i].Name))
+		}
+	}
+
+	if len(errs) > 0 {
+		return errs
+	}
+
+	return nil
+}
+
+// CreditScoreFor reads the credit score of the client and its band, a client without score
+// or a score out of the bands gets no spread
+func (l *LoanSimulation_usecase) CreditScoreFor(email string) (int, entities.ScoreBand, error) {
+	if l.CreditScoreProvider == nil {
+		return 0, entities.ScoreBand{}, nil
+	}
+
+	score, err := l.CreditScoreProvider.GetCreditScore(email)
+	if errors.Is(err, interfaces.ErrCreditScoreNotFound) {
+		l.Logger.Infoln(fmt.Sprintf("[email:%v] no credit score, the rate has no spread", email))
+		return 0, entities.ScoreBand{}, nil
+	}
+	if err != nil {
+		l.Logger.Errorln(fmt.Sprintf("[email:%v] Error getting credit score", email), err.Error())
+		return 0, entities.ScoreBand{}, fmt.Errorf("error getting credit score, %v", err.Error())
+	}
+
+	band, _ := ScoreBandFor(l.ScoreBands, score)
+	return score, band, nil
+}
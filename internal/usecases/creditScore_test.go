@@ -0,0 +1,97 @@
+package usecases_test
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/Jonattas-21/loan-engine/internal/api/dto"
+	"github.com/Jonattas-21/loan-engine/internal/domain/entities"
+	"github.com/Jonattas-21/loan-engine/internal/domain/interfaces"
+	"github.com/Jonattas-21/loan-engine/internal/usecases"
+	"github.com/Jonattas-21/loan-engine/package/money"
+	internalMock "github.com/Jonattas-21/loan-engine/tests"
+	"github.com/stretchr/testify/assert"
+)
+
+var scoreBands = []entities.ScoreBand{
+	{Name: "LOW", MinScore: 0, MaxScore: 399, Spread: 2},
+	{Name: "MEDIUM", MinScore: 400, MaxScore: 699, Spread: 0.5},
+	{Name: "HIGH", MinScore: 700, MaxScore: 1000, Spread: -0.5},
+}
+
+func scoreRequest(email string) dto.SimulationRequest_dto {
+	return dto.SimulationRequest_dto{
+		Email:        email,
+		LoanAmount:   money.MustParse("10000"),
+		Installments: 12,
+		BithDate:     time.Date(1990, 1, 1, 0, 0, 0, 0, time.UTC),
+		Currency:     "R$",
+	}
+}
+
+func TestCalculateLoan_creditScore(t *testing.T) {
+	assert := assert.New(t)
+	setupSimulation()
+	mockLoanConditions()
+	creditScoreProvider := new(internalMock.MockCreditScoreProvider)
+	creditScoreProvider.On("GetCreditScore", "good.payer@example.com").Return(850, nil)
+	creditScoreProvider.On("GetCreditScore", "late.payer@example.com").Return(320, nil)
+	creditScoreProvider.On("GetCreditScore", "new.client@example.com").Return(0, interfaces.ErrCreditScoreNotFound)
+	loanSimulationUsecase.CreditScoreProvider = creditScoreProvider
+	loanSimulationUsecase.ScoreBands = scoreBands
+
+	// the spread goes on top of the 3% of tier2
+	testCases := []struct {
+		email  string
+		score  int
+		band   string
+		spread float64
+		rate   float64
+	}{
+		{"good.payer@example.com", 850, "HIGH", -0.5, 2.5},
+		{"late.payer@example.com", 320, "LOW", 2, 5},
+		{"new.client@example.com", 0, "", 0, 3},
+	}
+
+	for _, testCase := range testCases {
+		result, err := loanSimulationUsecase.CalculateLoan(scoreRequest(testCase.email))
+
+		assert.NoError(err, testCase.email)
+		assert.Equal(testCase.score, result.CreditScore, testCase.email)
+		assert.Equal(testCase.band, result.CreditScoreBand, testCase.email)
+		assert.Equal(testCase.spread, result.CreditScoreSpread, testCase.email)
+		assert.Equal(testCase.rate, result.FeeAmountPercentage, testCase.email)
+		assert.Equal("tier2", result.PricingRuleName, testCase.email)
+	}
+	creditScoreProvider.AssertNumberOfCalls(t, "GetCreditScore", 3)
+}
+
+func TestCalculateLoan_creditScoreError(t *testing.T) {
+	assert := assert.New(t)
+	setupSimulation()
+	mockLoanConditions()
+	creditScoreProvider := new(internalMock.MockCreditScoreProvider)
+	creditScoreProvider.On("GetCreditScore", "test@example.com").Return(0, errors.New("bureau unavailable"))
+	loanSimulationUsecase.CreditScoreProvider = creditScoreProvider
+
+	_, err := loanSimulationUsecase.CalculateLoan(scoreRequest("test@example.com"))
+	assert.EqualError(err, "error getting credit score, bureau unavailable")
+}
+
+func TestValidateScoreBands(t *testing.T) {
+	assert := assert.New(t)
+
+	assert.Nil(usecases.ValidateScoreBands(scoreBands))
+	assert.Equal([]string{
+		"Score band MEDIUM must go from a min score to a max score between 0 and 1000",
+		"Score bands LOW and MEDIUM overlap",
+	}, usecases.ValidateScoreBands([]entities.ScoreBand{
+		{Name: "LOW", MinScore: 0, MaxScore: 500},
+		{Name: "MEDIUM", MinScore: 400, MaxScore: 1200},
+	}))
+
+	band, found := usecases.ScoreBandFor(scoreBands, 400)
+	assert.True(found)
+	assert.Equal("MEDIUM", band.Name)
+}
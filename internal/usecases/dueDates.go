@@ -0,0 +1,129 @@
+package usecases
+
+import (
+	"math"
+	"math/big"
+	"time"
+
+	"github.com/Jonattas-21/loan-engine/internal/domain/interfaces"
+)
+
+// the first due date is pushed one month when it would fall too close to the disbursement
+const minFirstPeriodDays = 15
+
+// NominalDueDates returns the contractual due date of each installment, on the payment day of every month
+// after the disbursement. Months shorter than the payment day use their last day.
+func NominalDueDates(disbursementDate time.Time, paymentDay int, totalInstallments int) []time.Time {
+	if paymentDay <= 0 {
+		paymentDay = disbursementDate.Day()
+	}
+
+	firstMonth := 1
+	if daysBetween(disbursementDate, monthlyDate(disbursementDate, firstMonth, paymentDay)) < minFirstPeriodDays {
+		firstMonth++
+	}
+
+	dueDates := make([]time.Time, totalInstallments)
+	for i := range dueDates {
+		dueDates[i] = monthlyDate(disbursementDate, firstMonth+i, paymentDay)
+	}
+	return dueDates
+}
+
+// SchedulePaymentDay is the day of the month of the installments, without a preferred day they keep the day the payments start
+func SchedulePaymentDay(paymentDay int, disbursementDate time.Time, gracePeriod GracePeriod) int {
+	if paymentDay > 0 {
+		return paymentDay
+	}
+	if gracePeriod.Unit == GraceUnitDays && gracePeriod.Length > 0 {
+		return disbursementDate.AddDate(0, 0, gracePeriod.Length).Day()
+	}
+	return disbursementDate.Day()
+}
+
+// NextBusinessDay rolls the date forward past weekends and the holidays of the calendar, a nil calendar only skips weekends
+func NextBusinessDay(date time.Time, calendar interfaces.HolidayCalendar) time.Time {
+	for date.Weekday() == time.Saturday || date.Weekday() == time.Sunday || (calendar != nil && calendar.IsHoliday(date)) {
+		date = date.AddDate(0, 0, 1)
+	}
+	return date
+}
+
+// FirstPeriodRate is the interest of the period between the disbursement and the first due date.
+// A full month pays the monthly rate, irregular periods pay it pro rata: (1 + r)^(days/30) - 1
+func FirstPeriodRate(disbursementDate time.Time, firstDueDate time.Time, monthlyInterestRate *big.Rat) *big.Rat {
+	if isFullMonth(disbursementDate, firstDueDate) {
+		return monthlyInterestRate
+	}
+
+	factor := new(big.Rat).Add(big.NewRat(1, 1), monthlyInterestRate)
+	periodFactor := ratPower(factor, big.NewRat(int64(daysBetween(disbursementDate, firstDueDate)), 30))
+	return periodFactor.Sub(periodFactor, big.NewRat(1, 1))
+}
+
+// rootPrecision is the precision in bits of the root of a fractional power, far below a cent of any balance
+const rootPrecision = 256
+
+// ratPower is base^exponent, the whole part of the exponent is exact and its fraction p/q is the q-th root of base^p
+func ratPower(base *big.Rat, exponent *big.Rat) *big.Rat {
+	whole, fraction := new(big.Int).DivMod(exponent.Num(), exponent.Denom(), new(big.Int))
+
+	power := calculatePower(base, int(new(big.Int).Abs(whole).Int64()))
+	if whole.Sign() < 0 {
+		power.Inv(power)
+	}
+	if fraction.Sign() == 0 {
+		return power
+	}
+
+	root := ratRoot(calculatePower(base, int(fraction.Int64())), int(exponent.Denom().Int64()))
+	return power.Mul(power, root)
+}
+
+// ratRoot is the n-th root of a positive value by Newton's method, starting from the float64 root
+func ratRoot(value *big.Rat, n int) *big.Rat {
+	x := new(big.Float).SetPrec(rootPrecision).SetRat(value)
+	approximation, _ := value.Float64()
+	root := new(big.Float).SetPrec(rootPrecision).SetFloat64(math.Pow(approximation, 1/float64(n)))
+
+	// each step doubles the correct bits, from the 53 of the float64
+	degree := new(big.Float).SetPrec(rootPrecision).SetInt64(int64(n))
+	previousDegree := new(big.Float).SetPrec(rootPrecision).SetInt64(int64(n - 1))
+	for bits := 53; bits < 2*rootPrecision; bits *= 2 {
+		power := new(big.Float).SetPrec(rootPrecision).SetInt64(1)
+		for i := 0; i < n-1; i++ {
+			power.Mul(power, root)
+		}
+		next := new(big.Float).SetPrec(rootPrecision).Quo(x, power)
+		next.Add(next, new(big.Float).SetPrec(rootPrecision).Mul(previousDegree, root))
+		root = next.Quo(next, degree)
+	}
+
+	rat, _ := root.Rat(nil)
+	return rat
+}
+
+// isFullMonth tells if to is one month after from, like jan 31 to feb 28 or feb 28 to mar 31 when the payment day is 31
+func isFullMonth(from time.Time, to time.Time) bool {
+	if monthlyDate(from, 1, from.Day()).Equal(to) {
+		return true
+	}
+	isLastDayOfMonth := from.AddDate(0, 0, 1).Month() != from.Month()
+	return isLastDayOfMonth && to.Day() > from.Day() && monthlyDate(from, 1, to.Day()).Equal(to)
+}
+
+// monthlyDate is the date months after from, on the given day or on the last day of shorter months
+func monthlyDate(from time.Time, months int, day int) time.Time {
+	firstOfMonth := time.Date(from.Year(), from.Month()+time.Month(months), 1, 0, 0, 0, 0, from.Location())
+	lastDay := firstOfMonth.AddDate(0, 1, -1).Day()
+	if day > lastDay {
+		day = lastDay
+	}
+	return time.Date(firstOfMonth.Year(), firstOfMonth.Month(), day, 0, 0, 0, 0, from.Location())
+}
+
+func daysBetween(from time.Time, to time.Time) int {
+	fromDate := time.Date(from.Year(), from.Month(), from.Day(), 0, 0, 0, 0, time.UTC)
+	toDate := time.Date(to.Year(), to.Month(), to.Day(), 0, 0, 0, 0, time.UTC)
+	return int(math.Round(toDate.Sub(fromDate).Hours() / 24))
+}
@@ -0,0 +1,95 @@
+package usecases_test
+
+import (
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/Jonattas-21/loan-engine/internal/usecases"
+	"github.com/Jonattas-21/loan-engine/package/money"
+	"github.com/stretchr/testify/assert"
+)
+
+type holidayCalendarStub map[string]bool
+
+func (h holidayCalendarStub) IsHoliday(date time.Time) bool {
+	return h[date.Format("2006-01-02")]
+}
+
+func date(year int, month time.Month, day int) time.Time {
+	return time.Date(year, month, day, 0, 0, 0, 0, time.UTC)
+}
+
+func TestNominalDueDates_sameDay(t *testing.T) {
+	assert := assert.New(t)
+
+	dueDates := usecases.NominalDueDates(date(2025, 1, 31), 0, 3)
+
+	// short months use their last day
+	assert.Equal([]time.Time{date(2025, 2, 28), date(2025, 3, 31), date(2025, 4, 30)}, dueDates)
+}
+
+func TestNominalDueDates_paymentDay(t *testing.T) {
+	assert := assert.New(t)
+
+	// 26 days until the first payment day
+	dueDates := usecases.NominalDueDates(date(2025, 1, 15), 10, 2)
+	assert.Equal([]time.Time{date(2025, 2, 10), date(2025, 3, 10)}, dueDates)
+
+	// only 6 days until the payment day, so the first installment goes to the next month
+	dueDates = usecases.NominalDueDates(date(2025, 1, 25), 1, 2)
+	assert.Equal([]time.Time{date(2025, 3, 1), date(2025, 4, 1)}, dueDates)
+}
+
+func TestNextBusinessDay(t *testing.T) {
+	assert := assert.New(t)
+	calendar := holidayCalendarStub{"2025-04-21": true}
+
+	assert.Equal(date(2025, 4, 22), usecases.NextBusinessDay(date(2025, 4, 19), calendar)) // saturday, sunday and tiradentes
+	assert.Equal(date(2025, 4, 21), usecases.NextBusinessDay(date(2025, 4, 19), nil))
+	assert.Equal(date(2025, 4, 23), usecases.NextBusinessDay(date(2025, 4, 23), calendar))
+}
+
+func TestFirstPeriodRate(t *testing.T) {
+	assert := assert.New(t)
+	monthlyRate := money.ExactRat(0.01)
+
+	// a full month is the monthly rate, even in february
+	assert.Equal(monthlyRate, usecases.FirstPeriodRate(date(2025, 1, 31), date(2025, 2, 28), monthlyRate))
+
+	// 45 days is (1.01)^1.5 - 1
+	rate, _ := usecases.FirstPeriodRate(date(2025, 1, 1), date(2025, 2, 15), monthlyRate).Float64()
+	assert.InDelta(0.015037, rate, 0.000001)
+
+	// squared it is (1.01)^3 far below a cent of any balance
+	factor := new(big.Rat).Add(big.NewRat(1, 1), usecases.FirstPeriodRate(date(2025, 1, 1), date(2025, 2, 15), monthlyRate))
+	difference := new(big.Rat).Sub(new(big.Rat).Mul(factor, factor), big.NewRat(1030301, 1000000))
+	assert.Equal(-1, new(big.Rat).Abs(difference).Cmp(big.NewRat(1, 1_000_000_000_000_000_000)))
+
+	// 60 days are two whole periods of 30 days, exact
+	assert.Equal(big.NewRat(201, 10000), usecases.FirstPeriodRate(date(2025, 1, 1), date(2025, 3, 2), monthlyRate))
+}
+
+func TestPriceCalculator_irregularFirstPeriod(t *testing.T) {
+	assert := assert.New(t)
+	calculator := &usecases.PriceCalculator{}
+	monthlyRate := money.ExactRat(0.01)
+
+	installments := calculator.CreateInstallments(usecases.AmortizationInput{
+		LoanAmount:          money.MustParse("10000"),
+		MonthlyInterestRate: monthlyRate,
+		FirstPeriodRate:     usecases.FirstPeriodRate(date(2025, 1, 1), date(2025, 2, 15), monthlyRate),
+		TotalInstallments:   12,
+		DueDates:            usecases.NominalDueDates(date(2025, 1, 1), 15, 12),
+		Currency:            "R$",
+	})
+
+	// the first interest covers 45 days and the installments stay equal
+	assert.Equal("150.37", installments[0].InstallmentFeeAmount.String())
+	assert.Equal(date(2025, 2, 15), installments[0].DueDate)
+	for _, installment := range installments[:11] {
+		assert.Equal(installments[0].InstallmentAmount, installment.InstallmentAmount)
+	}
+	assert.InDelta(installments[0].InstallmentAmount.Float64(), installments[11].InstallmentAmount.Float64(), 0.05)
+	assert.True(installments[11].ClosingBalance.IsZero())
+}
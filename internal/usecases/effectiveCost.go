@@ -0,0 +1,111 @@
+package usecases
+
+import (
+	"fmt"
+	"math"
+
+	"github.com/Jonattas-21/loan-engine/internal/domain/entities"
+	"github.com/Jonattas-21/loan-engine/package/money"
+)
+
+// CashFlow is a value received (positive) or paid (negative) by the customer, Period is measured in months from the disbursement
+type CashFlow struct {
+	Period float64
+	Amount money.Money
+}
+
+const (
+	irrMaxIterations = 200
+	irrTolerance     = 1e-12
+)
+
+// CalculateIRR solves the monthly rate that brings the net present value of the cash flows to zero.
+// Newton-Raphson converges in a few steps for loan flows, bisection is the fallback when it does not.
+func CalculateIRR(cashFlows []CashFlow) (float64, error) {
+	if len(cashFlows) < 2 {
+		return 0, fmt.Errorf("at least two cash flows are required to calculate the effective rate")
+	}
+
+	npv := func(rate float64) (float64, float64) {
+		var value, derivative float64
+		for _, cashFlow := range cashFlows {
+			amount := cashFlow.Amount.Float64()
+			discount := math.Pow(1+rate, -cashFlow.Period)
+			value += amount * discount
+			derivative += -cashFlow.Period * amount * discount / (1 + rate)
+		}
+		return value, derivative
+	}
+
+	rate := 0.01
+	for i := 0; i < irrMaxIterations; i++ {
+		value, derivative := npv(rate)
+		if math.Abs(value) < irrTolerance {
+			return rate, nil
+		}
+		if derivative == 0 {
+			break
+		}
+		next := rate - value/derivative
+		if next <= -1 || math.IsNaN(next) || math.IsInf(next, 0) {
+			break
+		}
+		if math.Abs(next-rate) < irrTolerance {
+			return next, nil
+		}
+		rate = next
+	}
+
+	// bisection between a rate close to -100% and 100% per month
+	low, high := -0.99, 1.0
+	lowValue, _ := npv(low)
+	highValue, _ := npv(high)
+	if lowValue*highValue > 0 {
+		return 0, fmt.Errorf("effective rate not found for the cash flows")
+	}
+	for i := 0; i < irrMaxIterations; i++ {
+		middle := (low + high) / 2
+		middleValue, _ := npv(middle)
+		if math.Abs(middleValue) < irrTolerance || (high-low)/2 < irrTolerance {
+			return middle, nil
+		}
+		if middleValue*lowValue < 0 {
+			high = middle
+		} else {
+			low = middle
+			lowValue = middleValue
+		}
+	}
+	return (low + high) / 2, nil
+}
+
+// SimulationCashFlows is the customer's point of view: the amount received on disbursement, less what is paid upfront, and every installment paid.
+// Periods follow the CET rule of calendar days over a 365 days year, expressed in months.
+func SimulationCashFlows(loanSimulation entities.LoanSimulation) []CashFlow {
+	cashFlows := []CashFlow{{Period: 0, Amount: loanSimulation.LoanAmount.Sub(loanSimulation.UpfrontAmount)}}
+	for _, installment := range loanSimulation.Installments {
+		period := float64(installment.InstallmentNumber)
+		if !installment.DueDate.IsZero() && !loanSimulation.DisbursementDate.IsZero() {
+			period = float64(daysBetween(loanSimulation.DisbursementDate, installment.DueDate)) * 12 / 365
+		}
+		cashFlows = append(cashFlows, CashFlow{
+			Period: period,
+			Amount: installment.InstallmentAmount.Neg(),
+		})
+	}
+	return cashFlows
+}
+
+// CalculateEffectiveRates returns the CET as monthly and annual percentages
+func CalculateEffectiveRates(cashFlows []CashFlow) (float64, float64, error) {
+	monthlyRate, err := CalculateIRR(cashFlows)
+	if err != nil {
+		return 0, 0, err
+	}
+	annualRate := math.Pow(1+monthlyRate, 12) - 1
+	return roundPercentage(monthlyRate * 100), roundPercentage(annualRate * 100), nil
+}
+
+func roundPercentage(value float64) float64 {
+	return math.Round(value*10000) / 10000
+}
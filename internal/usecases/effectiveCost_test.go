@@ -0,0 +1,59 @@
+package usecases_test
+
+import (
+	"testing"
+
+	"github.com/Jonattas-21/loan-engine/internal/domain/entities"
+	"github.com/Jonattas-21/loan-engine/internal/usecases"
+	"github.com/Jonattas-21/loan-engine/package/money"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCalculateIRR_ok(t *testing.T) {
+	assert := assert.New(t)
+
+	// 1000 today against 1100 in one month is 10% per month
+	rate, err := usecases.CalculateIRR([]usecases.CashFlow{
+		{Period: 0, Amount: money.MustParse("1000")},
+		{Period: 1, Amount: money.MustParse("-1100")},
+	})
+
+	assert.NoError(err)
+	assert.InDelta(0.10, rate, 1e-9)
+}
+
+func TestCalculateIRR_invalid(t *testing.T) {
+	assert := assert.New(t)
+
+	_, err := usecases.CalculateIRR([]usecases.CashFlow{{Period: 0, Amount: money.MustParse("1000")}})
+	assert.Error(err)
+
+	// nobody pays anything back, there is no rate for it
+	_, err = usecases.CalculateIRR([]usecases.CashFlow{
+		{Period: 0, Amount: money.MustParse("1000")},
+		{Period: 1, Amount: money.MustParse("1000")},
+	})
+	assert.Error(err)
+}
+
+func TestCalculateEffectiveRates_matchesNominalWithoutCosts(t *testing.T) {
+	assert := assert.New(t)
+	calculator := &usecases.SacCalculator{}
+
+	loanSimulation := entities.LoanSimulation{
+		LoanAmount: money.MustParse("12000"),
+		Installments: calculator.CreateInstallments(usecases.AmortizationInput{
+			LoanAmount:          money.MustParse("12000"),
+			MonthlyInterestRate: money.ExactRat(0.01),
+			TotalInstallments:   12,
+			Currency:            "R$",
+		}),
+	}
+
+	monthly, annual, err := usecases.CalculateEffectiveRates(usecases.SimulationCashFlows(loanSimulation))
+
+	// with no fees, taxes or insurance the CET is the contract rate
+	assert.NoError(err)
+	assert.InDelta(1.0, monthly, 0.0001)
+	assert.InDelta(12.6825, annual, 0.0001)
+}
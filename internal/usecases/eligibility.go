@@ -0,0 +1,142 @@
+package usecases
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/Jonattas-21/loan-engine/internal/domain/entities"
+	"github.com/Jonattas-21/loan-engine/package/money"
+)
+
+// DeclinedError declines a simulation request, with every reason found
+type DeclinedError struct {
+	Reasons []entities.DeclineReason
+}
+
+func (e *DeclinedError) Error() string {
+	messages := make([]string, len(e.Reasons))
+	for i, reason := range e.Reasons {
+		messages[i] = reason.Message
+	}
+	return strings.Join(messages, ", ")
+}
+
+// AgeAt is the age in full years at the date
+func AgeAt(birthDate time.Time, date time.Time) int {
+	age := date.Year() - birthDate.Year()
+
+	// adjust the age if the birthdate has not occurred yet this year
+	if date.YearDay() < birthDate.YearDay() {
+		age--
+	}
+	return age
+}
+
+// CheckEligibility checks the simulation against the policies of its currency, the reasons list every broken limit
+func (l *LoanSimulation_usecase) CheckEligibility(loanSimulation entities.LoanSimulation, birthDate time.Time) []entities.DeclineReason {
+	var reasons []entities.DeclineReason
+
+	var lastDueDate time.Time
+	var minInstallment money.Money
+	for _, installment := range loanSimulation.Installments {
+		lastDueDate = installment.DueDate
+		if installment.Kind == entities.InstallmentGrace {
+			continue
+		}
+		if minInstallment.IsZero() || installment.InstallmentAmount.Cmp(minInstallment) < 0 {
+			minInstallment = installment.InstallmentAmount
+		}
+	}
+
+	for _, policy := range l.EligibilityPolicies {
+		if policy.Currency != "" && policy.Currency != loanSimulation.Currency {
+			continue
+		}
+
+		if !policy.MinLoanAmount.IsZero() && loanSimulation.LoanAmount.Cmp(policy.MinLoanAmount) < 0 {
+			reasons = append(reasons, entities.DeclineReason{
+				Code:    entities.DeclineAmountBelowMinimum,
+				Message: fmt.Sprintf("Loan amount must be at least %v %v", loanSimulation.Currency, policy.MinLoanAmount),
+				Limit:   policy.MinLoanAmount.String(),
+				Value:   loanSimulation.LoanAmount.String(),
+			})
+		}
+
+		if !policy.MaxLoanAmount.IsZero() && loanSimulation.LoanAmount.Cmp(policy.MaxLoanAmount) > 0 {
+			reasons = append(reasons, entities.DeclineReason{
+				Code:    entities.DeclineAmountAboveMaximum,
+				Message: fmt.Sprintf("Loan amount must be up to %v %v", loanSimulation.Currency, policy.MaxLoanAmount),
+				Limit:   policy.MaxLoanAmount.String(),
+				Value:   loanSimulation.LoanAmount.String(),
+			})
+		}
+
+		if ageAtMaturity := AgeAt(birthDate, lastDueDate); policy.MaxAgeAtMaturity > 0 && ageAtMaturity > policy.MaxAgeAtMaturity {
+			reasons = append(reasons, entities.DeclineReason{
+				Code:    entities.DeclineAgeAtMaturityAboveLimit,
+				Message: fmt.Sprintf("Age at the last installment must be up to %v", policy.MaxAgeAtMaturity),
+				Limit:   fmt.Sprint(policy.MaxAgeAtMaturity),
+				Value:   fmt.Sprint(ageAtMaturity),
+			})
+		}
+
+		if !policy.MinInstallmentAmount.IsZero() && minInstallment.Cmp(policy.MinInstallmentAmount) < 0 {
+			reasons = append(reasons, entities.DeclineReason{
+				Code:    entities.DeclineInstallmentBelowMinimum,
+				Message: fmt.Sprintf("Installments must be at least %v %v", loanSimulation.Currency, policy.MinInstallmentAmount),
+				Limit:   policy.MinInstallmentAmount.String(),
+				Value:   minInstallment.String(),
+			})
+		}
+	}
+
+	return reasons
+}
+
+// ValidateEligibilityPolicies checks the configured policies
+func ValidateEligibilityPolicies(policies []entities.EligibilityPolicy) []string {
+	errs := []string{}
+	for _, policy := range policies {
+		currency := policy.Currency
+		if currency == "" {
+			currency = "every currency"
+		}
+
+		if policy.MinLoanAmount.IsNegative() || policy.MaxLoanAmount.IsNegative() || policy.MinInstallmentAmount.IsNegative() || policy.MaxAgeAtMaturity < 0 {
+			errs = append(errs, fmt.Sprintf("Eligibility policy of %v has a negative limit", currency))
+		}
+		if !policy.MaxLoanAmount.IsZero() && policy.MinLoanAmount.Cmp(policy.MaxLoanAmount) > 0 {
+			errs = append(errs, fmt.Sprintf("Eligibility policy of %v has the minimum loan amount above the maximum", currency))
+		}
+	}
+
+	if len(errs) > 0 {
+		return errs
+	}
+
+	return nil
+}
+
+// SimulationError gives the reasons of a failed request, the declines keep their reasons and any other error is a simulation error
+func (l *LoanSimulation_usecase) SimulationError(requestIndex int, email string, err error) entities.SimulationError {
+	simulationError := entities.SimulationError{RequestIndex: requestIndex, Email: email}
+
+	var declined *DeclinedError
+	if errors.As(err, &declined) {
+		simulationError.Reasons = declined.Reasons
+	} else {
+		simulationError.Reasons = []entities.DeclineReason{{Code: entities.DeclineSimulationError, Message: err.Error()}}
+	}
+	return simulationError
+}
+
+// InvalidRequestError gives a reason for each validation message of the request
+func (l *LoanSimulation_usecase) InvalidRequestError(requestIndex int, email string, validations []string) entities.SimulationError {
+	simulationError := entities.SimulationError{RequestIndex: requestIndex, Email: email}
+	for _, validation := range validations {
+		simulationError.Reasons = append(simulationError.Reasons, entities.DeclineReason{Code: entities.DeclineInvalidRequest, Message: validation})
+	}
+	return simulationError
+}
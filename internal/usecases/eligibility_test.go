@@ -0,0 +1,135 @@
+package usecases_test
+
+import (
+	"errors"
+	"sort"
+	"testing"
+	"time"
+
+	"github.com/Jonattas-21/loan-engine/internal/api/dto"
+	"github.com/Jonattas-21/loan-engine/internal/domain/entities"
+	"github.com/Jonattas-21/loan-engine/internal/usecases"
+	"github.com/Jonattas-21/loan-engine/package/money"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+var eligibilityPolicies = []entities.EligibilityPolicy{
+	{MaxAgeAtMaturity: 80},
+	{Currency: "R$", MinLoanAmount: money.MustParse("500"), MaxLoanAmount: money.MustParse("1000000"), MinInstallmentAmount: money.MustParse("50")},
+}
+
+func declineCodes(err error) []string {
+	var declined *usecases.DeclinedError
+	if !errors.As(err, &declined) {
+		return nil
+	}
+	codes := []string{}
+	for _, reason := range declined.Reasons {
+		codes = append(codes, reason.Code)
+	}
+	return codes
+}
+
+func TestCalculateLoan_declined(t *testing.T) {
+	assert := assert.New(t)
+	setupSimulation()
+	mockLoanConditions()
+	loanSimulationUsecase.EligibilityPolicies = eligibilityPolicies
+
+	simulationRequest := dto.SimulationRequest_dto{
+		LoanAmount:   money.MustParse("400"),
+		Installments: 12,
+		BithDate:     time.Date(1990, 1, 1, 0, 0, 0, 0, time.UTC),
+		Currency:     "R$",
+	}
+
+	// every broken limit is a reason
+	_, err := loanSimulationUsecase.CalculateLoan(simulationRequest)
+	assert.Equal([]string{entities.DeclineAmountBelowMinimum, entities.DeclineInstallmentBelowMinimum}, declineCodes(err))
+
+	var declined *usecases.DeclinedError
+	assert.True(errors.As(err, &declined))
+	assert.Equal("500.00", declined.Reasons[0].Limit)
+	assert.Equal("400.00", declined.Reasons[0].Value)
+
+	// the limits of other currencies do not apply
+	simulationRequest.Currency = "U$"
+	_, err = loanSimulationUsecase.CalculateLoan(simulationRequest)
+	assert.NoError(err)
+}
+
+func TestCalculateLoan_ageAtMaturity(t *testing.T) {
+	assert := assert.New(t)
+	setupSimulation()
+	mockLoanConditions()
+	loanSimulationUsecase.EligibilityPolicies = []entities.EligibilityPolicy{{MaxAgeAtMaturity: 40}}
+
+	// the client is 18 to 40, with 30 years of installments the last one is due after 40
+	birthDate := time.Now().AddDate(-30, 0, -1)
+	simulationRequest := dto.SimulationRequest_dto{
+		LoanAmount:   money.MustParse("10000"),
+		Installments: 12,
+		BithDate:     birthDate,
+		Currency:     "R$",
+	}
+
+	_, err := loanSimulationUsecase.CalculateLoan(simulationRequest)
+	assert.NoError(err)
+
+	simulationRequest.Installments = 12 * 11
+	_, err = loanSimulationUsecase.CalculateLoan(simulationRequest)
+	assert.Equal([]string{entities.DeclineAgeAtMaturityAboveLimit}, declineCodes(err))
+}
+
+func TestCalculateLoan_noPricingRule(t *testing.T) {
+	assert := assert.New(t)
+	setupSimulation()
+	mockLoanConditions()
+
+	// the tiers only go up to 40
+	_, err := loanSimulationUsecase.CalculateLoan(dto.SimulationRequest_dto{
+		LoanAmount:   money.MustParse("10000"),
+		Installments: 12,
+		BithDate:     time.Now().AddDate(-50, 0, 0),
+		Currency:     "R$",
+	})
+	assert.Equal([]string{entities.DeclineNoPricingRule}, declineCodes(err))
+}
+
+func TestGetLoanSimulation_errorSimulations(t *testing.T) {
+	assert := assert.New(t)
+	setupSimulation()
+	mockLoanConditions()
+	mockCacheRepo.On("Get", mock.Anything).Return("", errors.New("not found"))
+	loanSimulationUsecase.EligibilityPolicies = eligibilityPolicies
+
+	simulations, _, errorSimulations := loanSimulationUsecase.GetLoanSimulation([]dto.SimulationRequest_dto{
+		{Email: "invalid@example.com", LoanAmount: money.MustParse("10000"), Installments: 12, Currency: "R$"},
+		{Email: "declined@example.com", LoanAmount: money.MustParse("2000000"), Installments: 12, BithDate: time.Date(1990, 1, 1, 0, 0, 0, 0, time.UTC), Currency: "R$"},
+	})
+
+	assert.Empty(simulations)
+	assert.Len(errorSimulations, 2)
+	sort.Slice(errorSimulations, func(i, j int) bool { return errorSimulations[i].RequestIndex < errorSimulations[j].RequestIndex })
+
+	assert.Equal("invalid@example.com", errorSimulations[0].Email)
+	assert.Equal([]entities.DeclineReason{{Code: entities.DeclineInvalidRequest, Message: "Birthdate is required"}}, errorSimulations[0].Reasons)
+
+	assert.Equal(1, errorSimulations[1].RequestIndex)
+	assert.Equal(entities.DeclineAmountAboveMaximum, errorSimulations[1].Reasons[0].Code)
+	assert.Equal("Loan amount must be up to R$ 1000000.00", errorSimulations[1].Reasons[0].Message)
+}
+
+func TestValidateEligibilityPolicies(t *testing.T) {
+	assert := assert.New(t)
+
+	assert.Nil(usecases.ValidateEligibilityPolicies(eligibilityPolicies))
+	assert.Equal([]string{
+		"Eligibility policy of every currency has a negative limit",
+		"Eligibility policy of U$ has the minimum loan amount above the maximum",
+	}, usecases.ValidateEligibilityPolicies([]entities.EligibilityPolicy{
+		{MaxAgeAtMaturity: -1},
+		{Currency: "U$", MinLoanAmount: money.MustParse("1000"), MaxLoanAmount: money.MustParse("100")},
+	}))
+}
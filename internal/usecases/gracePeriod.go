@@ -0,0 +1,99 @@
+package usecases
+
+import (
+	"math/big"
+	"time"
+
+	"github.com/Jonattas-21/loan-engine/internal/domain/entities"
+	"github.com/Jonattas-21/loan-engine/internal/domain/interfaces"
+	"github.com/Jonattas-21/loan-engine/package/money"
+)
+
+const (
+	GraceUnitMonths = "MONTHS"
+	GraceUnitDays   = "DAYS"
+
+	GraceInterestCapitalized = "CAPITALIZED"
+	GraceInterestPaid        = "PAID"
+
+	maxGraceMonths = 12
+	maxGraceDays   = 365
+)
+
+// GracePeriod (carência) delays the first amortization, the interest of the period is added to the balance or paid alone
+type GracePeriod struct {
+	Length   int
+	Unit     string
+	Interest string
+}
+
+// GraceInput carries what is needed to build the grace lines of the schedule
+type GraceInput struct {
+	GracePeriod         GracePeriod
+	LoanAmount          money.Money
+	MonthlyInterestRate *big.Rat
+	DisbursementDate    time.Time
+	PaymentDay          int
+	HolidayCalendar     interfaces.HolidayCalendar
+	Currency            string
+	Rounding            money.RoundingMode
+}
+
+// CreateGraceInstallments returns one line per grace month, or a single line for a grace in days,
+// with the balance at the end of the grace and the date the amortization starts counting from
+func CreateGraceInstallments(input GraceInput) ([]entities.Installment, money.Money, time.Time) {
+	if input.GracePeriod.Length <= 0 {
+		return nil, input.LoanAmount, input.DisbursementDate
+	}
+
+	return graceInstallments(input, GraceNominalDates(input.GracePeriod, input.DisbursementDate, input.PaymentDay))
+}
+
+// GraceNominalDates returns the contractual date of each grace line, before rolling to a business day
+func GraceNominalDates(gracePeriod GracePeriod, disbursementDate time.Time, paymentDay int) []time.Time {
+	if gracePeriod.Length <= 0 {
+		return nil
+	}
+	if gracePeriod.Unit == GraceUnitDays {
+		return []time.Time{disbursementDate.AddDate(0, 0, gracePeriod.Length)}
+	}
+	return NominalDueDates(disbursementDate, paymentDay, gracePeriod.Length)
+}
+
+// graceInstallments builds the grace lines on the nominal dates, the interest counts from the disbursement date of the input
+func graceInstallments(input GraceInput, nominalDates []time.Time) ([]entities.Installment, money.Money, time.Time) {
+	var installments []entities.Installment
+	balance := input.LoanAmount
+	periodStart := input.DisbursementDate
+
+	for i, nominalDate := range nominalDates {
+		rate := input.MonthlyInterestRate
+		if i == 0 {
+			rate = FirstPeriodRate(periodStart, nominalDate, input.MonthlyInterestRate)
+		}
+		interestAmount := balance.Mul(rate, input.Rounding)
+
+		installment := entities.Installment{
+			InstallmentNumber: i + 1,
+			Kind:              entities.InstallmentGrace,
+			DueDate:           NextBusinessDay(nominalDate, input.HolidayCalendar),
+			OpeningBalance:    balance,
+			ClosingBalance:    balance,
+			Currency:          input.Currency,
+		}
+
+		if input.GracePeriod.Interest == GraceInterestPaid {
+			installment.InstallmentAmount = interestAmount
+			installment.InstallmentFeeAmount = interestAmount
+		} else {
+			installment.CapitalizedInterest = interestAmount
+			installment.ClosingBalance = balance.Add(interestAmount)
+		}
+
+		installments = append(installments, installment)
+		balance = installment.ClosingBalance
+		periodStart = nominalDate
+	}
+
+	return installments, balance, periodStart
+}
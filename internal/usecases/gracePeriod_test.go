@@ -0,0 +1,104 @@
+package usecases_test
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/Jonattas-21/loan-engine/internal/api/dto"
+	"github.com/Jonattas-21/loan-engine/internal/domain/entities"
+	"github.com/Jonattas-21/loan-engine/internal/usecases"
+	"github.com/Jonattas-21/loan-engine/package/money"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCreateGraceInstallments_capitalized(t *testing.T) {
+	assert := assert.New(t)
+
+	installments, balance, amortizationStart := usecases.CreateGraceInstallments(usecases.GraceInput{
+		GracePeriod:         usecases.GracePeriod{Length: 2, Unit: usecases.GraceUnitMonths, Interest: usecases.GraceInterestCapitalized},
+		LoanAmount:          money.MustParse("10000"),
+		MonthlyInterestRate: big.NewRat(1, 100),
+		DisbursementDate:    date(2025, 1, 10),
+		PaymentDay:          10,
+		Currency:            "R$",
+	})
+
+	assert.Len(installments, 2)
+	assert.Equal(entities.InstallmentGrace, installments[0].Kind)
+	assert.True(installments[0].InstallmentAmount.IsZero())
+	assert.Equal("100.00", installments[0].CapitalizedInterest.String())
+	assert.Equal("101.00", installments[1].CapitalizedInterest.String())
+	assert.Equal(installments[0].ClosingBalance, installments[1].OpeningBalance)
+	assert.Equal("10201.00", balance.String())
+	assert.Equal(date(2025, 3, 10), amortizationStart)
+}
+
+func TestCreateGraceInstallments_paid(t *testing.T) {
+	assert := assert.New(t)
+
+	installments, balance, _ := usecases.CreateGraceInstallments(usecases.GraceInput{
+		GracePeriod:         usecases.GracePeriod{Length: 2, Unit: usecases.GraceUnitMonths, Interest: usecases.GraceInterestPaid},
+		LoanAmount:          money.MustParse("10000"),
+		MonthlyInterestRate: big.NewRat(1, 100),
+		DisbursementDate:    date(2025, 1, 10),
+		PaymentDay:          10,
+		Currency:            "R$",
+	})
+
+	// interest only installments, the balance does not grow
+	for _, installment := range installments {
+		assert.Equal("100.00", installment.InstallmentAmount.String())
+		assert.Equal(installment.InstallmentAmount, installment.InstallmentFeeAmount)
+		assert.True(installment.CapitalizedInterest.IsZero())
+	}
+	assert.Equal("10000.00", balance.String())
+}
+
+func TestCreateGraceInstallments_days(t *testing.T) {
+	assert := assert.New(t)
+
+	installments, balance, amortizationStart := usecases.CreateGraceInstallments(usecases.GraceInput{
+		GracePeriod:         usecases.GracePeriod{Length: 45, Unit: usecases.GraceUnitDays, Interest: usecases.GraceInterestCapitalized},
+		LoanAmount:          money.MustParse("10000"),
+		MonthlyInterestRate: big.NewRat(1, 100),
+		DisbursementDate:    date(2025, 1, 1),
+		Currency:            "R$",
+	})
+
+	// a single line for 45 days, (1.01)^1.5 - 1, due on monday after the saturday
+	assert.Len(installments, 1)
+	assert.Equal("150.37", installments[0].CapitalizedInterest.String())
+	assert.Equal(date(2025, 2, 17), installments[0].DueDate)
+	assert.Equal("10150.37", balance.String())
+	assert.Equal(date(2025, 2, 15), amortizationStart)
+}
+
+func TestCreateInstallments_gracePeriod(t *testing.T) {
+	assert := assert.New(t)
+	setupSimulation()
+
+	simulationRequest := dto.SimulationRequest_dto{
+		LoanAmount:       money.MustParse("10000"),
+		Installments:     6,
+		Currency:         "R$",
+		DisbursementDate: date(2025, 1, 10),
+		GracePeriod:      2,
+	}
+
+	installments, err := loanSimulationUsecase.CreateInstallments(simulationRequest, big.NewRat(1, 100))
+
+	assert.NoError(err)
+	assert.Len(installments, 8)
+	assert.Equal(entities.InstallmentGrace, installments[1].Kind)
+	assert.Equal(entities.InstallmentAmortization, installments[2].Kind)
+	assert.Equal(3, installments[2].InstallmentNumber)
+	assert.Equal(date(2025, 4, 10), installments[2].DueDate)
+
+	// the capitalized interest is amortized with the principal
+	assert.Equal("10201.00", installments[2].OpeningBalance.String())
+	assert.Equal(money.MustParse("10201"), sumPrincipal(installments))
+	assert.True(installments[7].ClosingBalance.IsZero())
+
+	simulationRequest.GracePeriod = 13
+	assert.Contains(loanSimulationUsecase.ValidateSimulationRequest(simulationRequest), "Grace period must be up to 12 months or 365 days")
+}
@@ -0,0 +1,149 @@
+package usecases
+
+import (
+	"fmt"
+	"math/big"
+	"strings"
+
+	"github.com/Jonattas-21/loan-engine/internal/api/dto"
+	"github.com/Jonattas-21/loan-engine/internal/domain/entities"
+	"github.com/Jonattas-21/loan-engine/package/money"
+)
+
+// ValidateInsuranceProducts checks the configured insurance catalog
+func ValidateInsuranceProducts(products []entities.InsuranceProduct) []string {
+	errs := []string{}
+	codes := map[string]bool{}
+	for _, product := range products {
+		code := strings.ToUpper(strings.TrimSpace(product.Code))
+		if code == "" {
+			errs = append(errs, "Insurance product code is required")
+		} else if codes[code] {
+			errs = append(errs, fmt.Sprintf("Insurance product %v is informed more than once", product.Code))
+		}
+		codes[code] = true
+
+		switch strings.ToUpper(product.PremiumType) {
+		case entities.InsurancePremiumBalance:
+			if product.Rate <= 0 || product.Rate >= 100 {
+				errs = append(errs, fmt.Sprintf("Insurance product %v rate is required above 0 and below 100 per month", product.Code))
+			}
+		case entities.InsurancePremiumMonthly:
+			if !product.MonthlyPremium.IsPositive() {
+				errs = append(errs, fmt.Sprintf("Insurance product %v monthly premium is required above 0", product.Code))
+			}
+		default:
+			errs = append(errs, fmt.Sprintf("Insurance product %v premium type must be BALANCE or MONTHLY", product.Code))
+		}
+	}
+
+	if len(errs) > 0 {
+		return errs
+	}
+
+	return nil
+}
+
+// ValidateInsurances checks the insurances selected by the request against the catalog
+func (l *LoanSimulation_usecase) ValidateInsurances(simulationRequest dto.SimulationRequest_dto) []string {
+	var errs []string
+
+	selected := map[string]bool{}
+	for _, code := range simulationRequest.Insurances {
+		if _, ok := l.insuranceProduct(code, simulationRequest.Currency); !ok {
+			errs = append(errs, fmt.Sprintf("Insurance %v is not offered in %v", code, simulationRequest.Currency))
+		} else if selected[strings.ToUpper(strings.TrimSpace(code))] {
+			errs = append(errs, fmt.Sprintf("Insurance %v is informed more than once", code))
+		}
+		selected[strings.ToUpper(strings.TrimSpace(code))] = true
+	}
+
+	if payment := l.PaymentModeName(simulationRequest.InsurancePayment); payment != entities.PaymentFinanced && payment != entities.PaymentInstallment {
+		errs = append(errs, "Insurance payment must be FINANCED or INSTALLMENT")
+	}
+
+	return errs
+}
+
+// InsuranceCharges are the insurance products selected by the request, paid as requested
+func (l *LoanSimulation_usecase) InsuranceCharges(simulationRequest dto.SimulationRequest_dto) []entities.InsuranceCharge {
+	var charges []entities.InsuranceCharge
+	for _, code := range simulationRequest.Insurances {
+		product, ok := l.insuranceProduct(code, simulationRequest.Currency)
+		if !ok {
+			continue
+		}
+		charges = append(charges, entities.InsuranceCharge{
+			Code:           product.Code,
+			Name:           product.Name,
+			PremiumType:    strings.ToUpper(product.PremiumType),
+			Rate:           product.Rate,
+			MonthlyPremium: product.MonthlyPremium,
+			Payment:        l.PaymentModeName(simulationRequest.InsurancePayment),
+		})
+	}
+	return charges
+}
+
+// FinanceInsurancePremiums sets the single premium of the financed insurances for the whole term and returns their sum
+// to be added to the loan. As if paid with the installments, the balance premiums are over the opening balance of each
+// installment of the schedule without the insurances, so both payment modes charge the same premiums.
+func FinanceInsurancePremiums(charges []entities.InsuranceCharge, installments []entities.Installment, rounding money.RoundingMode) money.Money {
+	financed := money.Zero()
+	for i, charge := range charges {
+		if charge.Payment != entities.PaymentFinanced {
+			continue
+		}
+		charges[i].PremiumAmount = money.Zero()
+		for _, installment := range installments {
+			if installment.Kind == entities.InstallmentGrace {
+				continue
+			}
+			charges[i].PremiumAmount = charges[i].PremiumAmount.Add(monthlyPremium(charge, installment.OpeningBalance, rounding))
+		}
+		financed = financed.Add(charges[i].PremiumAmount)
+	}
+	return financed
+}
+
+// ApplyInsurancePremiums adds the premiums of the insurances paid with the installments to each installment amortizing the loan,
+// over the opening balance of the balance premiums. Like the admin fees and the financed premiums, nothing is charged
+// during the grace period. It sets the premium of each insurance and returns their sum.
+func ApplyInsurancePremiums(installments []entities.Installment, charges []entities.InsuranceCharge, rounding money.RoundingMode) money.Money {
+	total := money.Zero()
+	for i, charge := range charges {
+		if charge.Payment != entities.PaymentInstallment {
+			continue
+		}
+		charges[i].PremiumAmount = money.Zero()
+		for j := range installments {
+			if installments[j].Kind == entities.InstallmentGrace {
+				continue
+			}
+			premium := monthlyPremium(charge, installments[j].OpeningBalance, rounding)
+			installments[j].InsuranceAmount = installments[j].InsuranceAmount.Add(premium)
+			installments[j].InstallmentAmount = installments[j].InstallmentAmount.Add(premium)
+			charges[i].PremiumAmount = charges[i].PremiumAmount.Add(premium)
+		}
+		total = total.Add(charges[i].PremiumAmount)
+	}
+	return total
+}
+
+// monthlyPremium is the premium of one month over the balance
+func monthlyPremium(charge entities.InsuranceCharge, balance money.Money, rounding money.RoundingMode) money.Money {
+	if charge.PremiumType == entities.InsurancePremiumMonthly {
+		return charge.MonthlyPremium
+	}
+	return balance.Mul(new(big.Rat).Quo(money.ExactRat(charge.Rate), big.NewRat(100, 1)), rounding)
+}
+
+// insuranceProduct finds the product of the catalog offered in the currency
+func (l *LoanSimulation_usecase) insuranceProduct(code string, currency string) (entities.InsuranceProduct, bool) {
+	for _, product := range l.InsuranceProducts {
+		if strings.EqualFold(strings.TrimSpace(product.Code), strings.TrimSpace(code)) && (product.Currency == "" || product.Currency == currency) {
+			return product, true
+		}
+	}
+	return entities.InsuranceProduct{}, false
+}
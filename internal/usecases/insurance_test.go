@@ -0,0 +1,184 @@
+package usecases_test
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/Jonattas-21/loan-engine/internal/api/dto"
+	"github.com/Jonattas-21/loan-engine/internal/domain/entities"
+	"github.com/Jonattas-21/loan-engine/internal/usecases"
+	"github.com/Jonattas-21/loan-engine/package/money"
+	"github.com/stretchr/testify/assert"
+)
+
+var insuranceProducts = []entities.InsuranceProduct{
+	{Code: "CREDIT_LIFE", Name: "Credit life", PremiumType: entities.InsurancePremiumBalance, Rate: 0.05},
+	{Code: "UNEMPLOYMENT", Name: "Unemployment", PremiumType: entities.InsurancePremiumMonthly, MonthlyPremium: money.MustParse("12.90"), Currency: "R$"},
+}
+
+// insuranceRequest simulates 10000 in 12 installments at the 3% per year of tier2
+func insuranceRequest(insurancePayment string, insurances ...string) dto.SimulationRequest_dto {
+	simulationRequest := ladderRequest()
+	simulationRequest.Installments = 12
+	simulationRequest.Insurances = insurances
+	simulationRequest.InsurancePayment = insurancePayment
+	return simulationRequest
+}
+
+func TestValidateInsuranceProducts(t *testing.T) {
+	assert := assert.New(t)
+
+	assert.Nil(usecases.ValidateInsuranceProducts(insuranceProducts))
+	assert.Equal([]string{
+		"Insurance product CREDIT_LIFE rate is required above 0 and below 100 per month",
+		"Insurance product credit_life is informed more than once",
+		"Insurance product credit_life monthly premium is required above 0",
+		"Insurance product code is required",
+		"Insurance product  premium type must be BALANCE or MONTHLY",
+	}, usecases.ValidateInsuranceProducts([]entities.InsuranceProduct{
+		{Code: "CREDIT_LIFE", PremiumType: entities.InsurancePremiumBalance},
+		{Code: "credit_life", PremiumType: entities.InsurancePremiumMonthly},
+		{PremiumType: "ONCE"},
+	}))
+}
+
+func TestValidateInsurances(t *testing.T) {
+	assert := assert.New(t)
+	setupSimulation()
+	loanSimulationUsecase.InsuranceProducts = insuranceProducts
+
+	assert.Empty(loanSimulationUsecase.ValidateInsurances(insuranceRequest("installment", "credit_life", "UNEMPLOYMENT")))
+
+	simulationRequest := insuranceRequest("UPFRONT", "CREDIT_LIFE", "CREDIT_LIFE", "UNEMPLOYMENT", "TRAVEL")
+	simulationRequest.Currency = "U$"
+	assert.Equal([]string{
+		"Insurance CREDIT_LIFE is informed more than once",
+		"Insurance UNEMPLOYMENT is not offered in U$",
+		"Insurance TRAVEL is not offered in U$",
+		"Insurance payment must be FINANCED or INSTALLMENT",
+	}, loanSimulationUsecase.ValidateInsurances(simulationRequest))
+}
+
+func TestCalculateLoan_insurancePerInstallment(t *testing.T) {
+	assert := assert.New(t)
+	setupSimulation()
+	mockLoanConditions()
+	loanSimulationUsecase.InsuranceProducts = insuranceProducts
+
+	withoutInsurance, err := loanSimulationUsecase.CalculateLoan(insuranceRequest(""))
+	assert.NoError(err)
+	loanSimulation, err := loanSimulationUsecase.CalculateLoan(insuranceRequest(entities.PaymentInstallment, "CREDIT_LIFE", "UNEMPLOYMENT"))
+	assert.NoError(err)
+
+	// the credit is the same, the premiums are paid on top of each installment
+	assert.Equal(withoutInsurance.FinancedAmount, loanSimulation.FinancedAmount)
+	assert.Equal(withoutInsurance.AmountFeeTobePaid, loanSimulation.AmountFeeTobePaid)
+	assert.Len(loanSimulation.Insurances, 2)
+
+	premiums := money.Zero()
+	for i, installment := range loanSimulation.Installments {
+		creditLife := installment.OpeningBalance.Mul(money.ExactRat(0.0005), money.HalfEven)
+		assert.Equal(creditLife.Add(money.MustParse("12.90")), installment.InsuranceAmount)
+		assert.Equal(withoutInsurance.Installments[i].InstallmentAmount.Add(installment.InsuranceAmount), installment.InstallmentAmount)
+		premiums = premiums.Add(installment.InsuranceAmount)
+	}
+	assert.Equal(premiums, loanSimulation.InsuranceAmount)
+	assert.Equal("154.80", loanSimulation.Insurances[1].PremiumAmount.String())
+	assert.Equal(withoutInsurance.AmountTobePaid.Add(premiums), loanSimulation.AmountTobePaid)
+	assert.Greater(loanSimulation.EffectiveAnnualRate, withoutInsurance.EffectiveAnnualRate)
+}
+
+func TestCalculateLoan_insuranceFinanced(t *testing.T) {
+	assert := assert.New(t)
+	setupSimulation()
+	mockLoanConditions()
+	loanSimulationUsecase.InsuranceProducts = insuranceProducts
+
+	withoutInsurance, err := loanSimulationUsecase.CalculateLoan(insuranceRequest(""))
+	assert.NoError(err)
+	loanSimulation, err := loanSimulationUsecase.CalculateLoan(insuranceRequest("", "CREDIT_LIFE", "UNEMPLOYMENT"))
+	assert.NoError(err)
+
+	// single premiums for the 12 months: 0.05% of the declining balance of 10000 and 12.90 a month
+	assert.Equal("32.63", loanSimulation.Insurances[0].PremiumAmount.String())
+	assert.Equal("154.80", loanSimulation.Insurances[1].PremiumAmount.String())
+	assert.Equal("187.43", loanSimulation.InsuranceAmount.String())
+	assert.Equal("10000.00", loanSimulation.LoanAmount.String())
+
+	// the premiums are lent with the loan amount, the IOF over both
+	assert.Equal(loanSimulation.FinancedAmount, money.MustParse("10187.43").Add(loanSimulation.IOFAmount))
+	assert.Equal(1, loanSimulation.IOFAmount.Cmp(withoutInsurance.IOFAmount))
+	for _, installment := range loanSimulation.Installments {
+		assert.True(installment.InsuranceAmount.IsZero())
+	}
+	assert.Greater(loanSimulation.EffectiveAnnualRate, withoutInsurance.EffectiveAnnualRate)
+}
+
+func TestCalculateLoan_insurancePaymentModesAgree(t *testing.T) {
+	assert := assert.New(t)
+	setupSimulation()
+	mockLoanConditions()
+	loanSimulationUsecase.InsuranceProducts = insuranceProducts
+
+	// with the IOF paid upfront both modes amortize the same balances before the premiums
+	simulationRequest := insuranceRequest(entities.PaymentInstallment, "CREDIT_LIFE", "UNEMPLOYMENT")
+	simulationRequest.IOFPayment = entities.PaymentUpfront
+	perInstallment, err := loanSimulationUsecase.CalculateLoan(simulationRequest)
+	assert.NoError(err)
+	simulationRequest.InsurancePayment = entities.PaymentFinanced
+	financed, err := loanSimulationUsecase.CalculateLoan(simulationRequest)
+	assert.NoError(err)
+
+	for i := range financed.Insurances {
+		assert.Equal(perInstallment.Insurances[i].PremiumAmount, financed.Insurances[i].PremiumAmount)
+	}
+	assert.Equal(perInstallment.InsuranceAmount, financed.InsuranceAmount)
+}
+
+func TestCalculateLoan_insuranceDuringGrace(t *testing.T) {
+	assert := assert.New(t)
+	setupSimulation()
+	mockLoanConditions()
+	loanSimulationUsecase.InsuranceProducts = insuranceProducts
+
+	simulationRequest := insuranceRequest(entities.PaymentInstallment, "UNEMPLOYMENT")
+	simulationRequest.GracePeriod = 2
+	loanSimulation, err := loanSimulationUsecase.CalculateLoan(simulationRequest)
+	assert.NoError(err)
+
+	// as the admin fees, the premiums are paid with the installments amortizing the loan only
+	assert.Len(loanSimulation.Installments, 14)
+	for _, installment := range loanSimulation.Installments[:2] {
+		assert.True(installment.InsuranceAmount.IsZero())
+		assert.True(installment.InstallmentAmount.IsZero())
+	}
+	for _, installment := range loanSimulation.Installments[2:] {
+		assert.Equal("12.90", installment.InsuranceAmount.String())
+	}
+
+	// the same 12 months of a financed premium
+	assert.Equal("154.80", loanSimulation.Insurances[0].PremiumAmount.String())
+}
+
+func TestSimulatePrepayment_insurancePerInstallment(t *testing.T) {
+	assert := assert.New(t)
+	setupSimulation()
+	mockLoanConditions()
+	loanSimulationUsecase.InsuranceProducts = insuranceProducts
+
+	loanSimulation, err := loanSimulationUsecase.CalculateLoan(insuranceRequest(entities.PaymentInstallment, "UNEMPLOYMENT"))
+	assert.NoError(err)
+	jsonSimulation, _ := json.Marshal(loanSimulation)
+	mockCacheRepo.On("Get", loanSimulationUsecase.SimulationIDCacheKey(loanSimulation.ID)).Return(string(jsonSimulation), nil)
+
+	prepayment, validations, err := loanSimulationUsecase.SimulatePrepayment(loanSimulation.ID, "",
+		dto.PrepaymentRequest_dto{Kind: entities.PrepaymentPartial, Amount: money.MustParse("2000"), AtInstallment: 3})
+
+	assert.NoError(err)
+	assert.Nil(validations)
+	for _, installment := range prepayment.Installments {
+		assert.Equal("12.90", installment.InsuranceAmount.String())
+		assert.Equal(installment.PrincipalAmount.Add(installment.InstallmentFeeAmount).Add(installment.InsuranceAmount), installment.InstallmentAmount)
+	}
+	assert.True(prepayment.DiscountedInterest.IsPositive())
+}
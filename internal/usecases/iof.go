@@ -0,0 +1,49 @@
+package usecases
+
+import (
+	"math/big"
+	"time"
+
+	"github.com/Jonattas-21/loan-engine/internal/domain/entities"
+	"github.com/Jonattas-21/loan-engine/package/money"
+)
+
+// IOFCalculator calculates the IOF tax of a credit operation: a fixed rate over the amount plus
+// a daily rate over each amortized principal, counting the days until its due date up to MaxDays
+type IOFCalculator struct {
+	FixedRate *big.Rat
+	DailyRate *big.Rat
+	MaxDays   int
+}
+
+// DefaultIOFCalculator uses the consumer credit rates, 0.38% fixed and 0.0082% per day capped at 365 days
+func DefaultIOFCalculator() *IOFCalculator {
+	return &IOFCalculator{
+		FixedRate: big.NewRat(38, 10000),
+		DailyRate: big.NewRat(82, 1000000),
+		MaxDays:   365,
+	}
+}
+
+// Calculate sums the IOF of every installment principal, the total is rounded once at the end
+func (i *IOFCalculator) Calculate(disbursementDate time.Time, installments []entities.Installment, rounding money.RoundingMode) money.Money {
+	// both rates over the same denominator, so every installment only adds integers
+	denominator := new(big.Int).Mul(i.FixedRate.Denom(), i.DailyRate.Denom())
+	fixedRate := new(big.Int).Mul(i.FixedRate.Num(), i.DailyRate.Denom())
+	dailyRate := new(big.Int).Mul(i.DailyRate.Num(), i.FixedRate.Denom())
+
+	total := new(big.Int)
+	for _, installment := range installments {
+		days := daysBetween(disbursementDate, installment.DueDate)
+		if days > i.MaxDays {
+			days = i.MaxDays
+		}
+
+		rate := new(big.Int).Mul(dailyRate, big.NewInt(int64(days)))
+		rate.Add(rate, fixedRate)
+		total.Add(total, rate.Mul(rate, big.NewInt(installment.PrincipalAmount.Cents())))
+	}
+
+	// total is in cents
+	return money.FromCents(1).MulFraction(total, denominator, rounding)
+}
@@ -0,0 +1,42 @@
+package usecases_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/Jonattas-21/loan-engine/internal/domain/entities"
+	"github.com/Jonattas-21/loan-engine/internal/usecases"
+	"github.com/Jonattas-21/loan-engine/package/money"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestIOFCalculator_Calculate(t *testing.T) {
+	assert := assert.New(t)
+	calculator := usecases.DefaultIOFCalculator()
+	disbursementDate := time.Date(2025, 1, 15, 0, 0, 0, 0, time.UTC)
+
+	installments := []entities.Installment{
+		{InstallmentNumber: 1, DueDate: time.Date(2025, 2, 15, 0, 0, 0, 0, time.UTC), PrincipalAmount: money.MustParse("1000")}, // 31 days
+		{InstallmentNumber: 2, DueDate: time.Date(2025, 3, 17, 0, 0, 0, 0, time.UTC), PrincipalAmount: money.MustParse("1000")}, // 61 days
+	}
+
+	iof := calculator.Calculate(disbursementDate, installments, money.HalfEven)
+
+	// 1000 * (0.38% + 31 * 0.0082%) + 1000 * (0.38% + 61 * 0.0082%)
+	assert.Equal("15.14", iof.String())
+}
+
+func TestIOFCalculator_capsDays(t *testing.T) {
+	assert := assert.New(t)
+	calculator := usecases.DefaultIOFCalculator()
+	disbursementDate := time.Date(2025, 1, 15, 0, 0, 0, 0, time.UTC)
+
+	installments := []entities.Installment{
+		{InstallmentNumber: 24, DueDate: time.Date(2027, 1, 15, 0, 0, 0, 0, time.UTC), PrincipalAmount: money.MustParse("1000")},
+	}
+
+	iof := calculator.Calculate(disbursementDate, installments, money.HalfEven)
+
+	// 1000 * (0.38% + 365 * 0.0082%), the daily part stops after one year
+	assert.Equal("33.73", iof.String())
+}
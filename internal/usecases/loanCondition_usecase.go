@@ -2,6 +2,7 @@ package usecases
 
 import (
 	"fmt"
+	"strings"
 	"time"
 
 	"encoding/json"
@@ -9,21 +10,33 @@ import (
 	"github.com/Jonattas-21/loan-engine/internal/api/dto"
 	"github.com/Jonattas-21/loan-engine/internal/domain/entities"
 	"github.com/Jonattas-21/loan-engine/internal/domain/interfaces"
+	"github.com/Jonattas-21/loan-engine/package/ulid"
 	"golang.org/x/exp/slices"
 )
 
+// the cache keeps every version of the conditions, the ones in force are picked by date
+const loanConditionsCacheKey = "loan_conditions"
+
 type LoanCondition interface {
-	SetLoanCondition(loanConditionDto dto.LoanConditionRequest_dto) (error, []string)
+	SetLoanCondition(loanConditionDto dto.LoanConditionRequest_dto, author string) (error, []string)
+	ReplaceLoanConditions(loanConditionsDto dto.LoanConditionsRequest_dto, author string) (error, []string)
+	DeleteLoanCondition(name string, mergeInto string, effectiveFrom time.Time, author string) (error, []string)
 	GetLoanConditions() ([]entities.LoanCondition, error)
+	GetLoanConditionsAt(date time.Time) ([]entities.LoanCondition, error)
+	GetLoanConditionVersions(name string) ([]entities.LoanCondition, error)
 }
 
 type LoanCondition_usecase struct {
 	LoanConditionRepository interfaces.Repository[entities.LoanCondition]
 	CacheRepository         interfaces.CacheRepository
 	Logger                  interfaces.Log
+	MinSupportedAge         int // youngest age covered by the tiers, 18 when not informed
+	MaxSupportedAge         int // oldest age covered by the tiers, 100 when not informed
 }
 
-func (l *LoanCondition_usecase) SetLoanCondition(loanConditionDto dto.LoanConditionRequest_dto) (error, []string) {
+// SetLoanCondition creates a tier or a new version of it from the effective date, the current version is closed on that date.
+// The ages are kept when not informed, the tiers must still cover the supported ages with no overlap.
+func (l *LoanCondition_usecase) SetLoanCondition(loanConditionDto dto.LoanConditionRequest_dto, author string) (error, []string) {
 
 	// Validating loan condition
 	errs := l.ValidadeLoanCondition(loanConditionDto)
@@ -32,49 +45,272 @@ func (l *LoanCondition_usecase) SetLoanCondition(loanConditionDto dto.LoanCondit
 		return nil, errs
 	}
 
-	// Converting dto to entity, there is no need of a automapper here, yet.
-	LoanCondition := entities.LoanCondition{
+	current, err := l.openLoanConditions()
+	if err != nil {
+		return err, nil
+	}
+
+	// Converting dto to entity, there is no need of a automapper here, yet
+	condition := entities.LoanCondition{
 		Name:         loanConditionDto.Name,
 		InterestRate: loanConditionDto.InterestRate,
-		MaxAge:       loanConditionDto.MaxAge,
 		MinAge:       loanConditionDto.MinAge,
+		MaxAge:       loanConditionDto.MaxAge,
 	}
 
-	// Update in mongoDB
-	fieldsFrom := make(map[string]interface{})
+	found := false
+	target := []entities.LoanCondition{}
+	for _, currentCondition := range current {
+		if currentCondition.Name == condition.Name {
+			found = true
+			if condition.MinAge == 0 && condition.MaxAge == 0 {
+				condition.MinAge, condition.MaxAge = currentCondition.MinAge, currentCondition.MaxAge
+			}
+			currentCondition = condition
+		}
+		target = append(target, currentCondition)
+	}
+	if !found {
+		target = append(target, condition)
+	}
 
-	fieldsFrom["interestrate"] = LoanCondition.InterestRate
+	return l.applyLoanConditions(current, target, loanConditionDto.EffectiveFrom, author)
+}
 
-	// We can use this way to update all fields, but it's not necessary yet
-	// fieldsFrom["name"] = LoanCondition.Name
-	// fieldsFrom["maxage"] = LoanCondition.MaxAge
-	// fieldsFrom["minage"] = LoanCondition.MinAge
+// ReplaceLoanConditions sets the whole set of tiers from the effective date, so bands can be split, merged and reshaped at once.
+// The tiers left out are closed, nothing is written when the new set is invalid.
+func (l *LoanCondition_usecase) ReplaceLoanConditions(loanConditionsDto dto.LoanConditionsRequest_dto, author string) (error, []string) {
+	errs := []string{}
+	target := []entities.LoanCondition{}
+	for _, loanConditionDto := range loanConditionsDto.LoanConditions {
+		errs = append(errs, l.ValidadeLoanCondition(loanConditionDto)...)
+		target = append(target, entities.LoanCondition{
+			Name:         loanConditionDto.Name,
+			InterestRate: loanConditionDto.InterestRate,
+			MinAge:       loanConditionDto.MinAge,
+			MaxAge:       loanConditionDto.MaxAge,
+		})
+	}
+	if len(errs) > 0 {
+		l.Logger.Errorln("Error validating loan conditions: ", errs)
+		return nil, errs
+	}
 
-	err := l.LoanConditionRepository.UpdateItemCollection(LoanCondition.Name, fieldsFrom)
+	current, err := l.openLoanConditions()
 	if err != nil {
-		l.Logger.Errorln("Error found updating loan condition: ", err.Error())
 		return err, nil
 	}
 
-	// Save in cache, if not, let's just log the error and continue
-	jsonConditions, err := json.Marshal(LoanCondition)
+	return l.applyLoanConditions(current, target, loanConditionsDto.EffectiveFrom, author)
+}
+
+// DeleteLoanCondition closes the tier from the effective date, its ages go to the mergeInto tier when informed.
+// The tier versions are kept, the simulations still reference them.
+func (l *LoanCondition_usecase) DeleteLoanCondition(name string, mergeInto string, effectiveFrom time.Time, author string) (error, []string) {
+	current, err := l.openLoanConditions()
 	if err != nil {
-		l.Logger.Errorln("Error marshalling loan conditions: ", err.Error())
-	} else {
-		err = l.CacheRepository.Set("loan_conditions", jsonConditions, time.Minute*10)
+		return err, nil
+	}
+
+	var deleted *entities.LoanCondition
+	target := []entities.LoanCondition{}
+	for i, condition := range current {
+		if condition.Name == name {
+			deleted = &current[i]
+			continue
+		}
+		target = append(target, condition)
+	}
+	if deleted == nil {
+		return nil, []string{fmt.Sprintf("Loan condition %v not found", name)}
+	}
+
+	if mergeInto != "" {
+		index := slices.IndexFunc(target, func(condition entities.LoanCondition) bool { return condition.Name == mergeInto })
+		if index < 0 {
+			return nil, []string{fmt.Sprintf("Loan condition %v not found", mergeInto)}
+		}
+		target[index].MinAge = min(target[index].MinAge, deleted.MinAge)
+		target[index].MaxAge = max(target[index].MaxAge, deleted.MaxAge)
+	}
+
+	return l.applyLoanConditions(current, target, effectiveFrom, author)
+}
+
+// GetLoanConditionVersions returns every version of the tier, the oldest first
+func (l *LoanCondition_usecase) GetLoanConditionVersions(name string) ([]entities.LoanCondition, error) {
+	versions, err := l.LoanConditionRepository.Find(interfaces.Query{
+		Filter: interfaces.Where(interfaces.Eq("name", name)),
+		Sort:   []interfaces.SortField{{Field: "version"}},
+	})
+	if err != nil {
+		l.Logger.Errorln("Error getting loan condition versions: ", err.Error())
+		return nil, fmt.Errorf("error getting loan condition versions: %w", err)
+	}
+	return versions, nil
+}
+
+// openLoanConditions are the current version of each tier, the ones without an end
+func (l *LoanCondition_usecase) openLoanConditions() ([]entities.LoanCondition, error) {
+	current, err := l.LoanConditionRepository.Find(interfaces.Query{Filter: interfaces.Where(interfaces.Eq("effectiveto", nil))})
+	if err != nil {
+		l.Logger.Errorln("Error found getting loan conditions: ", err.Error())
+		return nil, fmt.Errorf("error getting current loan conditions: %w", err)
+	}
+	return current, nil
+}
+
+// applyLoanConditions makes the target the set of tiers from the effective date: the changed and new tiers get a new version
+// and the current version of the changed and removed ones is closed. The whole set is validated before the first write,
+// an invalid set is rejected with no change. The effective date can't be in the past, the simulations already priced keep their rates,
+// an earlier time of today (like a date without time) is effective now.
+func (l *LoanCondition_usecase) applyLoanConditions(current []entities.LoanCondition, target []entities.LoanCondition, effectiveFrom time.Time, author string) (error, []string) {
+	now := time.Now()
+	if effectiveFrom.IsZero() || (effectiveFrom.Before(now) && effectiveFrom.Format("2006-01-02") == now.In(effectiveFrom.Location()).Format("2006-01-02")) {
+		effectiveFrom = now
+	}
+	if author == "" {
+		author = "anonymous"
+	}
+
+	errs := []string{}
+	if effectiveFrom.Before(now) {
+		errs = append(errs, "Effective from must not be in the past")
+	}
+	if bandErrs := l.ValidateAgeBands(target); bandErrs != nil {
+		errs = append(errs, bandErrs...)
+	}
+
+	currentByName := map[string]entities.LoanCondition{}
+	for _, condition := range current {
+		currentByName[condition.Name] = condition
+	}
+
+	// the current versions to close, the changed and the removed tiers
+	toClose := []entities.LoanCondition{}
+	toSave := []entities.LoanCondition{}
+	targetNames := map[string]bool{}
+	for _, condition := range target {
+		targetNames[condition.Name] = true
+		currentCondition, found := currentByName[condition.Name]
+		if found && currentCondition.InterestRate == condition.InterestRate &&
+			currentCondition.MinAge == condition.MinAge && currentCondition.MaxAge == condition.MaxAge {
+			continue
+		}
+
+		condition.Version = 1
+		if found {
+			condition.Version = currentCondition.Version + 1
+			toClose = append(toClose, currentCondition)
+		}
+		toSave = append(toSave, condition)
+	}
+	for _, condition := range current {
+		if !targetNames[condition.Name] {
+			toClose = append(toClose, condition)
+		}
+	}
+
+	for _, condition := range toClose {
+		if !effectiveFrom.After(condition.EffectiveFrom) {
+			errs = append(errs, fmt.Sprintf("Effective from must be after the effective from of the current version of %v", condition.Name))
+		}
+	}
+
+	if len(errs) > 0 {
+		l.Logger.Errorln("Error validating loan conditions: ", errs)
+		return nil, errs
+	}
+
+	// There is no transaction: the new versions are saved first and the current ones closed after, so a failure
+	// halfway leaves the current versions in force, and what was written is reverted.
+	saved := []string{}
+	for _, condition := range toSave {
+		var err error
+		condition.ID, err = ulid.New(now)
+		if err != nil {
+			l.revertLoanConditions(saved, nil)
+			return err, nil
+		}
+		condition.EffectiveFrom = effectiveFrom
+		condition.Author = author
+		condition.ModifiedDate = now
+
+		err = l.LoanConditionRepository.SaveItemCollection(condition)
 		if err != nil {
-			l.Logger.Errorln("Error setting loan conditions in cache: ", err.Error())
+			l.Logger.Errorln("Error found saving loan condition version: ", err.Error())
+			l.revertLoanConditions(saved, nil)
+			return err, nil
 		}
+		saved = append(saved, condition.ID)
+	}
+
+	closed := []entities.LoanCondition{}
+	for _, condition := range toClose {
+		condition.EffectiveTo = &effectiveFrom
+		err := l.LoanConditionRepository.Upsert(interfaces.Where(interfaces.Eq("id", condition.ID)), condition)
+		if err != nil {
+			l.Logger.Errorln("Error found closing loan condition version: ", err.Error())
+			l.revertLoanConditions(saved, closed)
+			return err, nil
+		}
+		closed = append(closed, condition)
+	}
+
+	// Refresh the cache, if not, let's just log the error and continue
+	_, err := l.loadLoanConditionVersions()
+	if err != nil {
+		l.Logger.Errorln("Error refreshing loan conditions in cache: ", err.Error())
 	}
 
 	return nil, nil
 }
 
+// revertLoanConditions undoes a change that failed halfway: the saved versions are removed and the closed ones opened again
+func (l *LoanCondition_usecase) revertLoanConditions(saved []string, closed []entities.LoanCondition) {
+	for _, condition := range closed {
+		condition.EffectiveTo = nil
+		err := l.LoanConditionRepository.Upsert(interfaces.Where(interfaces.Eq("id", condition.ID)), condition)
+		if err != nil {
+			l.Logger.Errorln("Error found reopening loan condition version ", condition.ID, ": ", err.Error())
+		}
+	}
+
+	if len(saved) > 0 {
+		err := l.LoanConditionRepository.Delete(interfaces.Where(interfaces.In("id", saved)))
+		if err != nil {
+			l.Logger.Errorln("Error found removing loan condition versions ", saved, ": ", err.Error())
+		}
+	}
+}
+
+// GetLoanConditions returns the conditions in force now
 func (l *LoanCondition_usecase) GetLoanConditions() ([]entities.LoanCondition, error) {
+	return l.GetLoanConditionsAt(time.Now())
+}
+
+// GetLoanConditionsAt returns the version of each tier in force at the date
+func (l *LoanCondition_usecase) GetLoanConditionsAt(date time.Time) ([]entities.LoanCondition, error) {
+	versions, err := l.loanConditionVersions()
+	if err != nil {
+		return nil, err
+	}
+
+	conditions := []entities.LoanCondition{}
+	for _, version := range versions {
+		if version.InForce(date) {
+			conditions = append(conditions, version)
+		}
+	}
+	return conditions, nil
+}
+
+// loanConditionVersions returns every version of the conditions, from the cache when there
+func (l *LoanCondition_usecase) loanConditionVersions() ([]entities.LoanCondition, error) {
 	loanConditions := []entities.LoanCondition{}
 
 	// Check if we have the loan conditions in cache
-	val, err := l.CacheRepository.Get("loan_conditions")
+	val, err := l.CacheRepository.Get(loanConditionsCacheKey)
 	if err == nil {
 		err = json.Unmarshal([]byte(val), &loanConditions)
 		if err != nil {
@@ -85,8 +321,12 @@ func (l *LoanCondition_usecase) GetLoanConditions() ([]entities.LoanCondition, e
 		}
 	}
 
-	// If we don't have the loan conditions in cache, let's get it from mongoDB
-	conditions, err := l.LoanConditionRepository.GetItemsCollection("loan_conditions")
+	return l.loadLoanConditionVersions()
+}
+
+// loadLoanConditionVersions reads every version of the conditions from mongoDB and keeps them in cache
+func (l *LoanCondition_usecase) loadLoanConditionVersions() ([]entities.LoanCondition, error) {
+	conditions, err := l.LoanConditionRepository.GetItemsCollection(loanConditionsCacheKey)
 	l.Logger.Infoln(fmt.Printf("Conditions: %v", conditions))
 	if err != nil {
 		l.Logger.Errorln("Error getting loan conditions: ", err.Error())
@@ -99,7 +339,7 @@ func (l *LoanCondition_usecase) GetLoanConditions() ([]entities.LoanCondition, e
 		if err != nil {
 			l.Logger.Errorln("Error marshalling loan conditions: ", err.Error())
 		} else {
-			err = l.CacheRepository.Set("loan_conditions", jsonConditions, time.Minute*10)
+			err = l.CacheRepository.Set(loanConditionsCacheKey, jsonConditions, time.Minute*10)
 			if err != nil {
 				l.Logger.Errorln("Error setting loan conditions in cache: ", err.Error())
 			}
@@ -110,65 +350,79 @@ func (l *LoanCondition_usecase) GetLoanConditions() ([]entities.LoanCondition, e
 	return conditions, nil
 }
 
-func (l *LoanCondition_usecase) InitLoanEngineConditionsData() error {
-
-	err := l.LoanConditionRepository.TrunkCollection()
-	if err != nil {
-		l.Logger.Errorln("Error truncating loan conditions collection: ", err.Error())
-		return fmt.Errorf("error truncating loan conditions collection: %w", err)
+// LoanConditionMigrations are the data migrations of the conditions, the seeds are inserted in an empty collection
+func (l *LoanCondition_usecase) LoanConditionMigrations(seeds []entities.LoanCondition) []Migration {
+	return []Migration{
+		{Version: MigrationSeedLoanConditions, Name: "seed loan conditions", Up: func() error { return l.SeedLoanConditions(seeds) }},
+		{Version: MigrationVersionLoanConditions, Name: "version loan conditions saved before the versioning", Up: l.VersionUnversionedLoanConditions},
 	}
+}
 
-	err = l.LoanConditionRepository.SaveItemCollection(entities.LoanCondition{
-		Name:         "tier1",
-		InterestRate: 5,
-		MinAge:       18,
-		MaxAge:       25,
-		ModifiedDate: time.Now(),
-	})
+// SeedLoanConditions inserts the first version of each tier, only when there is no condition saved
+func (l *LoanCondition_usecase) SeedLoanConditions(seeds []entities.LoanCondition) error {
+	count, err := l.LoanConditionRepository.Count(interfaces.Filter{})
 	if err != nil {
-		l.Logger.Errorln("Error saving default loan condition for tier 1:", err.Error())
-		return fmt.Errorf("Error saving default loan condition for tier 1: %w", err)
+		l.Logger.Errorln("Error counting loan conditions: ", err.Error())
+		return fmt.Errorf("error counting loan conditions: %w", err)
+	}
+	if count > 0 {
+		l.Logger.Infoln(fmt.Sprintf("Loan conditions already saved (%v), skipping the seeds", count))
+		return nil
 	}
 
-	err = l.LoanConditionRepository.SaveItemCollection(entities.LoanCondition{
-		Name:         "tier2",
-		InterestRate: 3,
-		MinAge:       26,
-		MaxAge:       40,
-		ModifiedDate: time.Now(),
-	})
-	if err != nil {
-		l.Logger.Errorln("Error saving default loan condition for tier 2:", err.Error())
-		return fmt.Errorf("error saving default loan condition for tier 2: %w", err)
+	// the first version of each tier is in force from now
+	now := time.Now()
+	for _, condition := range seeds {
+		condition.ID, err = ulid.New(now)
+		if err != nil {
+			return err
+		}
+		condition.Version = 1
+		condition.EffectiveFrom = now
+		condition.EffectiveTo = nil
+		condition.Author = "system"
+		condition.ModifiedDate = now
+
+		err = l.LoanConditionRepository.SaveItemCollection(condition)
+		if err != nil {
+			l.Logger.Errorln(fmt.Sprintf("Error saving default loan condition for %v:", condition.Name), err.Error())
+			return fmt.Errorf("error saving default loan condition for %v: %w", condition.Name, err)
+		}
 	}
 
-	err = l.LoanConditionRepository.SaveItemCollection(entities.LoanCondition{
-		Name:         "tier3",
-		InterestRate: 2,
-		MinAge:       41,
-		MaxAge:       60,
-		ModifiedDate: time.Now(),
-	})
+	return nil
+}
+
+// VersionUnversionedLoanConditions makes the conditions saved before the versioning their first version,
+// in force since they were modified
+func (l *LoanCondition_usecase) VersionUnversionedLoanConditions() error {
+	unversionedFilter := interfaces.Where(interfaces.Eq("version", nil))
+	conditions, err := l.LoanConditionRepository.Find(interfaces.Query{Filter: unversionedFilter})
 	if err != nil {
-		l.Logger.Errorln("Error saving default loan condition for tier 3:", err.Error())
-		return fmt.Errorf("error saving default loan condition for tier 3: %w", err)
+		l.Logger.Errorln("Error getting unversioned loan conditions: ", err.Error())
+		return fmt.Errorf("error getting unversioned loan conditions: %w", err)
 	}
 
-	err = l.LoanConditionRepository.SaveItemCollection(entities.LoanCondition{
-		Name:         "tier4",
-		InterestRate: 4,
-		MinAge:       61,
-		MaxAge:       100,
-		ModifiedDate: time.Now(),
-	})
-	if err != nil {
-		l.Logger.Errorln("Error saving default loan condition for tier 4:", err.Error())
-		return fmt.Errorf("error saving default loan condition for tier 4: %w", err)
+	for _, condition := range conditions {
+		condition.ID, err = ulid.New(condition.ModifiedDate)
+		if err != nil {
+			return err
+		}
+		condition.Version = 1
+		condition.EffectiveFrom = condition.ModifiedDate
+		condition.Author = "system"
+
+		err = l.LoanConditionRepository.Upsert(unversionedFilter.And(interfaces.Eq("name", condition.Name)), condition)
+		if err != nil {
+			l.Logger.Errorln(fmt.Sprintf("Error versioning loan condition %v:", condition.Name), err.Error())
+			return fmt.Errorf("error versioning loan condition %v: %w", condition.Name, err)
+		}
 	}
 
 	return nil
 }
 
+// ValidadeLoanCondition checks a single tier, the set of tiers is checked by ValidateAgeBands
 func (l *LoanCondition_usecase) ValidadeLoanCondition(LoanCondition dto.LoanConditionRequest_dto) []string {
 
 	errs := []string{}
@@ -177,24 +431,13 @@ func (l *LoanCondition_usecase) ValidadeLoanCondition(LoanCondition dto.LoanCond
 		errs = append(errs, "InterestRate is required above 0 and below 80 per year")
 	}
 
-	tierNames := []string{"tier1", "tier2", "tier3", "tier4"}
-
-	if LoanCondition.Name == "" || !slices.Contains(tierNames, LoanCondition.Name) {
-		errs = append(errs, "Name is required and must be one of the following: tier1, tier2, tier3, tier4")
+	if strings.TrimSpace(LoanCondition.Name) == "" {
+		errs = append(errs, "Name is required")
 	}
 
-	// We can use this way to validate all fields, but it's not necessary yet
-	// if LoanCondition.MinAge <= 18 {
-	// 	errs = append(errs, "MinAge is required above 18")
-	// }
-
-	// if LoanCondition.MaxAge >= 120 {
-	// 	errs = append(errs, "MaxAge is required under 120")
-	// }
-
-	// if LoanCondition.MinAge > LoanCondition.MaxAge {
-	// 	errs = append(errs, "MinAge must be less than MaxAge")
-	// }
+	if LoanCondition.MinAge < 0 || LoanCondition.MaxAge < 0 {
+		errs = append(errs, "MinAge and MaxAge must not be negative")
+	}
 
 	if len(errs) > 0 {
 		return errs
@@ -10,6 +10,7 @@ import (
 
 	"github.com/Jonattas-21/loan-engine/internal/api/dto"
 	"github.com/Jonattas-21/loan-engine/internal/domain/entities"
+	"github.com/Jonattas-21/loan-engine/internal/domain/interfaces"
 	"github.com/Jonattas-21/loan-engine/internal/infrastructure/logger"
 	"github.com/Jonattas-21/loan-engine/internal/usecases"
 	internalMock "github.com/Jonattas-21/loan-engine/tests"
@@ -38,30 +39,354 @@ func setupCondition() {
 	}
 }
 
+// currentTiers are the open versions of the tiers, covering 18 to 100
+func currentTiers() []entities.LoanCondition {
+	effectiveFrom := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	return []entities.LoanCondition{
+		{ID: "01JH8Z5Q4E2V3W6X7Y8Z9A0B1C", Name: "tier1", Version: 1, InterestRate: 4.0, MinAge: 18, MaxAge: 25, EffectiveFrom: effectiveFrom},
+		{ID: "01JH8Z5Q4E2V3W6X7Y8Z9A0B1D", Name: "tier2", Version: 2, InterestRate: 3.0, MinAge: 26, MaxAge: 40, EffectiveFrom: effectiveFrom},
+		{ID: "01JH8Z5Q4E2V3W6X7Y8Z9A0B1E", Name: "tier3", Version: 1, InterestRate: 2.0, MinAge: 41, MaxAge: 100, EffectiveFrom: effectiveFrom},
+	}
+}
+
+func mockCurrentTiers() {
+	openFilter := interfaces.Query{Filter: interfaces.Where(interfaces.Eq("effectiveto", nil))}
+	mockConditionDatabaseRepo.On("Find", openFilter).Return(currentTiers(), nil)
+	mockConditionDatabaseRepo.On("Upsert", mock.Anything, mock.Anything).Return(nil)
+	mockConditionDatabaseRepo.On("SaveItemCollection", mock.Anything).Return(nil)
+	mockConditionDatabaseRepo.On("GetItemsCollection", "loan_conditions").Return([]entities.LoanCondition{}, nil)
+}
+
+// savedVersions are the versions written, by name
+func savedVersions() map[string]entities.LoanCondition {
+	saved := map[string]entities.LoanCondition{}
+	for _, call := range mockConditionDatabaseRepo.Calls {
+		if call.Method == "SaveItemCollection" {
+			condition := call.Arguments.Get(0).(entities.LoanCondition)
+			saved[condition.Name] = condition
+		}
+	}
+	return saved
+}
+
+// closedVersions are the versions closed, by name
+func closedVersions() map[string]entities.LoanCondition {
+	closed := map[string]entities.LoanCondition{}
+	for _, call := range mockConditionDatabaseRepo.Calls {
+		if call.Method == "Upsert" {
+			condition := call.Arguments.Get(1).(entities.LoanCondition)
+			closed[condition.Name] = condition
+		}
+	}
+	return closed
+}
+
 func TestSetLoanCondition(t *testing.T) {
 	setupCondition()
 	assert := assert.New(t)
-	// Define the test loan condition
-	loanCondition := dto.LoanConditionRequest_dto{
-		Name:         "tier1",
-		InterestRate: 5.0,
-		MaxAge:       60,
-		MinAge:       18,
-	}
+	mockCurrentTiers()
 
-	// Set up expected calls and returns
-	fields := map[string]interface{}{
-		"interestrate": 5.0,
+	// only the interest rate, the ages are kept
+	loanCondition := dto.LoanConditionRequest_dto{
+		Name:          "tier1",
+		InterestRate:  5.0,
+		EffectiveFrom: time.Now().AddDate(0, 1, 0).Truncate(time.Second),
 	}
-	mockConditionDatabaseRepo.On("UpdateItemCollection", loanCondition.Name, fields).Return(nil)
-	mockCacheRepo.On("Set", "loan_conditions", mock.Anything, time.Minute*10).Return(nil)
 
 	// Call the function
-	err, validations := loanConditionUsecase.SetLoanCondition(loanCondition)
+	err, validations := loanConditionUsecase.SetLoanCondition(loanCondition, "admin@example.com")
 
 	// Assertions
 	assert.NoError(err)
 	assert.Nil(validations)
+
+	// the current version is closed when the new one starts
+	currentFilter := interfaces.Where(interfaces.Eq("id", "01JH8Z5Q4E2V3W6X7Y8Z9A0B1C"))
+	mockConditionDatabaseRepo.AssertCalled(t, "Upsert", currentFilter, mock.Anything)
+	closed := closedVersions()
+	assert.Len(closed, 1)
+	assert.Equal("01JH8Z5Q4E2V3W6X7Y8Z9A0B1C", closed["tier1"].ID)
+	assert.Equal(loanCondition.EffectiveFrom, *closed["tier1"].EffectiveTo)
+
+	saved := savedVersions()
+	assert.Len(saved, 1)
+	newVersion := saved["tier1"]
+	assert.Equal(2, newVersion.Version)
+	assert.Equal(5.0, newVersion.InterestRate)
+	assert.Equal(18, newVersion.MinAge)
+	assert.Equal(25, newVersion.MaxAge)
+	assert.Equal(loanCondition.EffectiveFrom, newVersion.EffectiveFrom)
+	assert.Nil(newVersion.EffectiveTo)
+	assert.Equal("admin@example.com", newVersion.Author)
+	assert.NotEmpty(newVersion.ID)
+}
+
+func TestSetLoanCondition_effectiveBeforeCurrent(t *testing.T) {
+	setupCondition()
+	assert := assert.New(t)
+
+	// tier1 has a change scheduled for the next months
+	tiers := currentTiers()
+	tiers[0].EffectiveFrom = time.Now().AddDate(0, 2, 0)
+	openFilter := interfaces.Query{Filter: interfaces.Where(interfaces.Eq("effectiveto", nil))}
+	mockConditionDatabaseRepo.On("Find", openFilter).Return(tiers, nil)
+
+	err, validations := loanConditionUsecase.SetLoanCondition(dto.LoanConditionRequest_dto{
+		Name:          "tier1",
+		InterestRate:  5.0,
+		EffectiveFrom: time.Now().AddDate(0, 1, 0),
+	}, "")
+
+	assert.NoError(err)
+	assert.Equal([]string{"Effective from must be after the effective from of the current version of tier1"}, validations)
+	mockConditionDatabaseRepo.AssertNotCalled(t, "SaveItemCollection", mock.Anything)
+}
+
+func TestSetLoanCondition_retroactive(t *testing.T) {
+	setupCondition()
+	assert := assert.New(t)
+	mockCurrentTiers()
+
+	// the simulations already priced keep their rates
+	err, validations := loanConditionUsecase.SetLoanCondition(dto.LoanConditionRequest_dto{
+		Name:          "tier1",
+		InterestRate:  5.0,
+		EffectiveFrom: time.Now().AddDate(0, -1, 0),
+	}, "")
+
+	assert.NoError(err)
+	assert.Equal([]string{"Effective from must not be in the past"}, validations)
+	mockConditionDatabaseRepo.AssertNotCalled(t, "Upsert", mock.Anything, mock.Anything)
+	mockConditionDatabaseRepo.AssertNotCalled(t, "SaveItemCollection", mock.Anything)
+}
+
+func TestSetLoanCondition_today(t *testing.T) {
+	setupCondition()
+	assert := assert.New(t)
+	mockCurrentTiers()
+
+	// a date without time is 00:00, today is effective now
+	today, _ := time.Parse("2006-01-02", time.Now().UTC().Format("2006-01-02"))
+	err, validations := loanConditionUsecase.SetLoanCondition(dto.LoanConditionRequest_dto{
+		Name:          "tier1",
+		InterestRate:  5.0,
+		EffectiveFrom: today,
+	}, "")
+
+	assert.NoError(err)
+	assert.Nil(validations)
+	saved := savedVersions()
+	assert.True(saved["tier1"].EffectiveFrom.After(today))
+	assert.Equal(saved["tier1"].EffectiveFrom, *closedVersions()["tier1"].EffectiveTo)
+}
+
+func TestReplaceLoanConditions_revert(t *testing.T) {
+	setupCondition()
+	assert := assert.New(t)
+
+	openFilter := interfaces.Query{Filter: interfaces.Where(interfaces.Eq("effectiveto", nil))}
+	mockConditionDatabaseRepo.On("Find", openFilter).Return(currentTiers(), nil)
+	mockConditionDatabaseRepo.On("SaveItemCollection", mock.Anything).Return(nil)
+	mockConditionDatabaseRepo.On("Delete", mock.Anything).Return(nil)
+	tier2Filter := interfaces.Where(interfaces.Eq("id", "01JH8Z5Q4E2V3W6X7Y8Z9A0B1D"))
+	mockConditionDatabaseRepo.On("Upsert", tier2Filter, mock.Anything).Return(errors.New("connection lost"))
+	mockConditionDatabaseRepo.On("Upsert", mock.Anything, mock.Anything).Return(nil)
+
+	// closing tier2 fails after tier1 was closed
+	err, validations := loanConditionUsecase.ReplaceLoanConditions(dto.LoanConditionsRequest_dto{
+		LoanConditions: []dto.LoanConditionRequest_dto{
+			{Name: "tier1", InterestRate: 5.0, MinAge: 18, MaxAge: 25},
+			{Name: "tier2", InterestRate: 4.0, MinAge: 26, MaxAge: 40},
+			{Name: "tier3", InterestRate: 2.0, MinAge: 41, MaxAge: 100},
+		},
+	}, "")
+
+	assert.Error(err)
+	assert.Nil(validations)
+
+	// the new versions are removed and tier1 is opened again
+	saved := savedVersions()
+	assert.Len(saved, 2)
+	mockConditionDatabaseRepo.AssertCalled(t, "Delete", interfaces.Where(interfaces.In("id", []string{saved["tier1"].ID, saved["tier2"].ID})))
+	upserts := []entities.LoanCondition{}
+	for _, call := range mockConditionDatabaseRepo.Calls {
+		if call.Method == "Upsert" {
+			upserts = append(upserts, call.Arguments.Get(1).(entities.LoanCondition))
+		}
+	}
+	assert.Len(upserts, 3)
+	assert.Equal("tier1", upserts[2].Name)
+	assert.Nil(upserts[2].EffectiveTo)
+	mockConditionDatabaseRepo.AssertNotCalled(t, "GetItemsCollection", "loan_conditions")
+}
+
+func TestSetLoanCondition_reshape(t *testing.T) {
+	setupCondition()
+	assert := assert.New(t)
+	mockCurrentTiers()
+
+	// moving the end of tier1 alone overlaps tier2, nothing is written
+	err, validations := loanConditionUsecase.SetLoanCondition(dto.LoanConditionRequest_dto{Name: "tier1", InterestRate: 5.0, MinAge: 18, MaxAge: 30}, "")
+
+	assert.NoError(err)
+	assert.Equal([]string{"Tiers tier1 (18-30) and tier2 (26-40) overlap"}, validations)
+	mockConditionDatabaseRepo.AssertNotCalled(t, "Upsert", mock.Anything, mock.Anything)
+	mockConditionDatabaseRepo.AssertNotCalled(t, "SaveItemCollection", mock.Anything)
+
+	// a new tier must fit in the ages too
+	err, validations = loanConditionUsecase.SetLoanCondition(dto.LoanConditionRequest_dto{Name: "tier4", InterestRate: 5.0}, "")
+	assert.NoError(err)
+	assert.Contains(validations, "Tier tier4 ages must be between 18 and 100")
+}
+
+func TestReplaceLoanConditions_split(t *testing.T) {
+	setupCondition()
+	assert := assert.New(t)
+	mockCurrentTiers()
+
+	// tier3 is split in two, tier1 and tier2 do not change
+	err, validations := loanConditionUsecase.ReplaceLoanConditions(dto.LoanConditionsRequest_dto{
+		LoanConditions: []dto.LoanConditionRequest_dto{
+			{Name: "tier1", InterestRate: 4.0, MinAge: 18, MaxAge: 25},
+			{Name: "tier2", InterestRate: 3.0, MinAge: 26, MaxAge: 40},
+			{Name: "tier3", InterestRate: 2.0, MinAge: 41, MaxAge: 60},
+			{Name: "tier4", InterestRate: 4.0, MinAge: 61, MaxAge: 100},
+		},
+	}, "admin@example.com")
+
+	assert.NoError(err)
+	assert.Nil(validations)
+
+	closed := closedVersions()
+	assert.Len(closed, 1)
+	assert.NotNil(closed["tier3"].EffectiveTo)
+
+	saved := savedVersions()
+	assert.Len(saved, 2)
+	assert.Equal(2, saved["tier3"].Version)
+	assert.Equal(60, saved["tier3"].MaxAge)
+	assert.Equal(1, saved["tier4"].Version)
+	assert.Equal(61, saved["tier4"].MinAge)
+	assert.Equal(*closed["tier3"].EffectiveTo, saved["tier4"].EffectiveFrom)
+}
+
+func TestReplaceLoanConditions_invalid(t *testing.T) {
+	setupCondition()
+	assert := assert.New(t)
+	mockCurrentTiers()
+
+	err, validations := loanConditionUsecase.ReplaceLoanConditions(dto.LoanConditionsRequest_dto{
+		LoanConditions: []dto.LoanConditionRequest_dto{
+			{Name: "tier1", InterestRate: 4.0, MinAge: 20, MaxAge: 30},
+			{Name: "tier2", InterestRate: 3.0, MinAge: 35, MaxAge: 70},
+			{Name: "tier3", InterestRate: 2.0, MinAge: 60, MaxAge: 90},
+		},
+	}, "")
+
+	// every problem is reported and the set is rejected as a whole
+	assert.NoError(err)
+	assert.Equal([]string{
+		"Ages 18 to 19 are not covered by any tier",
+		"Ages 31 to 34 are not covered by any tier",
+		"Tiers tier2 (35-70) and tier3 (60-90) overlap",
+		"Ages 91 to 100 are not covered by any tier",
+	}, validations)
+	mockConditionDatabaseRepo.AssertNotCalled(t, "Upsert", mock.Anything, mock.Anything)
+	mockConditionDatabaseRepo.AssertNotCalled(t, "SaveItemCollection", mock.Anything)
+}
+
+func TestDeleteLoanCondition(t *testing.T) {
+	setupCondition()
+	assert := assert.New(t)
+	mockCurrentTiers()
+
+	// removing a tier leaves its ages out
+	err, validations := loanConditionUsecase.DeleteLoanCondition("tier2", "", time.Time{}, "")
+	assert.NoError(err)
+	assert.Equal([]string{"Ages 26 to 40 are not covered by any tier"}, validations)
+
+	err, validations = loanConditionUsecase.DeleteLoanCondition("tier5", "", time.Time{}, "")
+	assert.NoError(err)
+	assert.Equal([]string{"Loan condition tier5 not found"}, validations)
+	mockConditionDatabaseRepo.AssertNotCalled(t, "Upsert", mock.Anything, mock.Anything)
+
+	// tier1 takes the ages of tier2
+	err, validations = loanConditionUsecase.DeleteLoanCondition("tier2", "tier1", time.Time{}, "admin@example.com")
+	assert.NoError(err)
+	assert.Nil(validations)
+
+	closed := closedVersions()
+	assert.Len(closed, 2)
+	assert.Contains(closed, "tier2")
+
+	saved := savedVersions()
+	assert.Len(saved, 1)
+	assert.Equal(18, saved["tier1"].MinAge)
+	assert.Equal(40, saved["tier1"].MaxAge)
+	assert.Equal(2, saved["tier1"].Version)
+}
+
+func TestValidateAgeBands(t *testing.T) {
+	setupCondition()
+	assert := assert.New(t)
+
+	assert.Nil(loanConditionUsecase.ValidateAgeBands(currentTiers()))
+	assert.Equal([]string{"At least one tier is required to cover the ages 18 to 100"}, loanConditionUsecase.ValidateAgeBands(nil))
+
+	tiers := currentTiers()
+	tiers[1].Name = "tier1"
+	assert.Equal([]string{"Tier tier1 is informed more than once"}, loanConditionUsecase.ValidateAgeBands(tiers))
+
+	// the supported span is configurable
+	loanConditionUsecase.MinSupportedAge = 21
+	loanConditionUsecase.MaxSupportedAge = 80
+	assert.Equal([]string{
+		"Tier tier1 ages must be between 21 and 80",
+		"Tier tier3 ages must be between 21 and 80",
+	}, loanConditionUsecase.ValidateAgeBands(currentTiers()))
+}
+
+func TestGetLoanConditionVersions(t *testing.T) {
+	setupCondition()
+	assert := assert.New(t)
+
+	query := interfaces.Query{
+		Filter: interfaces.Where(interfaces.Eq("name", "tier1")),
+		Sort:   []interfaces.SortField{{Field: "version"}},
+	}
+	mockConditionDatabaseRepo.On("Find", query).Return(currentTiers()[:1], nil)
+
+	versions, err := loanConditionUsecase.GetLoanConditionVersions("tier1")
+	assert.NoError(err)
+	assert.Len(versions, 1)
+}
+
+func TestGetLoanConditionsAt(t *testing.T) {
+	setupCondition()
+	assert := assert.New(t)
+
+	changeDate := time.Date(2025, 3, 1, 0, 0, 0, 0, time.UTC)
+	loanConditions := []entities.LoanCondition{
+		{Name: "tier1", Version: 1, InterestRate: 4.0, EffectiveFrom: time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC), EffectiveTo: &changeDate},
+		{Name: "tier1", Version: 2, InterestRate: 5.0, EffectiveFrom: changeDate},
+	}
+	jsonConditions, _ := json.Marshal(loanConditions)
+	mockCacheRepo.On("Get", "loan_conditions").Return(string(jsonConditions), nil)
+
+	conditions, err := loanConditionUsecase.GetLoanConditionsAt(time.Date(2025, 2, 28, 23, 0, 0, 0, time.UTC))
+	assert.NoError(err)
+	assert.Len(conditions, 1)
+	assert.Equal(1, conditions[0].Version)
+
+	// the new version is in force from its first instant
+	conditions, err = loanConditionUsecase.GetLoanConditionsAt(changeDate)
+	assert.NoError(err)
+	assert.Len(conditions, 1)
+	assert.Equal(2, conditions[0].Version)
+
+	conditions, err = loanConditionUsecase.GetLoanConditionsAt(time.Date(2024, 12, 1, 0, 0, 0, 0, time.UTC))
+	assert.NoError(err)
+	assert.Empty(conditions)
 }
 
 func TestGetLoanConditions_db(t *testing.T) {
@@ -106,35 +431,78 @@ func TestGetLoanConditions_cache(t *testing.T) {
 	mockCacheRepo.AssertExpectations(t)
 }
 
-func TestInitLoanEngineConditionsData(t *testing.T) {
+var seedConditions = []entities.LoanCondition{
+	{Name: "tier1", InterestRate: 5, MinAge: 18, MaxAge: 25},
+	{Name: "tier2", InterestRate: 3, MinAge: 26, MaxAge: 40},
+}
+
+func TestSeedLoanConditions(t *testing.T) {
 	setupCondition()
 	assert := assert.New(t)
 
 	// Set up expected calls and returns
-	mockConditionDatabaseRepo.On("TrunkCollection").Return(nil)
+	mockConditionDatabaseRepo.On("Count", interfaces.Filter{}).Return(int64(0), nil)
 	mockConditionDatabaseRepo.On("SaveItemCollection", mock.Anything).Return(nil)
 
 	// Call the function
-	err := loanConditionUsecase.InitLoanEngineConditionsData()
+	err := loanConditionUsecase.SeedLoanConditions(seedConditions)
 
 	// Assertions
 	assert.Nil(err)
-	mockConditionDatabaseRepo.AssertExpectations(t)
+	mockConditionDatabaseRepo.AssertNumberOfCalls(t, "SaveItemCollection", 2)
+	seeded := mockConditionDatabaseRepo.Calls[1].Arguments.Get(0).(entities.LoanCondition)
+	assert.Equal("tier1", seeded.Name)
+	assert.Equal(1, seeded.Version)
+	assert.False(seeded.EffectiveFrom.IsZero())
+	assert.NotEmpty(seeded.ID)
+}
+
+func TestSeedLoanConditions_notEmpty(t *testing.T) {
+	setupCondition()
+	assert := assert.New(t)
+
+	// the conditions changed through the api are kept
+	mockConditionDatabaseRepo.On("Count", interfaces.Filter{}).Return(int64(4), nil)
+
+	err := loanConditionUsecase.SeedLoanConditions(seedConditions)
+
+	assert.Nil(err)
+	mockConditionDatabaseRepo.AssertNotCalled(t, "SaveItemCollection", mock.Anything)
+	mockConditionDatabaseRepo.AssertNotCalled(t, "TrunkCollection")
 }
 
-func TestInitLoanEngineConditionsData_Error(t *testing.T) {
+func TestSeedLoanConditions_Error(t *testing.T) {
 	setupCondition()
 	assert := assert.New(t)
 
 	// Set up expected calls and returns
-	mockConditionDatabaseRepo.On("TrunkCollection").Return(nil)
+	mockConditionDatabaseRepo.On("Count", interfaces.Filter{}).Return(int64(0), nil)
 	mockConditionDatabaseRepo.On("SaveItemCollection", mock.Anything).Return(errors.New("Error saving default loan condition for tier 1"))
 
 	// Call the function
-	err := loanConditionUsecase.InitLoanEngineConditionsData()
+	err := loanConditionUsecase.SeedLoanConditions(seedConditions)
 
 	// Assertions
 	assert.NotNil(err)
 	assert.Contains(err.Error(), "Error saving default loan condition for tier 1")
 	mockConditionDatabaseRepo.AssertExpectations(t)
 }
+
+func TestVersionUnversionedLoanConditions(t *testing.T) {
+	setupCondition()
+	assert := assert.New(t)
+
+	modifiedDate := time.Date(2024, 6, 1, 0, 0, 0, 0, time.UTC)
+	unversionedFilter := interfaces.Where(interfaces.Eq("version", nil))
+	mockConditionDatabaseRepo.On("Find", interfaces.Query{Filter: unversionedFilter}).Return([]entities.LoanCondition{{Name: "tier1", InterestRate: 5, ModifiedDate: modifiedDate}}, nil)
+	mockConditionDatabaseRepo.On("Upsert", unversionedFilter.And(interfaces.Eq("name", "tier1")), mock.Anything).Return(nil)
+
+	err := loanConditionUsecase.VersionUnversionedLoanConditions()
+
+	assert.NoError(err)
+	versioned := mockConditionDatabaseRepo.Calls[1].Arguments.Get(1).(entities.LoanCondition)
+	assert.Equal(1, versioned.Version)
+	assert.Equal(modifiedDate, versioned.EffectiveFrom)
+	assert.Nil(versioned.EffectiveTo)
+	assert.NotEmpty(versioned.ID)
+}
@@ -0,0 +1,240 @@
+package usecases
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/Jonattas-21/loan-engine/internal/api/dto"
+	"github.com/Jonattas-21/loan-engine/internal/domain/entities"
+	"github.com/Jonattas-21/loan-engine/internal/domain/interfaces"
+)
+
+// the cache keeps the whole fee catalog, it is refreshed on every change
+const loanFeesCacheKey = "loan_fees"
+
+// ErrLoanFeeNotFound is returned when there is no fee of the name in the catalog
+var ErrLoanFeeNotFound = errors.New("loan fee not found")
+
+type LoanFee interface {
+	SetLoanFee(loanFeeDto dto.LoanFeeRequest_dto, author string) (error, []string)
+	DeleteLoanFee(name string) error
+	GetLoanFees() ([]entities.LoanFee, error)
+}
+
+type LoanFee_usecase struct {
+	LoanFeeRepository interfaces.Repository[entities.LoanFee]
+	CacheRepository   interfaces.CacheRepository
+	Logger            interfaces.Log
+}
+
+// SetLoanFee creates a fee of the catalog or replaces the one of the same name
+func (l *LoanFee_usecase) SetLoanFee(loanFeeDto dto.LoanFeeRequest_dto, author string) (error, []string) {
+	errs := l.ValidateLoanFee(loanFeeDto)
+	if errs != nil {
+		l.Logger.Errorln("Error validating loan fee: ", errs)
+		return nil, errs
+	}
+
+	loanFee := newLoanFee(loanFeeDto, author, time.Now())
+
+	err := l.LoanFeeRepository.Upsert(interfaces.Where(interfaces.Eq("name", loanFee.Name)), loanFee)
+	if err != nil {
+		l.Logger.Errorln(fmt.Sprintf("Error saving loan fee %v: ", loanFee.Name), err.Error())
+		return fmt.Errorf("error saving loan fee %v: %w", loanFee.Name, err), nil
+	}
+
+	_, err = l.loadLoanFees()
+	return err, nil
+}
+
+// DeleteLoanFee removes the fee from the catalog, the simulations keep the fees they were charged
+func (l *LoanFee_usecase) DeleteLoanFee(name string) error {
+	fees, err := l.GetLoanFees()
+	if err != nil {
+		return err
+	}
+
+	found := false
+	for _, fee := range fees {
+		found = found || fee.Name == name
+	}
+	if !found {
+		return ErrLoanFeeNotFound
+	}
+
+	err = l.LoanFeeRepository.DeleteItemCollection(name)
+	if err != nil {
+		l.Logger.Errorln(fmt.Sprintf("Error deleting loan fee %v: ", name), err.Error())
+		return fmt.Errorf("error deleting loan fee %v: %w", name, err)
+	}
+
+	_, err = l.loadLoanFees()
+	return err
+}
+
+// GetLoanFees returns the fee catalog, from the cache when there
+func (l *LoanFee_usecase) GetLoanFees() ([]entities.LoanFee, error) {
+	loanFees := []entities.LoanFee{}
+
+	val, err := l.CacheRepository.Get(loanFeesCacheKey)
+	if err == nil {
+		err = json.Unmarshal([]byte(val), &loanFees)
+		if err != nil {
+			l.Logger.Errorln("Error unmarshalling loan fees from cache: ", err.Error())
+		} else {
+			return loanFees, nil
+		}
+	}
+
+	return l.loadLoanFees()
+}
+
+// loadLoanFees reads the fee catalog from mongoDB and keeps it in cache, an empty catalog included
+func (l *LoanFee_usecase) loadLoanFees() ([]entities.LoanFee, error) {
+	loanFees, err := l.LoanFeeRepository.GetItemsCollection(loanFeesCacheKey)
+	if err != nil {
+		l.Logger.Errorln("Error getting loan fees: ", err.Error())
+		return nil, fmt.Errorf("error getting loan fees from mongoDB: %w", err)
+	}
+	if loanFees == nil {
+		loanFees = []entities.LoanFee{}
+	}
+
+	// Save in cache, if not, let's just log the error and continue
+	jsonFees, err := json.Marshal(loanFees)
+	if err != nil {
+		l.Logger.Errorln("Error marshalling loan fees: ", err.Error())
+	} else {
+		err = l.CacheRepository.Set(loanFeesCacheKey, jsonFees, time.Minute*10)
+		if err != nil {
+			l.Logger.Errorln("Error setting loan fees in cache: ", err.Error())
+		}
+	}
+
+	return loanFees, nil
+}
+
+// LoanFeeMigrations are the data migrations of the fee catalog.
+// No fee is charged by default, the seeding is left pending until seeds are informed.
+func (l *LoanFee_usecase) LoanFeeMigrations(seeds []entities.LoanFee) []Migration {
+	if len(seeds) == 0 {
+		return nil
+	}
+	return []Migration{
+		{Version: MigrationSeedLoanFees, Name: "seed loan fees", Up: func() error { return l.SeedLoanFees(seeds) }},
+	}
+}
+
+// SeedLoanFees inserts the fees of the seeds, only when the catalog is empty
+func (l *LoanFee_usecase) SeedLoanFees(seeds []entities.LoanFee) error {
+	count, err := l.LoanFeeRepository.Count(interfaces.Filter{})
+	if err != nil {
+		l.Logger.Errorln("Error counting loan fees: ", err.Error())
+		return fmt.Errorf("error counting loan fees: %w", err)
+	}
+	if count > 0 {
+		l.Logger.Infoln(fmt.Sprintf("Loan fees already saved (%v), skipping the seeds", count))
+		return nil
+	}
+
+	// the seeds are checked as the fees set by the API, one invalid fails the migration before any is saved
+	requests := make([]dto.LoanFeeRequest_dto, len(seeds))
+	errs := []string{}
+	for i, seed := range seeds {
+		requests[i] = dto.LoanFeeRequest_dto{
+			Name:        seed.Name,
+			Kind:        seed.Kind,
+			Calculation: seed.Calculation,
+			Amount:      seed.Amount,
+			Rate:        seed.Rate,
+			Payment:     seed.Payment,
+			Products:    seed.Products,
+			Currencies:  seed.Currencies,
+		}
+		for _, validation := range l.ValidateLoanFee(requests[i]) {
+			errs = append(errs, fmt.Sprintf("%v: %v", seed.Name, validation))
+		}
+	}
+	if len(errs) > 0 {
+		l.Logger.Errorln("Error validating loan fee seeds: ", errs)
+		return fmt.Errorf("invalid loan fee seeds: %v", strings.Join(errs, "; "))
+	}
+
+	now := time.Now()
+	for _, request := range requests {
+		loanFee := newLoanFee(request, "system", now)
+
+		err = l.LoanFeeRepository.SaveItemCollection(loanFee)
+		if err != nil {
+			l.Logger.Errorln(fmt.Sprintf("Error saving default loan fee %v:", loanFee.Name), err.Error())
+			return fmt.Errorf("error saving default loan fee %v: %w", loanFee.Name, err)
+		}
+	}
+
+	return nil
+}
+
+// newLoanFee is the fee of a valid request, with the names trimmed, the codes in upper case and the default payment
+func newLoanFee(loanFeeDto dto.LoanFeeRequest_dto, author string, modifiedDate time.Time) entities.LoanFee {
+	loanFee := entities.LoanFee{
+		Name:         strings.TrimSpace(loanFeeDto.Name),
+		Kind:         strings.ToUpper(loanFeeDto.Kind),
+		Calculation:  strings.ToUpper(loanFeeDto.Calculation),
+		Amount:       loanFeeDto.Amount,
+		Rate:         loanFeeDto.Rate,
+		Payment:      strings.ToUpper(strings.TrimSpace(loanFeeDto.Payment)),
+		Products:     loanFeeDto.Products,
+		Currencies:   loanFeeDto.Currencies,
+		Author:       author,
+		ModifiedDate: modifiedDate,
+	}
+	if loanFee.Payment == "" {
+		loanFee.Payment = entities.PaymentFinanced
+	}
+	if loanFee.Kind == entities.FeeAdmin {
+		loanFee.Payment = entities.PaymentInstallment
+	}
+	return loanFee
+}
+
+// ValidateLoanFee checks a fee of the catalog
+func (l *LoanFee_usecase) ValidateLoanFee(loanFee dto.LoanFeeRequest_dto) []string {
+	errs := []string{}
+
+	if strings.TrimSpace(loanFee.Name) == "" {
+		errs = append(errs, "Name is required")
+	}
+
+	kind := strings.ToUpper(loanFee.Kind)
+	if kind != entities.FeeOrigination && kind != entities.FeeRegistration && kind != entities.FeeAdmin {
+		errs = append(errs, "Kind must be ORIGINATION, REGISTRATION or ADMIN")
+	}
+
+	switch strings.ToUpper(loanFee.Calculation) {
+	case entities.FeeFlat:
+		if !loanFee.Amount.IsPositive() {
+			errs = append(errs, "Amount is required above 0 for a flat fee")
+		}
+	case entities.FeePercentage:
+		if kind == entities.FeeAdmin {
+			errs = append(errs, "Admin fees must be flat by installment")
+		} else if loanFee.Rate <= 0 || loanFee.Rate >= 100 {
+			errs = append(errs, "Rate is required above 0 and below 100 for a percentage fee")
+		}
+	default:
+		errs = append(errs, "Calculation must be FLAT or PERCENTAGE")
+	}
+
+	if payment := strings.ToUpper(strings.TrimSpace(loanFee.Payment)); kind != entities.FeeAdmin && payment != "" && payment != entities.PaymentFinanced && payment != entities.PaymentUpfront {
+		errs = append(errs, "Payment must be FINANCED or UPFRONT")
+	}
+
+	if len(errs) > 0 {
+		return errs
+	}
+
+	return nil
+}
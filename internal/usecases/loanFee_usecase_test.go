@@ -0,0 +1,184 @@
+package usecases_test
+
+import (
+	"encoding/json"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/Jonattas-21/loan-engine/internal/api/dto"
+	"github.com/Jonattas-21/loan-engine/internal/domain/entities"
+	"github.com/Jonattas-21/loan-engine/internal/domain/interfaces"
+	"github.com/Jonattas-21/loan-engine/internal/infrastructure/logger"
+	"github.com/Jonattas-21/loan-engine/internal/usecases"
+	"github.com/Jonattas-21/loan-engine/package/money"
+	internalMock "github.com/Jonattas-21/loan-engine/tests"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+var (
+	mockFeeDatabaseRepo = new(internalMock.MockRepository[entities.LoanFee])
+	loanFeeUsecase      = &usecases.LoanFee_usecase{}
+)
+
+func setupLoanFee() {
+	mockFeeDatabaseRepo = new(internalMock.MockRepository[entities.LoanFee])
+	mockCacheRepo = new(internalMock.MockCacheRepository)
+	loanFeeUsecase = &usecases.LoanFee_usecase{
+		LoanFeeRepository: mockFeeDatabaseRepo,
+		CacheRepository:   mockCacheRepo,
+		Logger:            logger.LogSetup(),
+	}
+}
+
+// catalogFees are a TAC of 1% financed, a registration of 50 upfront and an admin fee of 5 by installment, in R$
+func catalogFees() []entities.LoanFee {
+	return []entities.LoanFee{
+		{Name: "TAC", Kind: entities.FeeOrigination, Calculation: entities.FeePercentage, Rate: 1, Payment: entities.PaymentFinanced, Currencies: []string{"R$"}},
+		{Name: "Registration", Kind: entities.FeeRegistration, Calculation: entities.FeeFlat, Amount: money.MustParse("50"), Payment: entities.PaymentUpfront, Currencies: []string{"R$"}},
+		{Name: "Admin", Kind: entities.FeeAdmin, Calculation: entities.FeeFlat, Amount: money.MustParse("5"), Payment: entities.PaymentInstallment, Currencies: []string{"R$"}},
+	}
+}
+
+func TestValidateLoanFee(t *testing.T) {
+	assert := assert.New(t)
+	setupLoanFee()
+
+	assert.Nil(loanFeeUsecase.ValidateLoanFee(dto.LoanFeeRequest_dto{Name: "TAC", Kind: "origination", Calculation: "percentage", Rate: 1}))
+	assert.Equal([]string{
+		"Name is required",
+		"Kind must be ORIGINATION, REGISTRATION or ADMIN",
+		"Calculation must be FLAT or PERCENTAGE",
+		"Payment must be FINANCED or UPFRONT",
+	}, loanFeeUsecase.ValidateLoanFee(dto.LoanFeeRequest_dto{Kind: "TAX", Payment: "INSTALLMENT"}))
+	assert.Equal([]string{"Admin fees must be flat by installment"},
+		loanFeeUsecase.ValidateLoanFee(dto.LoanFeeRequest_dto{Name: "Admin", Kind: "ADMIN", Calculation: "PERCENTAGE", Rate: 1}))
+	assert.Equal([]string{"Amount is required above 0 for a flat fee"},
+		loanFeeUsecase.ValidateLoanFee(dto.LoanFeeRequest_dto{Name: "Registration", Kind: "REGISTRATION", Calculation: "FLAT"}))
+}
+
+func TestSetLoanFee(t *testing.T) {
+	assert := assert.New(t)
+	setupLoanFee()
+
+	fees := catalogFees()
+	jsonFees, _ := json.Marshal(fees)
+	mockFeeDatabaseRepo.On("Upsert", mock.Anything, mock.Anything).Return(nil)
+	mockFeeDatabaseRepo.On("GetItemsCollection", "loan_fees").Return(fees, nil)
+	mockCacheRepo.On("Set", "loan_fees", jsonFees, time.Minute*10).Return(nil)
+
+	err, validations := loanFeeUsecase.SetLoanFee(dto.LoanFeeRequest_dto{Name: "Admin", Kind: "admin", Calculation: "flat", Amount: money.MustParse("5")}, "admin@example.com")
+
+	assert.NoError(err)
+	assert.Nil(validations)
+	mockFeeDatabaseRepo.AssertCalled(t, "Upsert", interfaces.Where(interfaces.Eq("name", "Admin")), mock.MatchedBy(func(fee entities.LoanFee) bool {
+		return fee.Kind == entities.FeeAdmin && fee.Calculation == entities.FeeFlat && fee.Payment == entities.PaymentInstallment && fee.Author == "admin@example.com"
+	}))
+	// the catalog in cache is refreshed
+	mockCacheRepo.AssertCalled(t, "Set", "loan_fees", jsonFees, time.Minute*10)
+}
+
+func TestSetLoanFee_invalid(t *testing.T) {
+	assert := assert.New(t)
+	setupLoanFee()
+
+	err, validations := loanFeeUsecase.SetLoanFee(dto.LoanFeeRequest_dto{Name: "TAC", Kind: "ORIGINATION", Calculation: "PERCENTAGE"}, "")
+
+	assert.NoError(err)
+	assert.Equal([]string{"Rate is required above 0 and below 100 for a percentage fee"}, validations)
+	mockFeeDatabaseRepo.AssertNotCalled(t, "Upsert", mock.Anything, mock.Anything)
+}
+
+func TestDeleteLoanFee(t *testing.T) {
+	assert := assert.New(t)
+	setupLoanFee()
+
+	fees := catalogFees()
+	jsonFees, _ := json.Marshal(fees)
+	mockCacheRepo.On("Get", "loan_fees").Return(string(jsonFees), nil)
+	mockFeeDatabaseRepo.On("DeleteItemCollection", "Registration").Return(nil)
+	mockFeeDatabaseRepo.On("GetItemsCollection", "loan_fees").Return([]entities.LoanFee{fees[0], fees[2]}, nil)
+	mockCacheRepo.On("Set", "loan_fees", mock.Anything, time.Minute*10).Return(nil)
+
+	assert.NoError(loanFeeUsecase.DeleteLoanFee("Registration"))
+	mockFeeDatabaseRepo.AssertCalled(t, "DeleteItemCollection", "Registration")
+
+	assert.ErrorIs(loanFeeUsecase.DeleteLoanFee("Unknown"), usecases.ErrLoanFeeNotFound)
+	mockFeeDatabaseRepo.AssertNotCalled(t, "DeleteItemCollection", "Unknown")
+}
+
+func TestGetLoanFees_db(t *testing.T) {
+	assert := assert.New(t)
+	setupLoanFee()
+
+	// an empty catalog is cached too
+	mockCacheRepo.On("Get", "loan_fees").Return("", fmt.Errorf("not found"))
+	mockFeeDatabaseRepo.On("GetItemsCollection", "loan_fees").Return([]entities.LoanFee(nil), nil)
+	mockCacheRepo.On("Set", "loan_fees", []byte("[]"), time.Minute*10).Return(nil)
+
+	fees, err := loanFeeUsecase.GetLoanFees()
+
+	assert.NoError(err)
+	assert.Empty(fees)
+	mockCacheRepo.AssertCalled(t, "Set", "loan_fees", []byte("[]"), time.Minute*10)
+}
+
+func TestSeedLoanFees(t *testing.T) {
+	assert := assert.New(t)
+	setupLoanFee()
+
+	mockFeeDatabaseRepo.On("Count", interfaces.Filter{}).Return(int64(0), nil)
+	mockFeeDatabaseRepo.On("SaveItemCollection", mock.Anything).Return(nil)
+
+	assert.NoError(loanFeeUsecase.SeedLoanFees(catalogFees()))
+	mockFeeDatabaseRepo.AssertNumberOfCalls(t, "SaveItemCollection", 3)
+}
+
+func TestSeedLoanFees_normalized(t *testing.T) {
+	assert := assert.New(t)
+	setupLoanFee()
+
+	mockFeeDatabaseRepo.On("Count", interfaces.Filter{}).Return(int64(0), nil)
+	mockFeeDatabaseRepo.On("SaveItemCollection", mock.Anything).Return(nil)
+
+	// the seeds get the codes in upper case and the default payment, like the fees set by the API
+	err := loanFeeUsecase.SeedLoanFees([]entities.LoanFee{
+		{Name: " TAC ", Kind: "origination", Calculation: "percentage", Rate: 1},
+		{Name: "Admin", Kind: "admin", Calculation: "flat", Amount: money.MustParse("5"), Payment: "upfront"},
+	})
+
+	assert.NoError(err)
+	tac := mockFeeDatabaseRepo.Calls[1].Arguments.Get(0).(entities.LoanFee)
+	assert.Equal("TAC", tac.Name)
+	assert.Equal(entities.FeeOrigination, tac.Kind)
+	assert.Equal(entities.FeePercentage, tac.Calculation)
+	assert.Equal(entities.PaymentFinanced, tac.Payment)
+	assert.Equal("system", tac.Author)
+	admin := mockFeeDatabaseRepo.Calls[2].Arguments.Get(0).(entities.LoanFee)
+	assert.Equal(entities.PaymentInstallment, admin.Payment)
+}
+
+func TestSeedLoanFees_invalid(t *testing.T) {
+	assert := assert.New(t)
+	setupLoanFee()
+
+	mockFeeDatabaseRepo.On("Count", interfaces.Filter{}).Return(int64(0), nil)
+
+	seeds := catalogFees()
+	seeds[1].Amount = money.Money{}
+	err := loanFeeUsecase.SeedLoanFees(seeds)
+
+	// no fee is saved, the migration is retried on the next start
+	assert.EqualError(err, "invalid loan fee seeds: Registration: Amount is required above 0 for a flat fee")
+	mockFeeDatabaseRepo.AssertNotCalled(t, "SaveItemCollection", mock.Anything)
+}
+
+func TestLoanFeeMigrations_withoutSeeds(t *testing.T) {
+	assert := assert.New(t)
+	setupLoanFee()
+
+	// without a seed file nothing is recorded, so the catalog can still be seeded later
+	assert.Empty(loanFeeUsecase.LoanFeeMigrations(nil))
+	assert.Len(loanFeeUsecase.LoanFeeMigrations(catalogFees()), 1)
+}
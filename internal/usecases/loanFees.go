@@ -0,0 +1,80 @@
+package usecases
+
+import (
+	"math/big"
+
+	"github.com/Jonattas-21/loan-engine/internal/api/dto"
+	"github.com/Jonattas-21/loan-engine/internal/domain/entities"
+	"github.com/Jonattas-21/loan-engine/package/money"
+)
+
+// LoanFeesFor are the fees of the catalog matching the product and the currency of the request
+func LoanFeesFor(loanFees []entities.LoanFee, simulationRequest dto.SimulationRequest_dto) []entities.LoanFee {
+	var matching []entities.LoanFee
+	for _, loanFee := range loanFees {
+		if matchesAny(loanFee.Products, simulationRequest.Product) && matchesAny(loanFee.Currencies, simulationRequest.Currency) {
+			matching = append(matching, loanFee)
+		}
+	}
+	return matching
+}
+
+// ChargeLoanFees prices the origination and registration fees over the loan amount,
+// and returns the sums of the financed ones, lent with the loan, and of the upfront ones
+func ChargeLoanFees(loanFees []entities.LoanFee, loanAmount money.Money, rounding money.RoundingMode) ([]entities.FeeCharge, money.Money, money.Money) {
+	var charges []entities.FeeCharge
+	financed, upfront := money.Zero(), money.Zero()
+	for _, loanFee := range loanFees {
+		if loanFee.Kind == entities.FeeAdmin {
+			continue
+		}
+
+		amount := loanFee.Amount
+		if loanFee.Calculation == entities.FeePercentage {
+			amount = loanAmount.Mul(new(big.Rat).Quo(money.ExactRat(loanFee.Rate), big.NewRat(100, 1)), rounding)
+		}
+		if loanFee.Payment == entities.PaymentUpfront {
+			upfront = upfront.Add(amount)
+		} else {
+			financed = financed.Add(amount)
+		}
+		charges = append(charges, entities.FeeCharge{Name: loanFee.Name, Kind: loanFee.Kind, Payment: loanFee.Payment, Amount: amount})
+	}
+	return charges, financed, upfront
+}
+
+// ApplyAdminFees adds the admin fees to each installment amortizing the loan, and returns them with their sum
+func ApplyAdminFees(installments []entities.Installment, loanFees []entities.LoanFee) ([]entities.FeeCharge, money.Money) {
+	var charges []entities.FeeCharge
+	total := money.Zero()
+	for _, loanFee := range loanFees {
+		if loanFee.Kind != entities.FeeAdmin {
+			continue
+		}
+
+		charge := entities.FeeCharge{Name: loanFee.Name, Kind: loanFee.Kind, Payment: entities.PaymentInstallment, Amount: money.Zero()}
+		for i := range installments {
+			if installments[i].Kind == entities.InstallmentGrace {
+				continue
+			}
+			installments[i].AdminFeeAmount = installments[i].AdminFeeAmount.Add(loanFee.Amount)
+			installments[i].InstallmentAmount = installments[i].InstallmentAmount.Add(loanFee.Amount)
+			charge.Amount = charge.Amount.Add(loanFee.Amount)
+		}
+		charges = append(charges, charge)
+		total = total.Add(charge.Amount)
+	}
+	return charges, total
+}
+
+// ItemizeCharges lists everything the loan charges above the loan amount: the interest, the IOF, the insurances and the fees
+func ItemizeCharges(interest money.Money, iofAmount money.Money, iofPayment string, insurances []entities.InsuranceCharge, fees []entities.FeeCharge) []entities.FeeCharge {
+	charges := []entities.FeeCharge{
+		{Name: "Interest", Kind: entities.ChargeInterest, Payment: entities.PaymentInstallment, Amount: interest},
+		{Name: "IOF", Kind: entities.ChargeIOF, Payment: iofPayment, Amount: iofAmount},
+	}
+	for _, insurance := range insurances {
+		charges = append(charges, entities.FeeCharge{Name: insurance.Name, Kind: entities.ChargeInsurance, Payment: insurance.Payment, Amount: insurance.PremiumAmount})
+	}
+	return append(charges, fees...)
+}
@@ -0,0 +1,81 @@
+package usecases_test
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/Jonattas-21/loan-engine/internal/domain/entities"
+	"github.com/Jonattas-21/loan-engine/package/money"
+	"github.com/stretchr/testify/assert"
+)
+
+// mockLoanFees charges the fees of the catalog to the simulations
+func mockLoanFees(fees []entities.LoanFee) {
+	jsonFees, _ := json.Marshal(fees)
+	mockCacheRepo.On("Get", "loan_fees").Return(string(jsonFees), nil)
+	loanSimulationUsecase.LoanFee = loanFeeUsecase
+	loanFeeUsecase.CacheRepository = mockCacheRepo
+}
+
+func TestCalculateLoan_loanFees(t *testing.T) {
+	assert := assert.New(t)
+	setupLoanFee()
+	setupSimulation()
+	mockLoanConditions()
+
+	withoutFees, err := loanSimulationUsecase.CalculateLoan(insuranceRequest(""))
+	assert.NoError(err)
+
+	mockLoanFees(catalogFees())
+	loanSimulation, err := loanSimulationUsecase.CalculateLoan(insuranceRequest(""))
+	assert.NoError(err)
+
+	// the TAC of 1% is lent with the loan, the registration paid at the disbursement and the admin fee with each installment
+	assert.Equal("10000.00", loanSimulation.LoanAmount.String())
+	assert.Equal(money.MustParse("10100").Add(loanSimulation.IOFAmount), loanSimulation.FinancedAmount)
+	assert.Equal("50.00", loanSimulation.UpfrontAmount.String())
+	assert.Equal("210.00", loanSimulation.FeesAmount.String())
+	for _, installment := range loanSimulation.Installments {
+		assert.Equal("5.00", installment.AdminFeeAmount.String())
+		assert.Equal(installment.PrincipalAmount.Add(installment.InstallmentFeeAmount).Add(installment.AdminFeeAmount), installment.InstallmentAmount)
+	}
+	assert.Greater(loanSimulation.EffectiveAnnualRate, withoutFees.EffectiveAnnualRate)
+
+	assert.Equal([]entities.FeeCharge{
+		{Name: "Interest", Kind: entities.ChargeInterest, Payment: entities.PaymentInstallment, Amount: loanSimulation.AmountFeeTobePaid},
+		{Name: "IOF", Kind: entities.ChargeIOF, Payment: entities.PaymentFinanced, Amount: loanSimulation.IOFAmount},
+		{Name: "TAC", Kind: entities.FeeOrigination, Payment: entities.PaymentFinanced, Amount: money.MustParse("100")},
+		{Name: "Registration", Kind: entities.FeeRegistration, Payment: entities.PaymentUpfront, Amount: money.MustParse("50")},
+		{Name: "Admin", Kind: entities.FeeAdmin, Payment: entities.PaymentInstallment, Amount: money.MustParse("60")},
+	}, loanSimulation.Fees)
+}
+
+func TestCalculateLoan_loanFeesByProductAndCurrency(t *testing.T) {
+	assert := assert.New(t)
+	setupLoanFee()
+	setupSimulation()
+	mockLoanConditions()
+
+	fees := catalogFees()
+	fees[0].Products = []string{"PAYROLL"}
+	mockLoanFees(fees)
+
+	simulationRequest := insuranceRequest("")
+	simulationRequest.Product = "personal"
+	loanSimulation, err := loanSimulationUsecase.CalculateLoan(simulationRequest)
+	assert.NoError(err)
+	assert.Equal("PERSONAL", loanSimulation.Product)
+	assert.Equal("110.00", loanSimulation.FeesAmount.String())
+
+	simulationRequest.Product = "payroll"
+	loanSimulation, err = loanSimulationUsecase.CalculateLoan(simulationRequest)
+	assert.NoError(err)
+	assert.Equal("210.00", loanSimulation.FeesAmount.String())
+
+	// the fees are in R$ only
+	simulationRequest.Currency = "U$"
+	loanSimulation, err = loanSimulationUsecase.CalculateLoan(simulationRequest)
+	assert.NoError(err)
+	assert.True(loanSimulation.FeesAmount.IsZero())
+	assert.Len(loanSimulation.Fees, 2)
+}
@@ -0,0 +1,92 @@
+package usecases_test
+
+import (
+	"encoding/json"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/Jonattas-21/loan-engine/internal/api/dto"
+	"github.com/Jonattas-21/loan-engine/internal/domain/entities"
+	"github.com/Jonattas-21/loan-engine/internal/domain/interfaces"
+	"github.com/Jonattas-21/loan-engine/internal/usecases"
+	"github.com/Jonattas-21/loan-engine/package/money"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestGetLoanSimulationByID_cache(t *testing.T) {
+	assert := assert.New(t)
+	setupSimulation()
+
+	loanSimulation := entities.LoanSimulation{ID: "01JH8Z5Q4E2V3W6X7Y8Z9A0B1C", Email: "test@example.com", LoanAmount: money.MustParse("10000")}
+	jsonSimulation, _ := json.Marshal(loanSimulation)
+	mockCacheRepo.On("Get", "simulation_id_01JH8Z5Q4E2V3W6X7Y8Z9A0B1C").Return(string(jsonSimulation), nil)
+
+	result, err := loanSimulationUsecase.GetLoanSimulationByID(loanSimulation.ID, "test@example.com")
+
+	assert.NoError(err)
+	assert.Equal(loanSimulation.ID, result.ID)
+	assert.Equal("10000.00", result.LoanAmount.String())
+	mockSimulationDatabaseRepo.AssertNotCalled(t, "FindByID", mock.Anything)
+}
+
+func TestGetLoanSimulationByID_db(t *testing.T) {
+	assert := assert.New(t)
+	setupSimulation()
+
+	loanSimulation := entities.LoanSimulation{ID: "01JH8Z5Q4E2V3W6X7Y8Z9A0B1C", Email: "test@example.com"}
+	jsonSimulation, _ := json.Marshal(loanSimulation)
+	mockCacheRepo.On("Get", "simulation_id_01JH8Z5Q4E2V3W6X7Y8Z9A0B1C").Return("", fmt.Errorf("not found"))
+	mockCacheRepo.On("Set", "simulation_id_01JH8Z5Q4E2V3W6X7Y8Z9A0B1C", jsonSimulation, time.Minute*5).Return(nil)
+	mockSimulationDatabaseRepo.On("FindByID", loanSimulation.ID).Return(loanSimulation, nil)
+
+	result, err := loanSimulationUsecase.GetLoanSimulationByID(loanSimulation.ID, "")
+
+	assert.NoError(err)
+	assert.Equal(loanSimulation.ID, result.ID)
+	mockCacheRepo.AssertCalled(t, "Set", "simulation_id_01JH8Z5Q4E2V3W6X7Y8Z9A0B1C", jsonSimulation, time.Minute*5)
+}
+
+func TestGetLoanSimulationByID_notFound(t *testing.T) {
+	assert := assert.New(t)
+	setupSimulation()
+
+	mockCacheRepo.On("Get", "simulation_id_unknown").Return("", fmt.Errorf("not found"))
+	mockSimulationDatabaseRepo.On("FindByID", "unknown").Return(entities.LoanSimulation{}, interfaces.ErrItemNotFound)
+
+	_, err := loanSimulationUsecase.GetLoanSimulationByID("unknown", "test@example.com")
+	assert.ErrorIs(err, usecases.ErrSimulationNotFound)
+}
+
+func TestGetLoanSimulationByID_otherOwner(t *testing.T) {
+	assert := assert.New(t)
+	setupSimulation()
+
+	loanSimulation := entities.LoanSimulation{ID: "01JH8Z5Q4E2V3W6X7Y8Z9A0B1C", Email: "test@example.com"}
+	jsonSimulation, _ := json.Marshal(loanSimulation)
+	mockCacheRepo.On("Get", "simulation_id_01JH8Z5Q4E2V3W6X7Y8Z9A0B1C").Return(string(jsonSimulation), nil)
+
+	// another user gets the same answer of a simulation that does not exist
+	_, err := loanSimulationUsecase.GetLoanSimulationByID(loanSimulation.ID, "other@example.com")
+	assert.ErrorIs(err, usecases.ErrSimulationNotFound)
+}
+
+func TestSimulationOwner(t *testing.T) {
+	assert := assert.New(t)
+	setupSimulation()
+
+	// the authenticated user owns the requests without email, or with its own in any case
+	requests := []dto.SimulationRequest_dto{{Email: ""}, {Email: "Test@Example.com"}}
+	assert.NoError(loanSimulationUsecase.SimulationOwner(requests, "test@example.com"))
+	assert.Equal("test@example.com", requests[0].Email)
+	assert.Equal("test@example.com", requests[1].Email)
+
+	requests = []dto.SimulationRequest_dto{{Email: "test@example.com"}, {Email: "other@example.com"}}
+	assert.ErrorIs(loanSimulationUsecase.SimulationOwner(requests, "test@example.com"), usecases.ErrSimulationOwner)
+
+	// without authentication the emails of the requests are kept
+	requests = []dto.SimulationRequest_dto{{Email: "other@example.com"}}
+	assert.NoError(loanSimulationUsecase.SimulationOwner(requests, ""))
+	assert.Equal("other@example.com", requests[0].Email)
+}
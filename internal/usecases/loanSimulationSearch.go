@@ -0,0 +1,89 @@
+package usecases
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/Jonattas-21/loan-engine/internal/domain/interfaces"
+	"github.com/Jonattas-21/loan-engine/package/pagination"
+)
+
+const (
+	defaultSearchLimit = 20
+	maxSearchLimit     = 100
+)
+
+// SearchLoanSimulations lists the saved simulations page by page.
+// An authenticated user only sees the own simulations, whatever email was asked.
+func (l *LoanSimulation_usecase) SearchLoanSimulations(query interfaces.LoanSimulationQuery, email string) (interfaces.LoanSimulationPage, error) {
+	query = l.SearchQuery(query)
+	if email != "" {
+		query.Email = email
+	}
+	query.Email = NormalizeEmail(query.Email)
+
+	page, err := l.LoanSimulationRepository.SearchLoanSimulations(query)
+	if err != nil {
+		l.Logger.Errorln(fmt.Sprintf("[email:%v] Error searching loan simulations", query.Email), err.Error())
+		return interfaces.LoanSimulationPage{}, fmt.Errorf("error searching loan simulations, %w", err)
+	}
+	return page, nil
+}
+
+// NormalizeEmail is the email the simulations are saved and searched by, the owner matches it whatever the case
+func NormalizeEmail(email string) string {
+	return strings.ToLower(strings.TrimSpace(email))
+}
+
+// SearchQuery fills the defaults of a search: sorted by the simulation date, 20 per page
+func (l *LoanSimulation_usecase) SearchQuery(query interfaces.LoanSimulationQuery) interfaces.LoanSimulationQuery {
+	query.SortBy = strings.ToLower(strings.TrimSpace(query.SortBy))
+	if query.SortBy == "" {
+		query.SortBy = interfaces.SortBySimulationDate
+	}
+	if query.Limit <= 0 {
+		query.Limit = defaultSearchLimit
+	}
+	return query
+}
+
+func (l *LoanSimulation_usecase) ValidateSearchQuery(query interfaces.LoanSimulationQuery) []string {
+	var errors []string
+	query = l.SearchQuery(query)
+
+	if query.SortBy != interfaces.SortBySimulationDate && query.SortBy != interfaces.SortByLoanAmount && query.SortBy != interfaces.SortByTotalInstallments {
+		errors = append(errors, "Sort must be simulation_date, loan_amount or total_installments")
+	}
+
+	if query.Limit > maxSearchLimit {
+		errors = append(errors, fmt.Sprintf("Limit must be up to %v", maxSearchLimit))
+	}
+
+	if !query.SimulationDateFrom.IsZero() && !query.SimulationDateTo.IsZero() && query.SimulationDateFrom.After(query.SimulationDateTo) {
+		errors = append(errors, "Simulation date from must be before simulation date to")
+	}
+
+	if query.MinLoanAmount.IsNegative() || query.MaxLoanAmount.IsNegative() {
+		errors = append(errors, "Loan amounts must be positive")
+	} else if query.MaxLoanAmount.IsPositive() && query.MinLoanAmount.Cmp(query.MaxLoanAmount) > 0 {
+		errors = append(errors, "Min loan amount must be up to max loan amount")
+	}
+
+	if query.MinInstallments < 0 || query.MaxInstallments < 0 {
+		errors = append(errors, "Installments must be positive")
+	} else if query.MaxInstallments > 0 && query.MinInstallments > query.MaxInstallments {
+		errors = append(errors, "Min installments must be up to max installments")
+	}
+
+	if query.Cursor != "" {
+		if cursor, err := pagination.Decode(query.Cursor); err != nil || cursor.Sort != query.SortBy {
+			errors = append(errors, "Cursor is invalid, it must be the next_cursor of a search with the same sort")
+		}
+	}
+
+	if len(errors) > 0 {
+		return errors
+	}
+
+	return nil
+}
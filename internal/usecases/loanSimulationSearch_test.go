@@ -0,0 +1,95 @@
+package usecases_test
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/Jonattas-21/loan-engine/internal/domain/entities"
+	"github.com/Jonattas-21/loan-engine/internal/domain/interfaces"
+	"github.com/Jonattas-21/loan-engine/package/money"
+	"github.com/Jonattas-21/loan-engine/package/pagination"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSearchLoanSimulations_defaults(t *testing.T) {
+	assert := assert.New(t)
+	setupSimulation()
+
+	expectedQuery := interfaces.LoanSimulationQuery{Currency: "R$", SortBy: interfaces.SortBySimulationDate, Limit: 20}
+	page := interfaces.LoanSimulationPage{LoanSimulations: []entities.LoanSimulation{{ID: "01JH8Z5Q4E2V3W6X7Y8Z9A0B1C"}}, NextCursor: "next"}
+	mockSimulationDatabaseRepo.On("SearchLoanSimulations", expectedQuery).Return(page, nil)
+
+	result, err := loanSimulationUsecase.SearchLoanSimulations(interfaces.LoanSimulationQuery{Currency: "R$"}, "")
+
+	assert.NoError(err)
+	assert.Equal(page, result)
+}
+
+func TestSearchLoanSimulations_owner(t *testing.T) {
+	assert := assert.New(t)
+	setupSimulation()
+
+	// the authenticated email replaces the asked one, in the case it is saved in
+	expectedQuery := interfaces.LoanSimulationQuery{Email: "test@example.com", SortBy: interfaces.SortByLoanAmount, Limit: 10}
+	mockSimulationDatabaseRepo.On("SearchLoanSimulations", expectedQuery).Return(interfaces.LoanSimulationPage{}, fmt.Errorf("timeout"))
+
+	_, err := loanSimulationUsecase.SearchLoanSimulations(interfaces.LoanSimulationQuery{Email: "other@example.com", SortBy: "LOAN_AMOUNT", Limit: 10}, "Test@Example.com")
+
+	assert.EqualError(err, "error searching loan simulations, timeout")
+	mockSimulationDatabaseRepo.AssertExpectations(t)
+}
+
+func TestValidateSearchQuery(t *testing.T) {
+	assert := assert.New(t)
+	setupSimulation()
+
+	assert.Nil(loanSimulationUsecase.ValidateSearchQuery(interfaces.LoanSimulationQuery{MinLoanAmount: money.MustParse("1000"), MaxInstallments: 24}))
+
+	errors := loanSimulationUsecase.ValidateSearchQuery(interfaces.LoanSimulationQuery{
+		SortBy:             "email",
+		Limit:              500,
+		SimulationDateFrom: date(2025, 2, 1),
+		SimulationDateTo:   date(2025, 1, 1),
+		MinLoanAmount:      money.MustParse("5000"),
+		MaxLoanAmount:      money.MustParse("1000"),
+		MinInstallments:    24,
+		MaxInstallments:    12,
+	})
+	assert.Equal([]string{
+		"Sort must be simulation_date, loan_amount or total_installments",
+		"Limit must be up to 100",
+		"Simulation date from must be before simulation date to",
+		"Min loan amount must be up to max loan amount",
+		"Min installments must be up to max installments",
+	}, errors)
+}
+
+func TestValidateSearchQuery_cursor(t *testing.T) {
+	assert := assert.New(t)
+	setupSimulation()
+
+	last := pagination.Cursor{Sort: interfaces.SortBySimulationDate, Value: "2025-01-01T00:00:00Z", ID: "01JH8Z5Q4E2V3W6X7Y8Z9A0B1C"}
+	assert.Nil(loanSimulationUsecase.ValidateSearchQuery(interfaces.LoanSimulationQuery{Cursor: pagination.Encode(last)}))
+
+	// a tampered cursor, or one of another sort, is rejected before the search
+	invalid := []string{"Cursor is invalid, it must be the next_cursor of a search with the same sort"}
+	assert.Equal(invalid, loanSimulationUsecase.ValidateSearchQuery(interfaces.LoanSimulationQuery{Cursor: "not a cursor"}))
+	assert.Equal(invalid, loanSimulationUsecase.ValidateSearchQuery(interfaces.LoanSimulationQuery{Cursor: pagination.Encode(last), SortBy: interfaces.SortByLoanAmount}))
+}
+
+func TestSearchLoanSimulations_emailCase(t *testing.T) {
+	assert := assert.New(t)
+	setupSimulation()
+	mockLoanConditions()
+
+	loanSimulation, err := loanSimulationUsecase.CalculateLoan(scoreRequest(" Test@Example.com"))
+	assert.NoError(err)
+	assert.Equal("test@example.com", loanSimulation.Email)
+
+	expectedQuery := interfaces.LoanSimulationQuery{Email: "test@example.com", SortBy: interfaces.SortBySimulationDate, Limit: 20}
+	mockSimulationDatabaseRepo.On("SearchLoanSimulations", expectedQuery).Return(interfaces.LoanSimulationPage{}, nil)
+
+	_, err = loanSimulationUsecase.SearchLoanSimulations(interfaces.LoanSimulationQuery{Email: "TEST@example.com"}, "")
+	assert.NoError(err)
+	mockSimulationDatabaseRepo.AssertExpectations(t)
+}
@@ -2,57 +2,106 @@ package usecases
 
 import (
 	"bytes"
+	"crypto/sha256"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"html/template"
 	"math/big"
+	"strings"
 	"time"
 
-	"math"
-
 	"github.com/Jonattas-21/loan-engine/internal/api/dto"
 	"github.com/Jonattas-21/loan-engine/internal/domain/entities"
 	"github.com/Jonattas-21/loan-engine/internal/domain/interfaces"
+	"github.com/Jonattas-21/loan-engine/package/money"
+	"github.com/Jonattas-21/loan-engine/package/ulid"
 )
 
+// ErrSimulationNotFound is returned when the simulation does not exist or belongs to another user
+var ErrSimulationNotFound = errors.New("loan simulation not found")
+
+// ErrSimulationOwner is returned when an authenticated user requests simulations for another email
+var ErrSimulationOwner = errors.New("simulations can only be requested for the authenticated email")
+
 type LoanSimulation interface {
 	GetLoanSimulation(SimulationRequests []dto.SimulationRequest_dto) ([]entities.LoanCondition, []error)
+	GetLoanSimulationByID(id string, email string) (entities.LoanSimulation, error)
+	SearchLoanSimulations(query interfaces.LoanSimulationQuery, email string) (interfaces.LoanSimulationPage, error)
 	CalculateLoan(SimulationRequest dto.SimulationRequest_dto) (entities.LoanCondition, error)
-	TruncateToTwoDecimals(value float64) float64
-	CalculatePower(base *big.Float, exponent int) *big.Float
+	CalculatePower(base *big.Rat, exponent int) *big.Rat
 	SendLoanSimulationEmailMessage(loanSimulation entities.LoanSimulation) error
-	CreateInstallments(simulationRequest dto.SimulationRequest_dto, installmentValue *big.Float) []entities.Installment
+	CreateInstallments(simulationRequest dto.SimulationRequest_dto, monthlyInterestRate *big.Rat) ([]entities.Installment, error)
 }
 
 type LoanSimulation_usecase struct {
-	LoanSimulationRepository interfaces.Repository[entities.LoanSimulation]
+	LoanSimulationRepository interfaces.LoanSimulationRepository
 	CacheRepository          interfaces.CacheRepository
 	EmailSender              interfaces.EmailSender
 	LoanCondition            LoanCondition
+	LoanFee                  LoanFee // fee catalog, no fee is charged without it
 	Logger                   interfaces.Log
 	QueuePublisher           interfaces.Queue
+	AmortizationCalculators  map[string]AmortizationCalculator
+	RoundingMode             money.RoundingMode
+	IOFCalculator            *IOFCalculator
+	HolidayCalendar          interfaces.HolidayCalendar
+	MaxInstallments          int                          // longest term offered, used when solving the term
+	PricingRules             []entities.PricingRule       // policy rules, matched before the age tiers of the same priority
+	EligibilityPolicies      []entities.EligibilityPolicy // limits a simulation must meet, by currency
+	MaxDebtToIncome          float64                      // percentage of the income the installments may take, 35 when not informed
+	AffordabilityAction      string                       // DECLINE (default) or FLAG the offers above the debt to income limit
+	CreditScoreProvider      interfaces.CreditScoreProvider
+	ScoreBands               []entities.ScoreBand         // spreads by credit score, none when empty
+	RecommendationRule       string                       // LOWEST_CET (default), LOWEST_TOTAL or LOWEST_INSTALLMENT offer of a ladder
+	InsuranceProducts        []entities.InsuranceProduct  // insurances the simulations may add
+	RateIndexProvider        interfaces.RateIndexProvider // index registry of the post-fixed loans, only assumed indexes without it
 }
 
-func (l *LoanSimulation_usecase) GetLoanSimulation(SimulationRequests []dto.SimulationRequest_dto) ([]entities.LoanSimulation, []string) {
+// GetLoanSimulation simulates the requests concurrently, the ladder requests give their offers grouped
+func (l *LoanSimulation_usecase) GetLoanSimulation(SimulationRequests []dto.SimulationRequest_dto) ([]entities.LoanSimulation, []entities.OfferLadder, []entities.SimulationError) {
 	var simulationResponses []entities.LoanSimulation
-	var errorsResponse []string
+	var ladderResponses []entities.OfferLadder
+	var errorsResponse []entities.SimulationError
 	simulatorChan := make(chan entities.LoanSimulation)
-	errorChan := make(chan error)
+	ladderChan := make(chan entities.OfferLadder)
+	errorChan := make(chan entities.SimulationError)
 	doneChan := make(chan bool)
 
 	//loop through all simulation requests
-	for _, simulationRequest := range SimulationRequests {
-		go func(simulationRequest dto.SimulationRequest_dto) {
+	for index, simulationRequest := range SimulationRequests {
+		go func(index int, simulationRequest dto.SimulationRequest_dto) {
 			var loanSimulation entities.LoanSimulation
 
 			//validate request
 			errors := l.ValidateSimulationRequest(simulationRequest)
 			if errors != nil {
-				errorChan <- fmt.Errorf("[email:%v] error validating simulation request: %v",simulationRequest.Email, errors )
+				errorChan <- l.InvalidRequestError(index, simulationRequest.Email, errors)
+				return
+			}
+
+			if IsOfferLadder(simulationRequest) {
+				ladder, err := l.CalculateOfferLadder(simulationRequest)
+				if err != nil {
+					errorChan <- l.SimulationError(index, simulationRequest.Email, fmt.Errorf("error calculating loan offers, %w", err))
+					return
+				}
+				ladder.RequestIndex = index
+
+				for _, offer := range ladder.Offers {
+					err = l.LoanSimulationRepository.SaveItemCollection(offer)
+					if err != nil {
+						l.Logger.Errorln(fmt.Sprintf("[email:%v] Error saving loan offer", simulationRequest.Email), err.Error())
+						errorChan <- l.SimulationError(index, simulationRequest.Email, fmt.Errorf("error saving loan offer, %v", err.Error()))
+						return
+					}
+					l.cacheSimulationByID(offer)
+				}
+				ladderChan <- ladder
 				return
 			}
 
-			keyRedis := fmt.Sprintf("simulation_%v_%v_%v", simulationRequest.Email, simulationRequest.LoanAmount, simulationRequest.Installments)
+			keyRedis := l.SimulationCacheKey(simulationRequest)
 
 			//check if the request is in cache
 			value, err := l.CacheRepository.Get(keyRedis)
@@ -75,7 +124,7 @@ func (l *LoanSimulation_usecase) GetLoanSimulation(SimulationRequests []dto.Simu
 			//calculate loan if not in cache
 			simulationResponse, err := l.CalculateLoan(simulationRequest)
 			if err != nil {
-				errorChan <- fmt.Errorf("[email:%v] error calculating loan, %v", simulationRequest.Email ,err.Error())
+				errorChan <- l.SimulationError(index, simulationRequest.Email, fmt.Errorf("error calculating loan, %w", err))
 				return
 			}
 
@@ -93,8 +142,10 @@ func (l *LoanSimulation_usecase) GetLoanSimulation(SimulationRequests []dto.Simu
 			err = l.LoanSimulationRepository.SaveItemCollection(simulationResponse)
 			if err != nil {
 				l.Logger.Errorln(fmt.Sprintf("[email:%v] Error saving loan simulation", simulationRequest.Email), err.Error())
-				errorChan <- fmt.Errorf("error saving loan simulation, %v", err.Error())
+				errorChan <- l.SimulationError(index, simulationRequest.Email, fmt.Errorf("error saving loan simulation, %v", err.Error()))
+				return
 			}
+			l.cacheSimulationByID(simulationResponse)
 
 			//send email
 			err = l.SendLoanSimulationEmailMessage(loanSimulation)
@@ -103,7 +154,7 @@ func (l *LoanSimulation_usecase) GetLoanSimulation(SimulationRequests []dto.Simu
 				l.Logger.Errorln(fmt.Sprintf("[email:%v] Error sending email for loan simulation", simulationRequest.Email), err.Error())
 			}
 			simulatorChan <- simulationResponse
-		}(simulationRequest)
+		}(index, simulationRequest)
 	}
 
 	// Collect async results
@@ -121,10 +172,12 @@ func (l *LoanSimulation_usecase) GetLoanSimulation(SimulationRequests []dto.Simu
 				// }
 				// l.QueuePublisher.PublishMessage(os.Getenv("RABBITMQ_PUBLISH_QUEUE") , string(jsonConditions))
 
-			case err := <-errorChan:
-				errorFormated := fmt.Errorf("error processing simulation: %v for request: %v", err.Error(), SimulationRequests[i])
-				l.Logger.Errorln(errorFormated)
-				errorsResponse = append(errorsResponse, errorFormated.Error())
+			case ladder := <-ladderChan:
+				ladderResponses = append(ladderResponses, ladder)
+
+			case simulationError := <-errorChan:
+				l.Logger.Errorln(fmt.Sprintf("error processing simulation: %v for request: %v", simulationError.Reasons, SimulationRequests[simulationError.RequestIndex]))
+				errorsResponse = append(errorsResponse, simulationError)
 			}
 		}
 		doneChan <- true
@@ -133,113 +186,402 @@ func (l *LoanSimulation_usecase) GetLoanSimulation(SimulationRequests []dto.Simu
 	// Wait for completion all the simulations
 	<-doneChan
 
-	return simulationResponses, errorsResponse
+	return simulationResponses, ladderResponses, errorsResponse
 }
 
 func (l *LoanSimulation_usecase) CalculateLoan(SimulationRequest dto.SimulationRequest_dto) (entities.LoanSimulation, error) {
-	//get fee conditions
-	conditions, err := l.LoanCondition.GetLoanConditions()
+	pricing, err := l.PricingFor(SimulationRequest, time.Now())
+	if err != nil {
+		return entities.LoanSimulation{}, err
+	}
+	return l.calculateLoan(SimulationRequest, pricing)
+}
+
+// calculateLoan solves the request by its mode and checks the result against the policies
+func (l *LoanSimulation_usecase) calculateLoan(SimulationRequest dto.SimulationRequest_dto, pricing Pricing) (entities.LoanSimulation, error) {
+	var err error
+	var loanSimulation entities.LoanSimulation
+	switch l.SimulationModeName(SimulationRequest.SimulationMode) {
+	case SimulationModeInstallment:
+		loanSimulation, err = l.SolveLoanAmount(SimulationRequest, pricing)
+	case SimulationModeTerm:
+		loanSimulation, err = l.SolveInstallments(SimulationRequest, pricing)
+	default:
+		loanSimulation, err = l.Simulate(SimulationRequest, pricing)
+	}
+	if err != nil {
+		return entities.LoanSimulation{}, err
+	}
+
+	reasons := l.CheckEligibility(loanSimulation, SimulationRequest.BithDate)
+	reasons = append(reasons, l.CheckAffordability(&loanSimulation, SimulationRequest)...)
+	if reasons != nil {
+		return entities.LoanSimulation{}, &DeclinedError{Reasons: reasons}
+	}
+
+	loanSimulation.ID, err = ulid.New(loanSimulation.SimulationDate)
+	if err != nil {
+		return entities.LoanSimulation{}, err
+	}
+	return loanSimulation, nil
+}
+
+// SimulationOwner makes the authenticated user the owner of the requests, the ones without email get it and the ones
+// for another email are refused. An empty email keeps the emails of the requests when the routes are not protected.
+func (l *LoanSimulation_usecase) SimulationOwner(simulationRequests []dto.SimulationRequest_dto, email string) error {
+	if email == "" {
+		return nil
+	}
+	for i := range simulationRequests {
+		if strings.TrimSpace(simulationRequests[i].Email) != "" && !strings.EqualFold(strings.TrimSpace(simulationRequests[i].Email), email) {
+			return ErrSimulationOwner
+		}
+		simulationRequests[i].Email = email
+	}
+	return nil
+}
+
+// GetLoanSimulationByID reads a saved simulation from the cache, then from the database.
+// A simulation of another email is not found, an empty email skips the check when the routes are not protected.
+func (l *LoanSimulation_usecase) GetLoanSimulationByID(id string, email string) (entities.LoanSimulation, error) {
+	var loanSimulation entities.LoanSimulation
+	keyRedis := l.SimulationIDCacheKey(id)
+
+	value, err := l.CacheRepository.Get(keyRedis)
+	if err == nil {
+		err = json.Unmarshal([]byte(value), &loanSimulation)
+		if err != nil {
+			l.Logger.Errorln(fmt.Sprintf("[id:%v] Error unmarshalling loan simulation from cache", id), err.Error())
+		}
+	}
+
 	if err != nil {
-		l.Logger.Errorln(fmt.Printf("Error getting loan conditions, %v", err.Error()))
-		return entities.LoanSimulation{}, fmt.Errorf("error getting loan conditions, %v", err.Error())
+		loanSimulation, err = l.LoanSimulationRepository.FindByID(id)
+		if errors.Is(err, interfaces.ErrItemNotFound) {
+			return entities.LoanSimulation{}, ErrSimulationNotFound
+		}
+		if err != nil {
+			l.Logger.Errorln(fmt.Sprintf("[id:%v] Error getting loan simulation", id), err.Error())
+			return entities.LoanSimulation{}, fmt.Errorf("error getting loan simulation, %v", err.Error())
+		}
+		l.cacheSimulationByID(loanSimulation)
+	}
+
+	if email != "" && !strings.EqualFold(loanSimulation.Email, email) {
+		return entities.LoanSimulation{}, ErrSimulationNotFound
 	}
+	return loanSimulation, nil
+}
 
-	//calculate age
-	today := time.Now()
-	age := today.Year() - SimulationRequest.BithDate.Year()
+// cacheSimulationByID keeps the simulation for the reads by id, if not, let's just log the error and continue
+func (l *LoanSimulation_usecase) cacheSimulationByID(loanSimulation entities.LoanSimulation) {
+	jsonSimulation, err := json.Marshal(loanSimulation)
+	if err != nil {
+		l.Logger.Errorln(fmt.Sprintf("[id:%v] error marshalling loan simulation: %v", loanSimulation.ID, err.Error()))
+		return
+	}
 
-	// adjust the age if the birthdate has not occurred yet this year
-	if today.YearDay() < SimulationRequest.BithDate.YearDay() {
-		age--
+	err = l.CacheRepository.Set(l.SimulationIDCacheKey(loanSimulation.ID), jsonSimulation, time.Minute*5)
+	if err != nil {
+		l.Logger.Errorln(fmt.Sprintf("[id:%v] error setting loan simulation in cache", loanSimulation.ID), err.Error())
 	}
+}
 
-	//get interest rate
-	var interestRate big.Float
-	for _, condition := range conditions {
-		if age >= condition.MinAge && age <= condition.MaxAge {
-			interestRate = *big.NewFloat(condition.InterestRate)
+// Simulate builds the simulation of the requested amount and term at the annual interest rate of the matching pricing rule
+func (l *LoanSimulation_usecase) Simulate(SimulationRequest dto.SimulationRequest_dto, pricing Pricing) (entities.LoanSimulation, error) {
+	pricingRule, loanCondition, err := l.PriceLoan(SimulationRequest, pricing)
+	if err != nil {
+		return entities.LoanSimulation{}, err
+	}
+	interestRateFloat := pricingRule.InterestRate + pricing.ScoreBand.Spread
+	amortizationSystem := l.AmortizationSystemName(SimulationRequest.AmortizationSystem)
+	l.Logger.Infoln(fmt.Sprintf("input fro calc: rate %v, instalmentsN %v, pv %v, system %v", interestRateFloat, SimulationRequest.Installments, SimulationRequest.LoanAmount, amortizationSystem))
+
+	// Creating instalment by month, the totals come from the schedule so they match the sum of the installments
+	simulationDate := time.Now()
+	disbursementDate := l.DisbursementDate(SimulationRequest, simulationDate)
+	SimulationRequest.DisbursementDate = disbursementDate
+	gracePeriod := l.GracePeriod(SimulationRequest)
+
+	//calculate monthly rate from the annual percentage, kept as an exact fraction. The rate of a post-fixed
+	//loan is the spread over the index of each period, the one of the disbursement is quoted
+	monthlyInterestRate := new(big.Rat).Quo(money.ExactRat(interestRateFloat), big.NewRat(12*100, 1))
+	annualInterestRate := interestRateFloat
+	indexCurve := IndexCurveFor(SimulationRequest, pricing, interestRateFloat)
+	if indexCurve != nil {
+		monthlyInterestRate = indexCurve.MonthlyRateAt(disbursementDate)
+		annualInterestRate, _ = indexCurve.AnnualRateAt(disbursementDate).Float64()
+	}
+
+	// the financed insurance premiums and fees are lent with the loan amount
+	insurances := l.InsuranceCharges(SimulationRequest)
+	loanFees := LoanFeesFor(pricing.LoanFees, SimulationRequest)
+	feeCharges, financedFees, upfrontFees := ChargeLoanFees(loanFees, SimulationRequest.LoanAmount, l.RoundingMode)
+	creditRequest := SimulationRequest
+	creditRequest.LoanAmount = SimulationRequest.LoanAmount.Add(financedFees)
+
+	installments, err := l.createInstallments(creditRequest, monthlyInterestRate, indexCurve)
+	if err != nil {
+		return entities.LoanSimulation{}, err
+	}
+	// the premiums are priced over the schedule without them, then lent with the loan
+	if financedInsurance := FinanceInsurancePremiums(insurances, installments, l.RoundingMode); financedInsurance.IsPositive() {
+		creditRequest.LoanAmount = creditRequest.LoanAmount.Add(financedInsurance)
+		installments, err = l.createInstallments(creditRequest, monthlyInterestRate, indexCurve)
+		if err != nil {
+			return entities.LoanSimulation{}, err
 		}
 	}
 
-	//check if interest rate was found
-	interestRateFloat, _ := interestRate.Float64()
-	if interestRateFloat == 0 {
-		return entities.LoanSimulation{}, fmt.Errorf("interest rate not found for age %v", age)
-	}
-
-	l.Logger.Infoln(fmt.Sprintf("input fro calc: rate %v, age %v, instalmentsN %v, pv %v", interestRateFloat, age, SimulationRequest.Installments, SimulationRequest.LoanAmount))
-
-	//calculate instalment from a given loan value
-	monthlyInterestRate := new(big.Float).Quo(&interestRate, big.NewFloat(12*100))
-	one := big.NewFloat(1)
-	l.Logger.Infoln(fmt.Sprintf("monthlyInterestRate %v", monthlyInterestRate))
-
-	// 1 + r
-	onePlusRate := new(big.Float).Add(one, monthlyInterestRate)
-	l.Logger.Infoln(fmt.Sprintf("onePlusRate %v", onePlusRate))
-
-	// (1 + r)^n
-	ratePower := l.CalculatePower(onePlusRate, SimulationRequest.Installments)
-	l.Logger.Infoln(fmt.Sprintf("ratePower %v", ratePower))
-	loanAmountBig := big.NewFloat(SimulationRequest.LoanAmount)
-	l.Logger.Infoln(fmt.Sprintf("loanAmountBig %v", loanAmountBig))
-	numerator := new(big.Float).Mul(loanAmountBig, monthlyInterestRate)
-	l.Logger.Infoln(fmt.Sprintf("numerator %v", numerator))
-	numerator.Mul(numerator, ratePower)
-
-	// (1 + r)^n - 1
-	denominator := new(big.Float).Sub(ratePower, one)
-	l.Logger.Infoln(fmt.Sprintf("denominator %v", denominator))
-	InstallmentValue := new(big.Float).Quo(numerator, denominator)
-	l.Logger.Infoln(fmt.Sprintf("InstallmentValue %v", InstallmentValue))
-
-	// Creating instalment by month
-	totalAmountTobePaid := new(big.Float).Mul(InstallmentValue, big.NewFloat(float64(SimulationRequest.Installments)))
-	totalAmountTobePaid_float, _ := totalAmountTobePaid.Float64()
-
-	//calculate fee
-	amountFeeTobePaid := totalAmountTobePaid_float - SimulationRequest.LoanAmount
-
-	return entities.LoanSimulation{
-		LoanAmount:          l.TruncateToTwoDecimals(SimulationRequest.LoanAmount),
-		AmountTobePaid:      l.TruncateToTwoDecimals(totalAmountTobePaid_float),
-		AmountFeeTobePaid:   l.TruncateToTwoDecimals(amountFeeTobePaid),
-		FeeAmountPercentage: interestRateFloat,
-		TotalInstallments:   SimulationRequest.Installments,
-		SimulationDate:      time.Now(),
-		Currency:            SimulationRequest.Currency,
-		Email:               SimulationRequest.Email,
-		Installments:        l.CreateInstallments(SimulationRequest, InstallmentValue),
-	}, nil
+	//calculate IOF tax over the amortized principal
+	iofPayment := l.PaymentModeName(SimulationRequest.IOFPayment)
+	iofAmount := l.iofCalculator().Calculate(disbursementDate, installments, l.RoundingMode)
+	financedAmount := creditRequest.LoanAmount
+	upfrontAmount := upfrontFees
+
+	if iofPayment == entities.PaymentFinanced && iofAmount.IsPositive() {
+		// the tax is proportional to the principal, so the financed amount is grossed up: F = PV / (1 - IOF/PV)
+		iofShare := new(big.Rat).Quo(iofAmount.Rat(), creditRequest.LoanAmount.Rat())
+		financedAmount = money.FromRat(new(big.Rat).Quo(creditRequest.LoanAmount.Rat(), new(big.Rat).Sub(big.NewRat(1, 1), iofShare)), l.RoundingMode)
+
+		financedRequest := creditRequest
+		financedRequest.LoanAmount = financedAmount
+		installments, err = l.createInstallments(financedRequest, monthlyInterestRate, indexCurve)
+		if err != nil {
+			return entities.LoanSimulation{}, err
+		}
+		iofAmount = financedAmount.Sub(creditRequest.LoanAmount)
+	} else {
+		upfrontAmount = upfrontAmount.Add(iofAmount)
+	}
+
+	installmentInsurance := ApplyInsurancePremiums(installments, insurances, l.RoundingMode)
+	insuranceAmount := money.Zero()
+	for _, insurance := range insurances {
+		insuranceAmount = insuranceAmount.Add(insurance.PremiumAmount)
+	}
+
+	adminCharges, adminFees := ApplyAdminFees(installments, loanFees)
+	feeCharges = append(feeCharges, adminCharges...)
+	feesAmount := financedFees.Add(upfrontFees).Add(adminFees)
+
+	// the interest is everything paid above the financed amount, capitalized grace interest included and the premiums and fees paid with the installments excluded
+	var totalAmountTobePaid money.Money
+	for _, installment := range installments {
+		totalAmountTobePaid = totalAmountTobePaid.Add(installment.InstallmentAmount)
+	}
+	amountFeeTobePaid := totalAmountTobePaid.Sub(financedAmount).Sub(installmentInsurance).Sub(adminFees)
+
+	loanSimulation := entities.LoanSimulation{
+		LoanAmount:           SimulationRequest.LoanAmount,
+		FinancedAmount:       financedAmount,
+		UpfrontAmount:        upfrontAmount,
+		AmountTobePaid:       totalAmountTobePaid,
+		AmountFeeTobePaid:    amountFeeTobePaid,
+		IOFAmount:            iofAmount,
+		IOFPayment:           iofPayment,
+		InsuranceAmount:      insuranceAmount,
+		Insurances:           insurances,
+		FeesAmount:           feesAmount,
+		Fees:                 ItemizeCharges(amountFeeTobePaid, iofAmount, iofPayment, insurances, feeCharges),
+		FeeAmountPercentage:  annualInterestRate,
+		LoanConditionID:      loanCondition.ID,
+		LoanConditionName:    loanCondition.Name,
+		LoanConditionVersion: loanCondition.Version,
+		PricingRuleID:        pricingRule.ID,
+		PricingRuleName:      pricingRule.Name,
+		PricingRuleKind:      pricingRule.Kind,
+		Segment:              strings.ToUpper(strings.TrimSpace(SimulationRequest.Segment)),
+		Product:              strings.ToUpper(strings.TrimSpace(SimulationRequest.Product)),
+		CreditScore:          pricing.CreditScore,
+		CreditScoreBand:      pricing.ScoreBand.Name,
+		CreditScoreSpread:    pricing.ScoreBand.Spread,
+		Estimated:            indexCurve != nil,
+		AmortizationSystem:   amortizationSystem,
+		TotalInstallments:    SimulationRequest.Installments,
+		SimulationDate:       simulationDate,
+		DisbursementDate:     disbursementDate,
+		PaymentDay:           SimulationRequest.PaymentDay,
+		GracePeriod:          gracePeriod.Length,
+		GracePeriodUnit:      gracePeriod.Unit,
+		GraceInterest:        gracePeriod.Interest,
+		SimulationMode:       l.SimulationModeName(SimulationRequest.SimulationMode),
+		Currency:             SimulationRequest.Currency,
+		Email:                NormalizeEmail(SimulationRequest.Email),
+		Installments:         installments,
+	}
+
+	if indexCurve != nil {
+		loanSimulation.RateIndex = indexCurve.Index
+		loanSimulation.IndexSpread = interestRateFloat
+	}
+
+	//calculate the total effective cost (CET) from the real cash flows, projected for a post-fixed loan
+	loanSimulation.EffectiveMonthlyRate, loanSimulation.EffectiveAnnualRate, err = CalculateEffectiveRates(SimulationCashFlows(loanSimulation))
+	if err != nil {
+		return entities.LoanSimulation{}, fmt.Errorf("error calculating effective rates, %v", err.Error())
+	}
+
+	return loanSimulation, nil
+}
+
+func (l *LoanSimulation_usecase) CalculatePower(base *big.Rat, exponent int) *big.Rat {
+	return calculatePower(base, exponent)
 }
 
-func (l *LoanSimulation_usecase) CalculatePower(base *big.Float, exponent int) *big.Float {
-	result := big.NewFloat(1)
-	for i := 0; i < exponent; i++ {
-		result.Mul(result, base)
+// SimulationCacheKey identifies a request by all of its options, the hash keeps the key short as the options grow
+func (l *LoanSimulation_usecase) SimulationCacheKey(simulationRequest dto.SimulationRequest_dto) string {
+	jsonRequest, _ := json.Marshal(simulationRequest)
+	return fmt.Sprintf("simulation_%v_%x", simulationRequest.Email, sha256.Sum256(jsonRequest))
+}
+
+// SimulationIDCacheKey identifies a saved simulation for the reads by id
+func (l *LoanSimulation_usecase) SimulationIDCacheKey(id string) string {
+	return fmt.Sprintf("simulation_id_%v", id)
+}
+
+// AmortizationSystemName normalizes the requested system, PRICE is the default when none is informed
+func (l *LoanSimulation_usecase) AmortizationSystemName(amortizationSystem string) string {
+	if strings.TrimSpace(amortizationSystem) == "" {
+		return AmortizationPrice
+	}
+	return strings.ToUpper(strings.TrimSpace(amortizationSystem))
+}
+
+// SimulationModeName normalizes what the request solves for, AMOUNT is the default when none is informed
+func (l *LoanSimulation_usecase) SimulationModeName(simulationMode string) string {
+	if strings.TrimSpace(simulationMode) == "" {
+		return SimulationModeAmount
+	}
+	return strings.ToUpper(strings.TrimSpace(simulationMode))
+}
+
+// PaymentModeName normalizes how a charge is paid, FINANCED is the default when none is informed
+func (l *LoanSimulation_usecase) PaymentModeName(paymentMode string) string {
+	if strings.TrimSpace(paymentMode) == "" {
+		return entities.PaymentFinanced
+	}
+	return strings.ToUpper(strings.TrimSpace(paymentMode))
+}
+
+func (l *LoanSimulation_usecase) iofCalculator() *IOFCalculator {
+	if l.IOFCalculator != nil {
+		return l.IOFCalculator
+	}
+	return DefaultIOFCalculator()
+}
+
+func (l *LoanSimulation_usecase) amortizationCalculators() map[string]AmortizationCalculator {
+	if l.AmortizationCalculators != nil {
+		return l.AmortizationCalculators
+	}
+	return DefaultAmortizationCalculators()
+}
+
+// DisbursementDate is the requested disbursement day, or the simulation day when none is informed
+func (l *LoanSimulation_usecase) DisbursementDate(simulationRequest dto.SimulationRequest_dto, simulationDate time.Time) time.Time {
+	date := simulationRequest.DisbursementDate
+	if date.IsZero() {
+		date = simulationDate
+	}
+	return time.Date(date.Year(), date.Month(), date.Day(), 0, 0, 0, 0, time.UTC)
+}
+
+// GracePeriod reads the grace options of the request, months and capitalized interest are the defaults
+func (l *LoanSimulation_usecase) GracePeriod(simulationRequest dto.SimulationRequest_dto) GracePeriod {
+	gracePeriod := GracePeriod{
+		Length:   simulationRequest.GracePeriod,
+		Unit:     strings.ToUpper(strings.TrimSpace(simulationRequest.GracePeriodUnit)),
+		Interest: strings.ToUpper(strings.TrimSpace(simulationRequest.GraceInterest)),
+	}
+	if gracePeriod.Unit == "" {
+		gracePeriod.Unit = GraceUnitMonths
 	}
-	return result
+	if gracePeriod.Interest == "" {
+		gracePeriod.Interest = GraceInterestCapitalized
+	}
+	return gracePeriod
 }
 
-func (l *LoanSimulation_usecase) TruncateToTwoDecimals(value float64) float64 {
-	factor := math.Pow(10, 2)
-	truncatedValue := math.Trunc(value*factor) / factor
-	return truncatedValue
+// CreateInstallments builds the amortization schedule with the calculator of the requested system, after the grace lines if any.
+// Interest runs over the nominal due dates, the installments are due on the next business day.
+func (l *LoanSimulation_usecase) CreateInstallments(simulationRequest dto.SimulationRequest_dto, monthlyInterestRate *big.Rat) ([]entities.Installment, error) {
+	return l.createInstallments(simulationRequest, monthlyInterestRate, nil)
 }
 
-func (l *LoanSimulation_usecase) CreateInstallments(simulationRequest dto.SimulationRequest_dto, installmentValue *big.Float) []entities.Installment {
-	var returnInstallments []entities.Installment
-	for i := 0; i < simulationRequest.Installments; i++ {
-		installmentValueFloat, _ := installmentValue.Float64()
-		installment := entities.Installment{
-			InstallmentNumber:    i + 1,
-			InstallmentAmount:    l.TruncateToTwoDecimals(installmentValueFloat),
-			//InstallmentFeeAmount: l.TruncateToTwoDecimals(installmentValueFloat), //todo
-			Currency:             simulationRequest.Currency,
+// createInstallments builds the schedule, a post-fixed loan at the rate of the index curve in the month each period starts.
+// The grace lines run at the monthly interest rate, the one of the disbursement.
+func (l *LoanSimulation_usecase) createInstallments(simulationRequest dto.SimulationRequest_dto, monthlyInterestRate *big.Rat, indexCurve *IndexCurve) ([]entities.Installment, error) {
+	amortizationSystem := l.AmortizationSystemName(simulationRequest.AmortizationSystem)
+	calculator, ok := l.amortizationCalculators()[amortizationSystem]
+	if !ok {
+		return nil, fmt.Errorf("amortization system %v is not supported", amortizationSystem)
+	}
+
+	disbursementDate := l.DisbursementDate(simulationRequest, time.Now())
+	gracePeriod := l.GracePeriod(simulationRequest)
+
+	paymentDay := SchedulePaymentDay(simulationRequest.PaymentDay, disbursementDate, gracePeriod)
+
+	graceInstallments, balance, amortizationStart := CreateGraceInstallments(GraceInput{
+		GracePeriod:         gracePeriod,
+		LoanAmount:          simulationRequest.LoanAmount,
+		MonthlyInterestRate: monthlyInterestRate,
+		DisbursementDate:    disbursementDate,
+		PaymentDay:          paymentDay,
+		HolidayCalendar:     l.HolidayCalendar,
+		Currency:            simulationRequest.Currency,
+		Rounding:            l.RoundingMode,
+	})
+
+	nominalDueDates := NominalDueDates(amortizationStart, paymentDay, simulationRequest.Installments)
+	dueDates := make([]time.Time, len(nominalDueDates))
+	for i, nominalDueDate := range nominalDueDates {
+		dueDates[i] = NextBusinessDay(nominalDueDate, l.HolidayCalendar)
+	}
+
+	var monthlyRates []*big.Rat
+	periodStarts := append([]time.Time{amortizationStart}, nominalDueDates...)
+	if indexCurve != nil {
+		for i := range nominalDueDates {
+			monthlyRates = append(monthlyRates, indexCurve.MonthlyRateAt(periodStarts[i]))
+		}
+	}
+
+	var firstPeriodRate *big.Rat
+	if len(nominalDueDates) > 0 {
+		firstRate := monthlyInterestRate
+		if len(monthlyRates) > 0 {
+			firstRate = monthlyRates[0]
 		}
-		returnInstallments = append(returnInstallments, installment)
+		firstPeriodRate = FirstPeriodRate(amortizationStart, nominalDueDates[0], firstRate)
 	}
-	return returnInstallments
+
+	installments := calculator.CreateInstallments(AmortizationInput{
+		LoanAmount:          balance,
+		MonthlyInterestRate: monthlyInterestRate,
+		FirstPeriodRate:     firstPeriodRate,
+		MonthlyRates:        monthlyRates,
+		TotalInstallments:   simulationRequest.Installments,
+		DueDates:            dueDates,
+		Currency:            simulationRequest.Currency,
+		Rounding:            l.RoundingMode,
+	})
+
+	// the grace lines come first and the numbering follows through the amortization
+	for i := range installments {
+		installments[i].InstallmentNumber += len(graceInstallments)
+	}
+
+	if indexCurve != nil {
+		for i := range graceInstallments {
+			graceInstallments[i].IndexRate, graceInstallments[i].Estimated = indexCurve.IndexAt(disbursementDate)
+		}
+		for i := range installments {
+			installments[i].IndexRate, installments[i].Estimated = indexCurve.IndexAt(periodStarts[i])
+		}
+	}
+	return append(graceInstallments, installments...), nil
 }
 
 func (l *LoanSimulation_usecase) SendLoanSimulationEmailMessage(loanSimulation entities.LoanSimulation) error {
@@ -276,11 +618,31 @@ func (l *LoanSimulation_usecase) ValidateSimulationRequest(SimulationRequest dto
 		errors = append(errors, "Birthdate is required")
 	}
 
-	if SimulationRequest.LoanAmount <= 0 {
-		errors = append(errors, "Loan amount is required above 0")
+	simulationMode := l.SimulationModeName(SimulationRequest.SimulationMode)
+	switch simulationMode {
+	case SimulationModeAmount:
+		if !SimulationRequest.LoanAmount.IsPositive() {
+			errors = append(errors, "Loan amount is required above 0")
+		}
+	case SimulationModeInstallment:
+		if !SimulationRequest.InstallmentAmount.IsPositive() {
+			errors = append(errors, "Installment amount is required above 0 to simulate by installment")
+		}
+	case SimulationModeTerm:
+		if !SimulationRequest.LoanAmount.IsPositive() {
+			errors = append(errors, "Loan amount is required above 0")
+		}
+		if !SimulationRequest.InstallmentAmount.IsPositive() {
+			errors = append(errors, "Installment amount is required above 0 to simulate by term")
+		}
+	default:
+		errors = append(errors, "Simulation mode must be AMOUNT, INSTALLMENT or TERM")
 	}
 
-	if SimulationRequest.Installments <= 0 {
+	// the term is what is solved in TERM mode, and the ladder has its own terms
+	if IsOfferLadder(SimulationRequest) {
+		errors = append(errors, l.ValidateOfferLadder(SimulationRequest)...)
+	} else if simulationMode != SimulationModeTerm && SimulationRequest.Installments <= 0 {
 		errors = append(errors, "Installments is required above 0")
 	}
 
@@ -288,6 +650,38 @@ func (l *LoanSimulation_usecase) ValidateSimulationRequest(SimulationRequest dto
 		errors = append(errors, "Currency is required R$ or U$")
 	}
 
+	if _, ok := l.amortizationCalculators()[l.AmortizationSystemName(SimulationRequest.AmortizationSystem)]; !ok {
+		errors = append(errors, "Amortization system must be PRICE, SAC or SACRE")
+	}
+
+	if iofPayment := l.PaymentModeName(SimulationRequest.IOFPayment); iofPayment != entities.PaymentFinanced && iofPayment != entities.PaymentUpfront {
+		errors = append(errors, "IOF payment must be FINANCED or UPFRONT")
+	}
+
+	if SimulationRequest.MonthlyIncome.IsNegative() || SimulationRequest.ExistingDebtPayments.IsNegative() {
+		errors = append(errors, "Monthly income and existing debt payments must not be negative")
+	} else if SimulationRequest.ExistingDebtPayments.IsPositive() && !SimulationRequest.MonthlyIncome.IsPositive() {
+		errors = append(errors, "Monthly income is required with existing debt payments")
+	}
+
+	errors = append(errors, l.ValidateInsurances(SimulationRequest)...)
+	errors = append(errors, l.ValidateRateIndex(SimulationRequest)...)
+
+	if SimulationRequest.PaymentDay < 0 || SimulationRequest.PaymentDay > 31 {
+		errors = append(errors, "Payment day must be between 1 and 31")
+	}
+
+	gracePeriod := l.GracePeriod(SimulationRequest)
+	if gracePeriod.Unit != GraceUnitMonths && gracePeriod.Unit != GraceUnitDays {
+		errors = append(errors, "Grace period unit must be MONTHS or DAYS")
+	} else if gracePeriod.Length < 0 || (gracePeriod.Unit == GraceUnitMonths && gracePeriod.Length > maxGraceMonths) || (gracePeriod.Unit == GraceUnitDays && gracePeriod.Length > maxGraceDays) {
+		errors = append(errors, fmt.Sprintf("Grace period must be up to %v months or %v days", maxGraceMonths, maxGraceDays))
+	}
+
+	if gracePeriod.Interest != GraceInterestCapitalized && gracePeriod.Interest != GraceInterestPaid {
+		errors = append(errors, "Grace interest must be CAPITALIZED or PAID")
+	}
+
 	if len(errors) > 0 {
 		return errors
 	}
@@ -10,6 +10,7 @@ import (
 	"github.com/Jonattas-21/loan-engine/internal/domain/entities"
 	"github.com/Jonattas-21/loan-engine/internal/infrastructure/logger"
 	"github.com/Jonattas-21/loan-engine/internal/usecases"
+	"github.com/Jonattas-21/loan-engine/package/money"
 	internalMock "github.com/Jonattas-21/loan-engine/tests"
 	"github.com/stretchr/testify/assert"
 	"math/rand"
@@ -17,7 +18,7 @@ import (
 )
 
 var (
-	mockSimulationDatabaseRepo = new(internalMock.MockRepository[entities.LoanSimulation])
+	mockSimulationDatabaseRepo = new(internalMock.MockLoanSimulationRepository)
 	loanSimulationUsecase      = &usecases.LoanSimulation_usecase{
 		CacheRepository:          mockCacheRepo,
 		LoanSimulationRepository: mockSimulationDatabaseRepo,
@@ -37,7 +38,7 @@ func setupSimulation() {
 		Logger:                  logger,
 	}
 
-	mockSimulationDatabaseRepo = new(internalMock.MockRepository[entities.LoanSimulation])
+	mockSimulationDatabaseRepo = new(internalMock.MockLoanSimulationRepository)
 	loanSimulationUsecase = &usecases.LoanSimulation_usecase{
 		CacheRepository:          mockCacheRepo,
 		LoanSimulationRepository: mockSimulationDatabaseRepo,
@@ -51,15 +52,16 @@ func TestCalculatePower_ok(t *testing.T) {
 
 	// Define test cases
 	testCases := []struct {
-		base     *big.Float
+		base     *big.Rat
 		exponent int
-		expected *big.Float
+		expected *big.Rat
 	}{
-		{big.NewFloat(2), 3, big.NewFloat(8)},      // 2^3 = 8
-		{big.NewFloat(5), 0, big.NewFloat(1)},      // 5^0 = 1
-		{big.NewFloat(3), 2, big.NewFloat(9)},      // 3^2 = 9
-		{big.NewFloat(7), 1, big.NewFloat(7)},      // 7^1 = 7
-		{big.NewFloat(10), 4, big.NewFloat(10000)}, // 10^4 = 10000
+		{big.NewRat(2, 1), 3, big.NewRat(8, 1)},      // 2^3 = 8
+		{big.NewRat(5, 1), 0, big.NewRat(1, 1)},      // 5^0 = 1
+		{big.NewRat(3, 1), 2, big.NewRat(9, 1)},      // 3^2 = 9
+		{big.NewRat(7, 1), 1, big.NewRat(7, 1)},      // 7^1 = 7
+		{big.NewRat(10, 1), 4, big.NewRat(10000, 1)}, // 10^4 = 10000
+		{big.NewRat(401, 400), 2, big.NewRat(160801, 160000)},
 	}
 
 	for _, tc := range testCases {
@@ -71,26 +73,6 @@ func TestCalculatePower_ok(t *testing.T) {
 	}
 }
 
-func TestTruncateToTwoDecimals_ok(t *testing.T) {
-	assert := assert.New(t)
-	// Define test cases
-	testCases := []struct {
-		input    float64
-		expected float64
-	}{
-		{123.456789, 123.45},
-		{987.654321, 987.65},
-		{1.999999, 1.99},
-		{0.123456, 0.12},
-		{100.0, 100.0},
-	}
-
-	for _, tc := range testCases {
-		result := loanSimulationUsecase.TruncateToTwoDecimals(tc.input)
-		assert.Equal(tc.expected, result)
-	}
-}
-
 func TestCalculateLoan_ok(t *testing.T) {
 	assert := assert.New(t)
 	setupSimulation()
@@ -98,10 +80,11 @@ func TestCalculateLoan_ok(t *testing.T) {
 	// Define test data
 	simulationRequest := dto.SimulationRequest_dto{
 		Email:        "test@example.com",
-		LoanAmount:   10000,
+		LoanAmount:   money.MustParse("10000"),
 		Installments: 6,
 		BithDate:     time.Date(1990, 1, 1, 0, 0, 0, 0, time.UTC),
 		Currency:     "R$",
+		IOFPayment:   "UPFRONT",
 	}
 	loanConditions := []entities.LoanCondition{
 		{Name: "tier1", InterestRate: 5, MinAge: 18, MaxAge: 25},
@@ -121,14 +104,138 @@ func TestCalculateLoan_ok(t *testing.T) {
 	// Assertions
 	assert.NoError(err)
 	assert.NotNil(result)
-	assert.Equal(10000.00, result.LoanAmount)
-	assert.Equal(10087.68, result.AmountTobePaid)
-	assert.Equal(87.68, result.AmountFeeTobePaid)
+	assert.Equal("10000.00", result.LoanAmount.String())
+	assert.Equal("10087.69", result.AmountTobePaid.String())
+	assert.Equal("87.69", result.AmountFeeTobePaid.String())
 	assert.Equal(3.0, result.FeeAmountPercentage)
+	assert.Equal("PRICE", result.AmortizationSystem)
+	assert.Equal("UPFRONT", result.IOFPayment)
+	assert.True(result.IOFAmount.IsPositive())
+	assert.Equal(result.IOFAmount, result.UpfrontAmount)
+	assert.Equal(result.LoanAmount, result.FinancedAmount)
+
+	// the tax paid upfront makes the effective cost higher than the 0.25% contract rate
+	assert.Greater(result.EffectiveMonthlyRate, 0.25)
+	assert.Greater(result.EffectiveAnnualRate, 3.0419)
 	assert.Equal(6, result.TotalInstallments)
 	assert.Equal("R$", result.Currency)
 	assert.Equal("test@example.com", result.Email)
 	assert.NotEmpty(result.Installments)
+	assert.Len(result.ID, 26)
+}
+
+func TestCalculateLoan_financedIOF(t *testing.T) {
+	assert := assert.New(t)
+	setupSimulation()
+
+	simulationRequest := dto.SimulationRequest_dto{
+		Email:              "test@example.com",
+		LoanAmount:         money.MustParse("10000"),
+		Installments:       12,
+		BithDate:           time.Date(1990, 1, 1, 0, 0, 0, 0, time.UTC),
+		Currency:           "R$",
+		AmortizationSystem: "SAC",
+	}
+	loanConditions := []entities.LoanCondition{
+		{Name: "tier2", InterestRate: 3, MinAge: 26, MaxAge: 40},
+	}
+	jsonConditions, _ := json.Marshal(loanConditions)
+	mockCacheRepo.On("Get", "loan_conditions").Return(string(jsonConditions), nil)
+
+	result, err := loanSimulationUsecase.CalculateLoan(simulationRequest)
+
+	assert.NoError(err)
+	assert.Equal("FINANCED", result.IOFPayment)
+	assert.True(result.IOFAmount.IsPositive())
+	assert.True(result.UpfrontAmount.IsZero())
+	assert.Equal(result.LoanAmount.Add(result.IOFAmount), result.FinancedAmount)
+	assert.Equal(result.FinancedAmount, sumPrincipal(result.Installments))
+}
+
+func TestCreateInstallments_schedule(t *testing.T) {
+	assert := assert.New(t)
+	setupSimulation()
+
+	simulationRequest := dto.SimulationRequest_dto{
+		LoanAmount:   money.MustParse("10000"),
+		Installments: 6,
+		Currency:     "R$",
+	}
+
+	// 3% per year, PRICE is the default system
+	installments, err := loanSimulationUsecase.CreateInstallments(simulationRequest, big.NewRat(1, 400))
+
+	assert.NoError(err)
+	assert.Len(installments, 6)
+	assert.Equal("10000.00", installments[0].OpeningBalance.String())
+	assert.Equal("25.00", installments[0].InstallmentFeeAmount.String())
+	assert.Equal("1656.28", installments[0].PrincipalAmount.String())
+	assert.Equal("8343.72", installments[0].ClosingBalance.String())
+
+	var principal money.Money
+	for i, installment := range installments {
+		assert.Equal(i+1, installment.InstallmentNumber)
+		assert.Equal(installment.PrincipalAmount.Add(installment.InstallmentFeeAmount), installment.InstallmentAmount)
+		if i > 0 {
+			assert.Equal(installments[i-1].ClosingBalance, installment.OpeningBalance)
+		}
+		principal = principal.Add(installment.PrincipalAmount)
+	}
+
+	// the last installment absorbs the rounding
+	assert.Equal(money.MustParse("10000"), principal)
+	assert.True(installments[5].ClosingBalance.IsZero())
+}
+
+func TestCreateInstallments_rounding(t *testing.T) {
+	assert := assert.New(t)
+	setupSimulation()
+
+	// 0.5% per month, the interest falls exactly on half a cent
+	testCases := []struct {
+		loanAmount string
+		mode       money.RoundingMode
+		expected   string
+	}{
+		{"1001.00", money.HalfEven, "5.00"}, // 5.005
+		{"1001.00", money.HalfUp, "5.01"},
+		{"1001.00", money.Truncate, "5.00"},
+		{"1003.00", money.HalfEven, "5.02"}, // 5.015
+		{"1003.00", money.HalfUp, "5.02"},
+		{"1003.00", money.Truncate, "5.01"},
+	}
+
+	for _, tc := range testCases {
+		loanSimulationUsecase.RoundingMode = tc.mode
+		simulationRequest := dto.SimulationRequest_dto{
+			LoanAmount:   money.MustParse(tc.loanAmount),
+			Installments: 1,
+			Currency:     "R$",
+		}
+
+		installments, err := loanSimulationUsecase.CreateInstallments(simulationRequest, big.NewRat(5, 1000))
+
+		assert.NoError(err)
+		assert.Equal(tc.expected, installments[0].InstallmentFeeAmount.String(), "Amount: %v, Mode: %v", tc.loanAmount, tc.mode)
+	}
+}
+
+func TestCreateInstallments_unknownSystem(t *testing.T) {
+	assert := assert.New(t)
+	setupSimulation()
+
+	simulationRequest := dto.SimulationRequest_dto{
+		LoanAmount:         money.MustParse("10000"),
+		Installments:       6,
+		Currency:           "R$",
+		AmortizationSystem: "GERMAN",
+	}
+
+	installments, err := loanSimulationUsecase.CreateInstallments(simulationRequest, big.NewRat(1, 400))
+
+	assert.Error(err)
+	assert.Nil(installments)
+	assert.Contains(loanSimulationUsecase.ValidateSimulationRequest(simulationRequest), "Amortization system must be PRICE, SAC or SACRE")
 }
 
 func TestCalculateLoan_volume(t *testing.T) {
@@ -148,7 +255,7 @@ func TestCalculateLoan_volume(t *testing.T) {
 
 		simulationRequest = append(simulationRequest, dto.SimulationRequest_dto{
 			Email:        "teste@teste.com",
-			LoanAmount:   rendLoanValue,
+			LoanAmount:   money.FromFloat(rendLoanValue, money.HalfEven),
 			Installments: randInstallmentNumber,
 			BithDate:     time.Date(1990, 1, 1, 0, 0, 0, 0, time.UTC),
 			Currency:     "R$",
@@ -0,0 +1,82 @@
+package usecases
+
+import (
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/Jonattas-21/loan-engine/internal/domain/entities"
+	"github.com/Jonattas-21/loan-engine/internal/domain/interfaces"
+)
+
+// The versions of every migration, in the order they apply. A new migration takes the next number here,
+// a recorded version is never reused.
+const (
+	MigrationSeedLoanConditions    = 1
+	MigrationVersionLoanConditions = 2
+	MigrationSeedLoanFees          = 3
+)
+
+// Migration changes the data once, Version orders the migrations and is recorded when Up succeeds
+type Migration struct {
+	Version int
+	Name    string
+	Up      func() error
+}
+
+type Migration_usecase struct {
+	MigrationRepository interfaces.Repository[entities.Migration]
+	Migrations          []Migration
+	Logger              interfaces.Log
+}
+
+// Migrate applies, in version order, the migrations not recorded yet. It stops at the first failure,
+// so the next start retries from it.
+func (m *Migration_usecase) Migrate() error {
+	applied, err := m.MigrationRepository.GetItemsCollection("migrations")
+	if err != nil {
+		m.Logger.Errorln("Error getting applied migrations: ", err.Error())
+		return fmt.Errorf("error getting applied migrations: %w", err)
+	}
+
+	appliedVersions := make(map[int]bool)
+	for _, migration := range applied {
+		appliedVersions[migration.Version] = true
+	}
+
+	migrations := append([]Migration{}, m.Migrations...)
+	sort.Slice(migrations, func(i, j int) bool {
+		return migrations[i].Version < migrations[j].Version
+	})
+	// two migrations of the same version would be recorded as one, the second never applied
+	for i := 1; i < len(migrations); i++ {
+		if migrations[i].Version == migrations[i-1].Version {
+			return fmt.Errorf("migrations %v and %v have the same version %v", migrations[i-1].Name, migrations[i].Name, migrations[i].Version)
+		}
+	}
+
+	for _, migration := range migrations {
+		if appliedVersions[migration.Version] {
+			continue
+		}
+
+		m.Logger.Infoln(fmt.Sprintf("Applying migration %v: %v", migration.Version, migration.Name))
+		err = migration.Up()
+		if err != nil {
+			m.Logger.Errorln(fmt.Sprintf("Error applying migration %v: ", migration.Version), err.Error())
+			return fmt.Errorf("error applying migration %v %v: %w", migration.Version, migration.Name, err)
+		}
+
+		err = m.MigrationRepository.SaveItemCollection(entities.Migration{
+			Version:   migration.Version,
+			Name:      migration.Name,
+			AppliedAt: time.Now(),
+		})
+		if err != nil {
+			m.Logger.Errorln(fmt.Sprintf("Error recording migration %v: ", migration.Version), err.Error())
+			return fmt.Errorf("error recording migration %v: %w", migration.Version, err)
+		}
+	}
+
+	return nil
+}
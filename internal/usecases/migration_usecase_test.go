@@ -0,0 +1,85 @@
+package usecases_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/Jonattas-21/loan-engine/internal/domain/entities"
+	"github.com/Jonattas-21/loan-engine/internal/infrastructure/logger"
+	"github.com/Jonattas-21/loan-engine/internal/usecases"
+	internalMock "github.com/Jonattas-21/loan-engine/tests"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestMigrate_pendingInOrder(t *testing.T) {
+	assert := assert.New(t)
+	mockMigrationRepo := new(internalMock.MockRepository[entities.Migration])
+
+	var applied []int
+	migrationUsecase := usecases.Migration_usecase{
+		MigrationRepository: mockMigrationRepo,
+		Logger:              logger.LogSetup(),
+		Migrations: []usecases.Migration{
+			{Version: 3, Name: "third", Up: func() error { applied = append(applied, 3); return nil }},
+			{Version: 1, Name: "first", Up: func() error { applied = append(applied, 1); return nil }},
+			{Version: 2, Name: "second", Up: func() error { applied = append(applied, 2); return nil }},
+		},
+	}
+
+	mockMigrationRepo.On("GetItemsCollection", "migrations").Return([]entities.Migration{{Version: 1, Name: "first"}}, nil)
+	mockMigrationRepo.On("SaveItemCollection", mock.Anything).Return(nil)
+
+	err := migrationUsecase.Migrate()
+
+	assert.NoError(err)
+	assert.Equal([]int{2, 3}, applied)
+	assert.Equal(2, mockMigrationRepo.Calls[1].Arguments.Get(0).(entities.Migration).Version)
+	assert.Equal(3, mockMigrationRepo.Calls[2].Arguments.Get(0).(entities.Migration).Version)
+}
+
+func TestMigrate_stopsOnError(t *testing.T) {
+	assert := assert.New(t)
+	mockMigrationRepo := new(internalMock.MockRepository[entities.Migration])
+
+	secondApplied := false
+	migrationUsecase := usecases.Migration_usecase{
+		MigrationRepository: mockMigrationRepo,
+		Logger:              logger.LogSetup(),
+		Migrations: []usecases.Migration{
+			{Version: 1, Name: "first", Up: func() error { return errors.New("duplicate key") }},
+			{Version: 2, Name: "second", Up: func() error { secondApplied = true; return nil }},
+		},
+	}
+
+	mockMigrationRepo.On("GetItemsCollection", "migrations").Return([]entities.Migration{}, nil)
+
+	err := migrationUsecase.Migrate()
+
+	// the failed migration is not recorded, so it runs again on the next start
+	assert.EqualError(err, "error applying migration 1 first: duplicate key")
+	assert.False(secondApplied)
+	mockMigrationRepo.AssertNotCalled(t, "SaveItemCollection", mock.Anything)
+}
+
+func TestMigrate_duplicateVersion(t *testing.T) {
+	assert := assert.New(t)
+	mockMigrationRepo := new(internalMock.MockRepository[entities.Migration])
+
+	applied := false
+	migrationUsecase := usecases.Migration_usecase{
+		MigrationRepository: mockMigrationRepo,
+		Logger:              logger.LogSetup(),
+		Migrations: []usecases.Migration{
+			{Version: 1, Name: "first", Up: func() error { applied = true; return nil }},
+			{Version: 1, Name: "other", Up: func() error { applied = true; return nil }},
+		},
+	}
+
+	mockMigrationRepo.On("GetItemsCollection", "migrations").Return([]entities.Migration{}, nil)
+
+	err := migrationUsecase.Migrate()
+
+	assert.EqualError(err, "migrations first and other have the same version 1")
+	assert.False(applied)
+}
@@ -0,0 +1,172 @@
+package usecases
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/Jonattas-21/loan-engine/internal/api/dto"
+	"github.com/Jonattas-21/loan-engine/internal/domain/entities"
+	"github.com/Jonattas-21/loan-engine/package/money"
+	"github.com/Jonattas-21/loan-engine/package/ulid"
+	"golang.org/x/exp/slices"
+)
+
+// How the recommended offer of a ladder is picked, among the affordable offers under the installment cap
+const (
+	RecommendLowestCET         = "LOWEST_CET"
+	RecommendLowestTotal       = "LOWEST_TOTAL"
+	RecommendLowestInstallment = "LOWEST_INSTALLMENT"
+
+	// most offers of a ladder and the step of a range when none is informed
+	maxLadderOffers = 24
+	defaultTermStep = 12
+)
+
+// IsOfferLadder tells if the request asks for offers over several terms
+func IsOfferLadder(simulationRequest dto.SimulationRequest_dto) bool {
+	return len(simulationRequest.Terms) > 0 || simulationRequest.MaxTerm > 0
+}
+
+// LadderTerms are the terms of the offers, the listed ones sorted once each or the range from the min to the max term
+func LadderTerms(simulationRequest dto.SimulationRequest_dto) []int {
+	if len(simulationRequest.Terms) > 0 {
+		terms := slices.Clone(simulationRequest.Terms)
+		slices.Sort(terms)
+		return slices.Compact(terms)
+	}
+
+	step := simulationRequest.TermStep
+	if step <= 0 {
+		step = defaultTermStep
+	}
+	minTerm := simulationRequest.MinTerm
+	if minTerm <= 0 {
+		minTerm = step
+	}
+
+	terms := []int{}
+	for term := minTerm; term <= simulationRequest.MaxTerm; term += step {
+		terms = append(terms, term)
+	}
+	return terms
+}
+
+// ValidateOfferLadder checks the terms of a ladder request
+func (l *LoanSimulation_usecase) ValidateOfferLadder(simulationRequest dto.SimulationRequest_dto) []string {
+	errs := []string{}
+
+	if len(simulationRequest.Terms) > 0 && simulationRequest.MaxTerm > 0 {
+		errs = append(errs, "Terms and a term range are not allowed together")
+	}
+	if l.SimulationModeName(simulationRequest.SimulationMode) == SimulationModeTerm {
+		errs = append(errs, "Offers over several terms are not allowed in TERM mode")
+	}
+	if simulationRequest.MaxTerm > 0 && (simulationRequest.MinTerm < 0 || simulationRequest.MinTerm > simulationRequest.MaxTerm || simulationRequest.TermStep < 0) {
+		errs = append(errs, "Term range must go from a min term up to the max term by a positive step")
+	}
+
+	terms := LadderTerms(simulationRequest)
+	if len(terms) > maxLadderOffers {
+		errs = append(errs, fmt.Sprintf("Offers must be up to %v terms", maxLadderOffers))
+	}
+	for _, term := range terms {
+		if term <= 0 || term > l.maxInstallments() {
+			errs = append(errs, fmt.Sprintf("Terms must be between 1 and %v installments", l.maxInstallments()))
+			break
+		}
+	}
+
+	if len(errs) > 0 {
+		return errs
+	}
+
+	return nil
+}
+
+// CalculateOfferLadder simulates the request at each term of the ladder with the same pricing, the declined terms are listed
+// with the reasons. The recommended offer is flagged, the request is declined only when no term is offered.
+func (l *LoanSimulation_usecase) CalculateOfferLadder(simulationRequest dto.SimulationRequest_dto) (entities.OfferLadder, error) {
+	pricing, err := l.PricingFor(simulationRequest, time.Now())
+	if err != nil {
+		return entities.OfferLadder{}, err
+	}
+
+	ladder := entities.OfferLadder{
+		Email:              NormalizeEmail(simulationRequest.Email),
+		RecommendationRule: l.recommendationRule(),
+		Offers:             []entities.LoanSimulation{},
+		DeclinedOffers:     []entities.DeclinedOffer{},
+	}
+	ladder.ID, err = ulid.New(time.Now())
+	if err != nil {
+		return entities.OfferLadder{}, err
+	}
+
+	var reasons []entities.DeclineReason
+	for _, term := range LadderTerms(simulationRequest) {
+		request := simulationRequest
+		request.Installments = term
+
+		offer, err := l.calculateLoan(request, pricing)
+		var declined *DeclinedError
+		if errors.As(err, &declined) {
+			ladder.DeclinedOffers = append(ladder.DeclinedOffers, entities.DeclinedOffer{Installments: term, Reasons: declined.Reasons})
+			reasons = append(reasons, declined.Reasons...)
+			continue
+		}
+		if err != nil {
+			return entities.OfferLadder{}, err
+		}
+
+		offer.OfferLadderID = ladder.ID
+		ladder.Offers = append(ladder.Offers, offer)
+	}
+
+	if len(ladder.Offers) == 0 {
+		return entities.OfferLadder{}, &DeclinedError{Reasons: reasons}
+	}
+
+	if recommended := l.RecommendOffer(ladder.Offers, simulationRequest.InstallmentAmount); recommended >= 0 {
+		ladder.Offers[recommended].Recommended = true
+		ladder.RecommendedID = ladder.Offers[recommended].ID
+	}
+	return ladder, nil
+}
+
+// RecommendOffer picks the offer of the configured rule among the affordable ones with installments under the cap,
+// on a tie the shorter term. It is -1 when no offer qualifies.
+func (l *LoanSimulation_usecase) RecommendOffer(offers []entities.LoanSimulation, installmentCap money.Money) int {
+	rule := l.recommendationRule()
+	better := func(offer entities.LoanSimulation, best entities.LoanSimulation) bool {
+		switch rule {
+		case RecommendLowestTotal:
+			return offer.AmountTobePaid.Cmp(best.AmountTobePaid) < 0
+		case RecommendLowestInstallment:
+			return MaxInstallment(offer.Installments).Cmp(MaxInstallment(best.Installments)) < 0
+		default:
+			return offer.EffectiveAnnualRate < best.EffectiveAnnualRate
+		}
+	}
+
+	recommended := -1
+	for i, offer := range offers {
+		if offer.AffordabilityFlag || installmentCap.IsPositive() && MaxInstallment(offer.Installments).Cmp(installmentCap) > 0 {
+			continue
+		}
+		if recommended < 0 || better(offer, offers[recommended]) ||
+			!better(offers[recommended], offer) && offer.TotalInstallments < offers[recommended].TotalInstallments {
+			recommended = i
+		}
+	}
+	return recommended
+}
+
+// recommendationRule normalizes the configured rule, the lowest CET when none is configured
+func (l *LoanSimulation_usecase) recommendationRule() string {
+	if strings.TrimSpace(l.RecommendationRule) == "" {
+		return RecommendLowestCET
+	}
+	return strings.ToUpper(strings.TrimSpace(l.RecommendationRule))
+}
@@ -0,0 +1,154 @@
+package usecases_test
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/Jonattas-21/loan-engine/internal/api/dto"
+	"github.com/Jonattas-21/loan-engine/internal/domain/entities"
+	"github.com/Jonattas-21/loan-engine/internal/usecases"
+	"github.com/Jonattas-21/loan-engine/package/money"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func ladderRequest(terms ...int) dto.SimulationRequest_dto {
+	return dto.SimulationRequest_dto{
+		Email:      "test@example.com",
+		LoanAmount: money.MustParse("10000"),
+		BithDate:   time.Date(1990, 1, 1, 0, 0, 0, 0, time.UTC),
+		Currency:   "R$",
+		Terms:      terms,
+	}
+}
+
+func TestLadderTerms(t *testing.T) {
+	assert := assert.New(t)
+
+	assert.Equal([]int{6, 18}, usecases.LadderTerms(dto.SimulationRequest_dto{Terms: []int{6, 18}}))
+	assert.Equal([]int{12, 24}, usecases.LadderTerms(dto.SimulationRequest_dto{Terms: []int{24, 12, 24}}))
+	assert.Equal([]int{12, 24, 36, 48}, usecases.LadderTerms(dto.SimulationRequest_dto{MinTerm: 12, MaxTerm: 48, TermStep: 12}))
+	assert.Equal([]int{12, 24, 36}, usecases.LadderTerms(dto.SimulationRequest_dto{MaxTerm: 40}))
+	assert.Equal([]int{6, 9, 12}, usecases.LadderTerms(dto.SimulationRequest_dto{MinTerm: 6, MaxTerm: 12, TermStep: 3}))
+}
+
+func TestCalculateOfferLadder(t *testing.T) {
+	assert := assert.New(t)
+	setupSimulation()
+	mockLoanConditions()
+
+	// the 12 months installments are above the cap
+	simulationRequest := ladderRequest(12, 24, 36, 48)
+	simulationRequest.InstallmentAmount = money.MustParse("500")
+
+	ladder, err := loanSimulationUsecase.CalculateOfferLadder(simulationRequest)
+
+	assert.NoError(err)
+	assert.Equal(usecases.RecommendLowestCET, ladder.RecommendationRule)
+	assert.Len(ladder.Offers, 4)
+	assert.Empty(ladder.DeclinedOffers)
+
+	var recommended []entities.LoanSimulation
+	for i, offer := range ladder.Offers {
+		assert.Equal(ladder.ID, offer.OfferLadderID)
+		assert.Equal([]int{12, 24, 36, 48}[i], offer.TotalInstallments)
+		assert.NotEmpty(offer.ID)
+		if offer.Recommended {
+			recommended = append(recommended, offer)
+		}
+	}
+	assert.Len(recommended, 1)
+	assert.Equal(ladder.RecommendedID, recommended[0].ID)
+
+	// the lowest CET among the offers under the cap
+	for _, offer := range ladder.Offers[1:] {
+		assert.LessOrEqual(recommended[0].EffectiveAnnualRate, offer.EffectiveAnnualRate)
+	}
+
+	// the terms are offered once each, from the shortest
+	ladder, err = loanSimulationUsecase.CalculateOfferLadder(ladderRequest(24, 12, 24))
+	assert.NoError(err)
+	assert.Len(ladder.Offers, 2)
+	assert.Equal(12, ladder.Offers[0].TotalInstallments)
+	assert.Equal(24, ladder.Offers[1].TotalInstallments)
+}
+
+func TestCalculateOfferLadder_declinedTerms(t *testing.T) {
+	assert := assert.New(t)
+	setupSimulation()
+	mockLoanConditions()
+	loanSimulationUsecase.EligibilityPolicies = []entities.EligibilityPolicy{{MaxAgeAtMaturity: 32}}
+	loanSimulationUsecase.RecommendationRule = usecases.RecommendLowestInstallment
+
+	simulationRequest := ladderRequest(12, 24, 36)
+	simulationRequest.BithDate = time.Now().AddDate(-30, 0, -1)
+
+	ladder, err := loanSimulationUsecase.CalculateOfferLadder(simulationRequest)
+
+	// the client would be 33 at the end of the longest term
+	assert.NoError(err)
+	assert.Len(ladder.Offers, 2)
+	assert.Equal([]entities.DeclinedOffer{{Installments: 36, Reasons: []entities.DeclineReason{{
+		Code:    entities.DeclineAgeAtMaturityAboveLimit,
+		Message: "Age at the last installment must be up to 32",
+		Limit:   "32",
+		Value:   "33",
+	}}}}, ladder.DeclinedOffers)
+
+	// the lowest installment is the longest term offered
+	assert.True(ladder.Offers[1].Recommended)
+
+	// no term offered declines the request
+	simulationRequest.Terms = []int{36, 48}
+	_, err = loanSimulationUsecase.CalculateOfferLadder(simulationRequest)
+	var declined *usecases.DeclinedError
+	assert.True(errors.As(err, &declined))
+	assert.Len(declined.Reasons, 2)
+}
+
+func TestValidateSimulationRequest_offerLadder(t *testing.T) {
+	assert := assert.New(t)
+	setupSimulation()
+
+	// the installments are not required, the ladder has its terms
+	assert.Nil(loanSimulationUsecase.ValidateSimulationRequest(ladderRequest(12, 24)))
+
+	simulationRequest := ladderRequest(12, 400)
+	simulationRequest.MaxTerm = 48
+	simulationRequest.SimulationMode = "TERM"
+	simulationRequest.InstallmentAmount = money.MustParse("800")
+	assert.Equal([]string{
+		"Terms and a term range are not allowed together",
+		"Offers over several terms are not allowed in TERM mode",
+		"Terms must be between 1 and 360 installments",
+	}, loanSimulationUsecase.ValidateSimulationRequest(simulationRequest))
+
+	assert.Contains(loanSimulationUsecase.ValidateSimulationRequest(dto.SimulationRequest_dto{
+		LoanAmount: money.MustParse("10000"), BithDate: time.Now().AddDate(-30, 0, 0), Currency: "R$", MinTerm: 1, MaxTerm: 300, TermStep: 1,
+	}), "Offers must be up to 24 terms")
+
+	// a repeated term is one offer
+	terms := []int{}
+	for term := 24; term >= 1; term-- {
+		terms = append(terms, term, term)
+	}
+	assert.Nil(loanSimulationUsecase.ValidateSimulationRequest(ladderRequest(terms...)))
+}
+
+func TestGetLoanSimulation_offerLadder(t *testing.T) {
+	assert := assert.New(t)
+	setupSimulation()
+	mockLoanConditions()
+	mockCacheRepo.On("Set", mock.Anything, mock.Anything, mock.Anything).Return(nil)
+	mockSimulationDatabaseRepo.On("SaveItemCollection", mock.Anything).Return(nil)
+
+	simulations, ladders, errorSimulations := loanSimulationUsecase.GetLoanSimulation([]dto.SimulationRequest_dto{ladderRequest(12, 24)})
+
+	// the offers are grouped under the request and each one is saved
+	assert.Empty(simulations)
+	assert.Empty(errorSimulations)
+	assert.Len(ladders, 1)
+	assert.Len(ladders[0].Offers, 2)
+	mockSimulationDatabaseRepo.AssertNumberOfCalls(t, "SaveItemCollection", 2)
+}
@@ -0,0 +1,243 @@
+package usecases
+
+import (
+	"fmt"
+	"math/big"
+	"strings"
+	"time"
+
+	"github.com/Jonattas-21/loan-engine/internal/api/dto"
+	"github.com/Jonattas-21/loan-engine/internal/domain/entities"
+	"github.com/Jonattas-21/loan-engine/package/money"
+)
+
+// ValidatePrepaymentRequest checks the prepayment event, the limits of the saved simulation are checked when it is simulated
+func (l *LoanSimulation_usecase) ValidatePrepaymentRequest(prepaymentRequest dto.PrepaymentRequest_dto) []string {
+	errs := []string{}
+
+	kind := strings.ToUpper(strings.TrimSpace(prepaymentRequest.Kind))
+	if kind != entities.PrepaymentTotal && kind != entities.PrepaymentPartial {
+		errs = append(errs, "Kind must be TOTAL or PARTIAL")
+	}
+	if kind == entities.PrepaymentPartial && !prepaymentRequest.Amount.IsPositive() {
+		errs = append(errs, "Amount is required above 0 for a partial prepayment")
+	}
+
+	recalculation := l.RecalculationName(prepaymentRequest.Recalculation)
+	if recalculation != entities.RecalculationReduceInstallment && recalculation != entities.RecalculationReduceTerm {
+		errs = append(errs, "Recalculation must be REDUCE_INSTALLMENT or REDUCE_TERM")
+	}
+
+	if prepaymentRequest.AtInstallment < 0 {
+		errs = append(errs, "Installment of the prepayment must not be negative")
+	}
+	if (prepaymentRequest.AtInstallment > 0) == !prepaymentRequest.Date.IsZero() {
+		errs = append(errs, "Either the installment or the date of the prepayment is required")
+	}
+
+	if len(errs) > 0 {
+		return errs
+	}
+
+	return nil
+}
+
+// SimulatePrepayment pays the saved simulation early. The payoff is the balance after the installments already due
+// plus the interest accrued since the last of them, the interest of the future installments is discounted.
+// A partial prepayment rebuilds the rest of the schedule over the same due dates, the grace lines left included,
+// keeping the term with smaller installments or keeping the installments with a shorter term.
+// The interest counts from the contractual dates, not the business days they were rolled to. The IOF paid at the contract is not refunded.
+func (l *LoanSimulation_usecase) SimulatePrepayment(id string, email string, prepaymentRequest dto.PrepaymentRequest_dto) (entities.PrepaymentSimulation, []string, error) {
+	loanSimulation, err := l.GetLoanSimulationByID(id, email)
+	if err != nil {
+		return entities.PrepaymentSimulation{}, nil, err
+	}
+
+	// the schedule of a post-fixed loan is projected over a curve that is not kept with it
+	if loanSimulation.RateIndex != "" {
+		return entities.PrepaymentSimulation{}, []string{fmt.Sprintf("Prepayment of a post-fixed loan over %v is not supported", loanSimulation.RateIndex)}, nil
+	}
+
+	installments := loanSimulation.Installments
+	if len(installments) == 0 {
+		return entities.PrepaymentSimulation{}, nil, fmt.Errorf("loan simulation %v has no installments", id)
+	}
+	nominalDates := ScheduleNominalDates(loanSimulation)
+	lastNominalDate := nominalDates[len(nominalDates)-1]
+
+	// installments already due are paid with the prepayment, the interest is accrued until the accrual date
+	var installmentsPaid int
+	var prepaymentDate, accrualDate time.Time
+	if prepaymentRequest.AtInstallment > 0 {
+		if prepaymentRequest.AtInstallment >= len(installments) {
+			return entities.PrepaymentSimulation{}, []string{fmt.Sprintf("Installment of the prepayment must be before the last installment %v", len(installments))}, nil
+		}
+		installmentsPaid = prepaymentRequest.AtInstallment
+		prepaymentDate = installments[installmentsPaid-1].DueDate
+		accrualDate = nominalDates[installmentsPaid-1]
+	} else {
+		prepaymentDate = prepaymentRequest.Date
+		if prepaymentDate.Before(loanSimulation.DisbursementDate) || !prepaymentDate.Before(lastNominalDate) {
+			return entities.PrepaymentSimulation{}, []string{fmt.Sprintf("Date of the prepayment must be from the disbursement %v and before the last due date %v",
+				loanSimulation.DisbursementDate.Format("2006-01-02"), lastNominalDate.Format("2006-01-02"))}, nil
+		}
+		for installmentsPaid < len(installments) && !nominalDates[installmentsPaid].After(prepaymentDate) {
+			installmentsPaid++
+		}
+		accrualDate = prepaymentDate
+	}
+
+	monthlyInterestRate := new(big.Rat).Quo(money.ExactRat(loanSimulation.FeeAmountPercentage), big.NewRat(12*100, 1))
+
+	balance := installments[0].OpeningBalance
+	interestStart := loanSimulation.DisbursementDate
+	if installmentsPaid > 0 {
+		balance = installments[installmentsPaid-1].ClosingBalance
+		interestStart = nominalDates[installmentsPaid-1]
+	}
+
+	accruedInterest := money.Zero()
+	if accrualDate.After(interestStart) {
+		accruedInterest = balance.Mul(FirstPeriodRate(interestStart, accrualDate, monthlyInterestRate), l.RoundingMode)
+		interestStart = accrualDate
+	}
+
+	// the insurance premiums and admin fees paid with the installments are not interest, only the credit installments are compared
+	remaining := installments[installmentsPaid:]
+	remainingAmount := money.Zero()
+	for _, installment := range remaining {
+		remainingAmount = remainingAmount.Add(creditInstallment(installment))
+	}
+
+	prepayment := entities.PrepaymentSimulation{
+		LoanSimulationID:   loanSimulation.ID,
+		Kind:               strings.ToUpper(strings.TrimSpace(prepaymentRequest.Kind)),
+		PrepaymentDate:     prepaymentDate,
+		InstallmentsPaid:   installmentsPaid,
+		OutstandingBalance: balance,
+		AccruedInterest:    accruedInterest,
+		PayoffAmount:       balance.Add(accruedInterest),
+		RemainingBalance:   money.Zero(),
+		Installments:       []entities.Installment{},
+		Currency:           loanSimulation.Currency,
+	}
+
+	if prepayment.Kind == entities.PrepaymentTotal {
+		prepayment.PrepaymentAmount = prepayment.PayoffAmount
+		prepayment.DiscountedInterest = remainingAmount.Sub(prepayment.PayoffAmount)
+		return prepayment, nil, nil
+	}
+
+	if prepaymentRequest.Amount.Cmp(prepayment.PayoffAmount) >= 0 {
+		return entities.PrepaymentSimulation{}, []string{fmt.Sprintf("Amount of a partial prepayment must be below the payoff amount of %v %v",
+			loanSimulation.Currency, prepayment.PayoffAmount)}, nil
+	}
+
+	amortizationSystem := l.AmortizationSystemName(loanSimulation.AmortizationSystem)
+	calculator, ok := l.amortizationCalculators()[amortizationSystem]
+	if !ok {
+		return entities.PrepaymentSimulation{}, nil, fmt.Errorf("amortization system %v is not supported", amortizationSystem)
+	}
+
+	remainingDates := nominalDates[installmentsPaid:]
+	dueDates := make([]time.Time, len(remaining))
+	graceLines := 0
+	for i, installment := range remaining {
+		dueDates[i] = installment.DueDate
+		if installment.Kind == entities.InstallmentGrace {
+			graceLines++
+		}
+	}
+
+	loanAmount := prepayment.PayoffAmount.Sub(prepaymentRequest.Amount)
+	graceInput := GraceInput{
+		GracePeriod:         GracePeriod{Length: graceLines, Unit: loanSimulation.GracePeriodUnit, Interest: loanSimulation.GraceInterest},
+		LoanAmount:          loanAmount,
+		MonthlyInterestRate: monthlyInterestRate,
+		DisbursementDate:    interestStart,
+		Currency:            loanSimulation.Currency,
+		Rounding:            l.RoundingMode,
+	}
+
+	// the grace lines left run over the new balance, the term is the one of the amortization.
+	// The insurances paid with the installments go on over the new balance, the admin fees on the same due dates
+	createInstallments := func(term int) []entities.Installment {
+		schedule, balance, amortizationStart := graceInstallments(graceInput, remainingDates[:graceLines])
+		amortization := calculator.CreateInstallments(AmortizationInput{
+			LoanAmount:          balance,
+			MonthlyInterestRate: monthlyInterestRate,
+			FirstPeriodRate:     FirstPeriodRate(amortizationStart, remainingDates[graceLines], monthlyInterestRate),
+			TotalInstallments:   term,
+			DueDates:            dueDates[graceLines : graceLines+term],
+			Currency:            loanSimulation.Currency,
+			Rounding:            l.RoundingMode,
+		})
+		for i := range amortization {
+			amortization[i].InstallmentNumber += graceLines
+		}
+		schedule = append(schedule, amortization...)
+
+		ApplyInsurancePremiums(schedule, append([]entities.InsuranceCharge{}, loanSimulation.Insurances...), l.RoundingMode)
+		for i := range schedule {
+			schedule[i].DueDate = dueDates[i]
+			schedule[i].AdminFeeAmount = remaining[i].AdminFeeAmount
+			schedule[i].InstallmentAmount = schedule[i].InstallmentAmount.Add(remaining[i].AdminFeeAmount)
+		}
+		return schedule
+	}
+
+	prepayment.Recalculation = l.RecalculationName(prepaymentRequest.Recalculation)
+	amortizationLines := len(remaining) - graceLines
+	schedule := createInstallments(amortizationLines)
+	if prepayment.Recalculation == entities.RecalculationReduceTerm {
+		// the shortest term whose installments fit in the ones of the current schedule
+		currentInstallment := MaxInstallment(remaining)
+		for term := 1; term < amortizationLines; term++ {
+			if candidate := createInstallments(term); MaxInstallment(candidate).Cmp(currentInstallment) <= 0 {
+				schedule = candidate
+				break
+			}
+		}
+	}
+
+	// the numbering follows the installments already paid
+	newAmount := money.Zero()
+	for i := range schedule {
+		schedule[i].InstallmentNumber += installmentsPaid
+		newAmount = newAmount.Add(creditInstallment(schedule[i]))
+	}
+
+	prepayment.PrepaymentAmount = prepaymentRequest.Amount
+	prepayment.RemainingBalance = loanAmount
+	prepayment.DiscountedInterest = remainingAmount.Sub(prepaymentRequest.Amount).Sub(newAmount)
+	prepayment.TotalInstallments = len(schedule)
+	prepayment.Installments = schedule
+	return prepayment, nil, nil
+}
+
+// ScheduleNominalDates returns the contractual date of each line of the saved schedule, the grace lines first.
+// The due dates are these rolled to the next business day.
+func ScheduleNominalDates(loanSimulation entities.LoanSimulation) []time.Time {
+	gracePeriod := GracePeriod{Length: loanSimulation.GracePeriod, Unit: loanSimulation.GracePeriodUnit, Interest: loanSimulation.GraceInterest}
+	paymentDay := SchedulePaymentDay(loanSimulation.PaymentDay, loanSimulation.DisbursementDate, gracePeriod)
+
+	nominalDates := GraceNominalDates(gracePeriod, loanSimulation.DisbursementDate, paymentDay)
+	amortizationStart := loanSimulation.DisbursementDate
+	if len(nominalDates) > 0 {
+		amortizationStart = nominalDates[len(nominalDates)-1]
+	}
+	return append(nominalDates, NominalDueDates(amortizationStart, paymentDay, len(loanSimulation.Installments)-len(nominalDates))...)
+}
+
+// RecalculationName normalizes the requested recalculation, the term is kept with smaller installments by default
+func (l *LoanSimulation_usecase) RecalculationName(recalculation string) string {
+	if strings.TrimSpace(recalculation) == "" {
+		return entities.RecalculationReduceInstallment
+	}
+	return strings.ToUpper(strings.TrimSpace(recalculation))
+}
+
+// creditInstallment is the principal and the interest of the installment, without the premiums and fees paid with it
+func creditInstallment(installment entities.Installment) money.Money {
+	return installment.InstallmentAmount.Sub(installment.InsuranceAmount).Sub(installment.AdminFeeAmount)
+}
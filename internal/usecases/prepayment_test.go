@@ -0,0 +1,226 @@
+package usecases_test
+
+import (
+	"encoding/json"
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/Jonattas-21/loan-engine/internal/api/dto"
+	"github.com/Jonattas-21/loan-engine/internal/domain/entities"
+	"github.com/Jonattas-21/loan-engine/internal/usecases"
+	"github.com/Jonattas-21/loan-engine/package/money"
+	"github.com/stretchr/testify/assert"
+)
+
+// savedSimulation simulates 10000 in 12 installments and keeps it in the cache by id
+func savedSimulation(t *testing.T) entities.LoanSimulation {
+	simulationRequest := ladderRequest()
+	simulationRequest.Installments = 12
+	return savedSimulationOf(t, simulationRequest)
+}
+
+// savedSimulationOf simulates the request and keeps it in the cache by id
+func savedSimulationOf(t *testing.T, simulationRequest dto.SimulationRequest_dto) entities.LoanSimulation {
+	setupSimulation()
+	mockLoanConditions()
+
+	loanSimulation, err := loanSimulationUsecase.CalculateLoan(simulationRequest)
+	assert.NoError(t, err)
+
+	jsonSimulation, _ := json.Marshal(loanSimulation)
+	mockCacheRepo.On("Get", loanSimulationUsecase.SimulationIDCacheKey(loanSimulation.ID)).Return(string(jsonSimulation), nil)
+	return loanSimulation
+}
+
+func TestValidatePrepaymentRequest(t *testing.T) {
+	assert := assert.New(t)
+	setupSimulation()
+
+	assert.Nil(loanSimulationUsecase.ValidatePrepaymentRequest(dto.PrepaymentRequest_dto{Kind: "total", AtInstallment: 3}))
+	assert.Equal([]string{
+		"Kind must be TOTAL or PARTIAL",
+		"Recalculation must be REDUCE_INSTALLMENT or REDUCE_TERM",
+		"Either the installment or the date of the prepayment is required",
+	}, loanSimulationUsecase.ValidatePrepaymentRequest(dto.PrepaymentRequest_dto{Kind: "SOME", Recalculation: "SKIP"}))
+	assert.Equal([]string{"Amount is required above 0 for a partial prepayment"},
+		loanSimulationUsecase.ValidatePrepaymentRequest(dto.PrepaymentRequest_dto{Kind: "PARTIAL", AtInstallment: 3}))
+}
+
+func TestSimulatePrepayment_total(t *testing.T) {
+	assert := assert.New(t)
+	loanSimulation := savedSimulation(t)
+
+	prepayment, validations, err := loanSimulationUsecase.SimulatePrepayment(loanSimulation.ID, "test@example.com",
+		dto.PrepaymentRequest_dto{Kind: entities.PrepaymentTotal, AtInstallment: 3})
+
+	assert.NoError(err)
+	assert.Nil(validations)
+	assert.Equal(3, prepayment.InstallmentsPaid)
+	assert.Equal(loanSimulation.Installments[2].DueDate, prepayment.PrepaymentDate)
+	assert.Equal(loanSimulation.Installments[2].ClosingBalance, prepayment.PayoffAmount)
+	assert.True(prepayment.AccruedInterest.IsZero())
+	assert.Equal(prepayment.PayoffAmount, prepayment.PrepaymentAmount)
+	assert.Empty(prepayment.Installments)
+
+	// the interest of the 9 installments left is not paid
+	remaining := money.Zero()
+	for _, installment := range loanSimulation.Installments[3:] {
+		remaining = remaining.Add(installment.InstallmentFeeAmount)
+	}
+	assert.Equal(remaining, prepayment.DiscountedInterest)
+}
+
+func TestSimulatePrepayment_totalAtDate(t *testing.T) {
+	assert := assert.New(t)
+	loanSimulation := savedSimulation(t)
+
+	date := loanSimulation.Installments[1].DueDate.AddDate(0, 0, 10)
+	prepayment, validations, err := loanSimulationUsecase.SimulatePrepayment(loanSimulation.ID, "test@example.com",
+		dto.PrepaymentRequest_dto{Kind: entities.PrepaymentTotal, Date: date})
+
+	assert.NoError(err)
+	assert.Nil(validations)
+	assert.Equal(2, prepayment.InstallmentsPaid)
+	assert.Equal(loanSimulation.Installments[1].ClosingBalance, prepayment.OutstandingBalance)
+
+	// ten days of interest, below the interest of the next installment
+	assert.True(prepayment.AccruedInterest.IsPositive())
+	assert.Equal(-1, prepayment.AccruedInterest.Cmp(loanSimulation.Installments[2].InstallmentFeeAmount))
+	assert.Equal(prepayment.OutstandingBalance.Add(prepayment.AccruedInterest), prepayment.PayoffAmount)
+}
+
+func TestSimulatePrepayment_reduceInstallment(t *testing.T) {
+	assert := assert.New(t)
+	loanSimulation := savedSimulation(t)
+
+	prepayment, validations, err := loanSimulationUsecase.SimulatePrepayment(loanSimulation.ID, "test@example.com",
+		dto.PrepaymentRequest_dto{Kind: entities.PrepaymentPartial, Amount: money.MustParse("2000"), AtInstallment: 3})
+
+	assert.NoError(err)
+	assert.Nil(validations)
+	assert.Equal(entities.RecalculationReduceInstallment, prepayment.Recalculation)
+	assert.Equal(loanSimulation.Installments[2].ClosingBalance.Sub(money.MustParse("2000")), prepayment.RemainingBalance)
+	assert.Equal(9, prepayment.TotalInstallments)
+	assert.Len(prepayment.Installments, 9)
+
+	principal := money.Zero()
+	for i, installment := range prepayment.Installments {
+		assert.Equal(i+4, installment.InstallmentNumber)
+		assert.Equal(loanSimulation.Installments[i+3].DueDate, installment.DueDate)
+		assert.Equal(-1, installment.InstallmentAmount.Cmp(loanSimulation.Installments[i+3].InstallmentAmount))
+		principal = principal.Add(installment.PrincipalAmount)
+	}
+	assert.Equal(prepayment.RemainingBalance, principal)
+	assert.True(prepayment.DiscountedInterest.IsPositive())
+}
+
+func TestSimulatePrepayment_reduceTerm(t *testing.T) {
+	assert := assert.New(t)
+	loanSimulation := savedSimulation(t)
+
+	prepayment, validations, err := loanSimulationUsecase.SimulatePrepayment(loanSimulation.ID, "test@example.com",
+		dto.PrepaymentRequest_dto{Kind: entities.PrepaymentPartial, Amount: money.MustParse("2000"), AtInstallment: 3, Recalculation: "reduce_term"})
+
+	assert.NoError(err)
+	assert.Nil(validations)
+	assert.Equal(entities.RecalculationReduceTerm, prepayment.Recalculation)
+	assert.Less(prepayment.TotalInstallments, 9)
+	assert.Equal(4, prepayment.Installments[0].InstallmentNumber)
+
+	current := loanSimulation.Installments[3].InstallmentAmount
+	for _, installment := range prepayment.Installments {
+		assert.LessOrEqual(installment.InstallmentAmount.Cmp(current), 0)
+	}
+}
+
+func TestSimulatePrepayment_invalidEvent(t *testing.T) {
+	assert := assert.New(t)
+	loanSimulation := savedSimulation(t)
+
+	_, validations, err := loanSimulationUsecase.SimulatePrepayment(loanSimulation.ID, "test@example.com",
+		dto.PrepaymentRequest_dto{Kind: entities.PrepaymentTotal, AtInstallment: 12})
+	assert.NoError(err)
+	assert.Equal([]string{"Installment of the prepayment must be before the last installment 12"}, validations)
+
+	_, validations, err = loanSimulationUsecase.SimulatePrepayment(loanSimulation.ID, "test@example.com",
+		dto.PrepaymentRequest_dto{Kind: entities.PrepaymentPartial, Amount: money.MustParse("20000"), AtInstallment: 3})
+	assert.NoError(err)
+	assert.Len(validations, 1)
+	assert.Contains(validations[0], "Amount of a partial prepayment must be below the payoff amount")
+}
+
+func TestSimulatePrepayment_nominalDates(t *testing.T) {
+	assert := assert.New(t)
+
+	// the 4th installment is due on saturday may 10, it is paid on monday may 12
+	simulationRequest := ladderRequest()
+	simulationRequest.Installments = 12
+	simulationRequest.DisbursementDate = time.Date(2025, 1, 10, 0, 0, 0, 0, time.UTC)
+	loanSimulation := savedSimulationOf(t, simulationRequest)
+	assert.Equal(time.Date(2025, 5, 12, 0, 0, 0, 0, time.UTC), loanSimulation.Installments[3].DueDate)
+
+	nominalDates := usecases.ScheduleNominalDates(loanSimulation)
+	assert.Len(nominalDates, 12)
+	assert.Equal(time.Date(2025, 5, 10, 0, 0, 0, 0, time.UTC), nominalDates[3])
+
+	// paid on its due date, nothing is accrued
+	prepayment, validations, err := loanSimulationUsecase.SimulatePrepayment(loanSimulation.ID, "test@example.com",
+		dto.PrepaymentRequest_dto{Kind: entities.PrepaymentPartial, Amount: money.MustParse("2000"), AtInstallment: 4})
+	assert.NoError(err)
+	assert.Nil(validations)
+	assert.True(prepayment.AccruedInterest.IsZero())
+
+	// the next installment is a full month from may 10, not from the day it was rolled to
+	monthlyInterestRate := new(big.Rat).Quo(money.ExactRat(loanSimulation.FeeAmountPercentage), big.NewRat(12*100, 1))
+	assert.Equal(prepayment.RemainingBalance.Mul(monthlyInterestRate, loanSimulationUsecase.RoundingMode), prepayment.Installments[0].InstallmentFeeAmount)
+
+	// at a date the interest runs from the contractual date of the last installment
+	prepayment, validations, err = loanSimulationUsecase.SimulatePrepayment(loanSimulation.ID, "test@example.com",
+		dto.PrepaymentRequest_dto{Kind: entities.PrepaymentTotal, Date: time.Date(2025, 5, 20, 0, 0, 0, 0, time.UTC)})
+	assert.NoError(err)
+	assert.Nil(validations)
+	assert.Equal(4, prepayment.InstallmentsPaid)
+	tenDays := usecases.FirstPeriodRate(nominalDates[3], time.Date(2025, 5, 20, 0, 0, 0, 0, time.UTC), monthlyInterestRate)
+	assert.Equal(prepayment.OutstandingBalance.Mul(tenDays, loanSimulationUsecase.RoundingMode), prepayment.AccruedInterest)
+}
+
+func TestSimulatePrepayment_grace(t *testing.T) {
+	assert := assert.New(t)
+
+	simulationRequest := ladderRequest()
+	simulationRequest.Installments = 12
+	simulationRequest.DisbursementDate = time.Date(2025, 1, 10, 0, 0, 0, 0, time.UTC)
+	simulationRequest.GracePeriod = 3
+	loanSimulation := savedSimulationOf(t, simulationRequest)
+	assert.Len(loanSimulation.Installments, 15)
+
+	// prepaid after the first grace month, the two grace lines left are kept
+	prepayment, validations, err := loanSimulationUsecase.SimulatePrepayment(loanSimulation.ID, "test@example.com",
+		dto.PrepaymentRequest_dto{Kind: entities.PrepaymentPartial, Amount: money.MustParse("2000"), AtInstallment: 1})
+
+	assert.NoError(err)
+	assert.Nil(validations)
+	assert.Len(prepayment.Installments, 14)
+	for i, installment := range prepayment.Installments {
+		assert.Equal(i+2, installment.InstallmentNumber)
+		assert.Equal(loanSimulation.Installments[i+1].DueDate, installment.DueDate)
+		assert.Equal(loanSimulation.Installments[i+1].Kind, installment.Kind)
+	}
+
+	// the grace interest is still capitalized, over the new balance
+	grace := prepayment.Installments[:2]
+	assert.Equal(prepayment.RemainingBalance, grace[0].OpeningBalance)
+	assert.True(grace[0].CapitalizedInterest.IsPositive())
+	assert.True(grace[0].PrincipalAmount.IsZero())
+	assert.Equal(grace[1].ClosingBalance, prepayment.Installments[2].OpeningBalance)
+	assert.True(prepayment.Installments[13].ClosingBalance.IsZero())
+
+	// reducing the term keeps the grace and shortens the amortization
+	prepayment, _, err = loanSimulationUsecase.SimulatePrepayment(loanSimulation.ID, "test@example.com",
+		dto.PrepaymentRequest_dto{Kind: entities.PrepaymentPartial, Amount: money.MustParse("2000"), AtInstallment: 1, Recalculation: entities.RecalculationReduceTerm})
+	assert.NoError(err)
+	assert.Less(prepayment.TotalInstallments, 14)
+	assert.Equal(entities.InstallmentGrace, prepayment.Installments[1].Kind)
+	assert.Equal(entities.InstallmentAmortization, prepayment.Installments[2].Kind)
+}
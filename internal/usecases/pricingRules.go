@@ -0,0 +1,187 @@
+package usecases
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/Jonattas-21/loan-engine/internal/api/dto"
+	"github.com/Jonattas-21/loan-engine/internal/domain/entities"
+	"github.com/Jonattas-21/loan-engine/package/money"
+)
+
+// PricingCriteria describe the loan the pricing rules are matched against
+type PricingCriteria struct {
+	Age          int
+	LoanAmount   money.Money
+	Installments int
+	Currency     string
+	Segment      string
+}
+
+// Pricing prices the simulations of a client, the rule is matched with the amount and term of each simulation
+// since the reverse simulations change them
+type Pricing struct {
+	Age         int
+	AgeTiers    []entities.LoanCondition // versions in force at the simulation
+	CreditScore int
+	ScoreBand   entities.ScoreBand    // its spread is added to the rate of the matching rule
+	LoanFees    []entities.LoanFee    // catalog the fees of each simulation are matched in
+	IndexValues []entities.IndexValue // curve of the index of a post-fixed loan
+}
+
+// AgeTierRule is the pricing rule of an age tier, matching on the age only
+func AgeTierRule(condition entities.LoanCondition) entities.PricingRule {
+	return entities.PricingRule{
+		ID:           condition.ID,
+		Name:         condition.Name,
+		Kind:         entities.PricingRuleAgeTier,
+		InterestRate: condition.InterestRate,
+		MinAge:       condition.MinAge,
+		MaxAge:       condition.MaxAge,
+	}
+}
+
+// MatchesPricingRule tells if the loan meets every criterion of the rule, the empty ones match any loan
+func MatchesPricingRule(rule entities.PricingRule, criteria PricingCriteria) bool {
+	if rule.MinAge > 0 && criteria.Age < rule.MinAge || rule.MaxAge > 0 && criteria.Age > rule.MaxAge {
+		return false
+	}
+	if !rule.MinLoanAmount.IsZero() && criteria.LoanAmount.Cmp(rule.MinLoanAmount) < 0 ||
+		!rule.MaxLoanAmount.IsZero() && criteria.LoanAmount.Cmp(rule.MaxLoanAmount) > 0 {
+		return false
+	}
+	if rule.MinInstallments > 0 && criteria.Installments < rule.MinInstallments ||
+		rule.MaxInstallments > 0 && criteria.Installments > rule.MaxInstallments {
+		return false
+	}
+	return matchesAny(rule.Currencies, criteria.Currency) && matchesAny(rule.Segments, criteria.Segment)
+}
+
+func matchesAny(values []string, value string) bool {
+	if len(values) == 0 {
+		return true
+	}
+	for _, candidate := range values {
+		if strings.EqualFold(strings.TrimSpace(candidate), strings.TrimSpace(value)) {
+			return true
+		}
+	}
+	return false
+}
+
+// MatchPricingRule picks the matching rule of the highest priority, on the same priority the first one in the list
+func MatchPricingRule(rules []entities.PricingRule, criteria PricingCriteria) (entities.PricingRule, bool) {
+	ordered := append([]entities.PricingRule{}, rules...)
+	sort.SliceStable(ordered, func(i, j int) bool { return ordered[i].Priority > ordered[j].Priority })
+
+	for _, rule := range ordered {
+		if MatchesPricingRule(rule, criteria) {
+			return rule, true
+		}
+	}
+	return entities.PricingRule{}, false
+}
+
+// ValidatePricingRules checks the configured policy rules
+func ValidatePricingRules(rules []entities.PricingRule) []string {
+	errs := []string{}
+	names := map[string]bool{}
+	for _, rule := range rules {
+		if strings.TrimSpace(rule.Name) == "" {
+			errs = append(errs, "Pricing rule name is required")
+		} else if names[rule.Name] {
+			errs = append(errs, fmt.Sprintf("Pricing rule %v is informed more than once", rule.Name))
+		}
+		names[rule.Name] = true
+
+		if rule.InterestRate <= 0 || rule.InterestRate >= 80 {
+			errs = append(errs, fmt.Sprintf("Pricing rule %v interest rate is required above 0 and below 80 per year", rule.Name))
+		}
+		if rule.MaxAge > 0 && rule.MinAge > rule.MaxAge ||
+			!rule.MaxLoanAmount.IsZero() && rule.MinLoanAmount.Cmp(rule.MaxLoanAmount) > 0 ||
+			rule.MaxInstallments > 0 && rule.MinInstallments > rule.MaxInstallments {
+			errs = append(errs, fmt.Sprintf("Pricing rule %v has a minimum above the maximum", rule.Name))
+		}
+	}
+
+	if len(errs) > 0 {
+		return errs
+	}
+
+	return nil
+}
+
+// PricingFor reads the age and the credit score of the client, the age tiers in force at the date, the fee catalog
+// and the curve of the index of a post-fixed request
+func (l *LoanSimulation_usecase) PricingFor(simulationRequest dto.SimulationRequest_dto, date time.Time) (Pricing, error) {
+	//get fee conditions
+	conditions, err := l.LoanCondition.GetLoanConditionsAt(date)
+	if err != nil {
+		l.Logger.Errorln(fmt.Printf("Error getting loan conditions, %v", err.Error()))
+		return Pricing{}, fmt.Errorf("error getting loan conditions, %v", err.Error())
+	}
+
+	creditScore, scoreBand, err := l.CreditScoreFor(simulationRequest.Email)
+	if err != nil {
+		return Pricing{}, err
+	}
+
+	var loanFees []entities.LoanFee
+	if l.LoanFee != nil {
+		loanFees, err = l.LoanFee.GetLoanFees()
+		if err != nil {
+			return Pricing{}, fmt.Errorf("error getting loan fees, %v", err.Error())
+		}
+	}
+
+	indexValues, err := l.IndexValuesFor(simulationRequest)
+	if err != nil {
+		return Pricing{}, err
+	}
+
+	return Pricing{
+		Age:         AgeAt(simulationRequest.BithDate, date),
+		AgeTiers:    conditions,
+		CreditScore: creditScore,
+		ScoreBand:   scoreBand,
+		LoanFees:    loanFees,
+		IndexValues: indexValues,
+	}, nil
+}
+
+// PriceLoan matches the policy rules and the age tiers against the simulated loan,
+// the age tier of the client is returned even when a policy rule sets the rate
+func (l *LoanSimulation_usecase) PriceLoan(simulationRequest dto.SimulationRequest_dto, pricing Pricing) (entities.PricingRule, entities.LoanCondition, error) {
+	rules := append([]entities.PricingRule{}, l.PricingRules...)
+
+	var ageTier entities.LoanCondition
+	for _, condition := range pricing.AgeTiers {
+		rules = append(rules, AgeTierRule(condition))
+		if pricing.Age >= condition.MinAge && pricing.Age <= condition.MaxAge {
+			ageTier = condition
+		}
+	}
+
+	rule, found := MatchPricingRule(rules, PricingCriteria{
+		Age:          pricing.Age,
+		LoanAmount:   simulationRequest.LoanAmount,
+		Installments: simulationRequest.Installments,
+		Currency:     simulationRequest.Currency,
+		Segment:      simulationRequest.Segment,
+	})
+
+	//check if interest rate was found
+	if !found || rule.InterestRate == 0 {
+		return entities.PricingRule{}, entities.LoanCondition{}, &DeclinedError{Reasons: []entities.DeclineReason{{
+			Code:    entities.DeclineNoPricingRule,
+			Message: fmt.Sprintf("interest rate not found for age %v", pricing.Age),
+			Value:   fmt.Sprint(pricing.Age),
+		}}}
+	}
+	if rule.InterestRate+pricing.ScoreBand.Spread <= 0 {
+		return entities.PricingRule{}, entities.LoanCondition{}, fmt.Errorf("interest rate of %v with the spread of the score band %v is not positive", rule.Name, pricing.ScoreBand.Name)
+	}
+	return rule, ageTier, nil
+}
@@ -0,0 +1,95 @@
+package usecases_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/Jonattas-21/loan-engine/internal/api/dto"
+	"github.com/Jonattas-21/loan-engine/internal/domain/entities"
+	"github.com/Jonattas-21/loan-engine/internal/usecases"
+	"github.com/Jonattas-21/loan-engine/package/money"
+	"github.com/stretchr/testify/assert"
+)
+
+var policyRules = []entities.PricingRule{
+	{ID: "private_long_term", Name: "private_long_term", Kind: entities.PricingRulePolicy, Priority: 20, InterestRate: 2.5, MinInstallments: 49, Segments: []string{"PRIVATE"}},
+	{ID: "usd_loans", Name: "usd_loans", Kind: entities.PricingRulePolicy, Priority: 10, InterestRate: 6, Currencies: []string{"U$"}},
+	{ID: "large_amounts", Name: "large_amounts", Kind: entities.PricingRulePolicy, Priority: 10, InterestRate: 2.8, MinLoanAmount: money.MustParse("100000")},
+}
+
+func TestMatchPricingRule(t *testing.T) {
+	assert := assert.New(t)
+
+	rules := append([]entities.PricingRule{}, policyRules...)
+	rules = append(rules, usecases.AgeTierRule(entities.LoanCondition{ID: "tier2_v1", Name: "tier2", InterestRate: 3, MinAge: 26, MaxAge: 40}))
+
+	testCases := []struct {
+		name     string
+		criteria usecases.PricingCriteria
+		expected string
+	}{
+		{"age tier", usecases.PricingCriteria{Age: 30, LoanAmount: money.MustParse("10000"), Installments: 12, Currency: "R$"}, "tier2"},
+		{"currency", usecases.PricingCriteria{Age: 30, LoanAmount: money.MustParse("10000"), Installments: 12, Currency: "U$"}, "usd_loans"},
+		{"amount band", usecases.PricingCriteria{Age: 30, LoanAmount: money.MustParse("100000"), Installments: 12, Currency: "R$"}, "large_amounts"},
+		// on the same priority the first rule in the list wins
+		{"same priority", usecases.PricingCriteria{Age: 30, LoanAmount: money.MustParse("150000"), Installments: 12, Currency: "U$"}, "usd_loans"},
+		{"segment and term", usecases.PricingCriteria{Age: 30, LoanAmount: money.MustParse("150000"), Installments: 60, Currency: "U$", Segment: "private"}, "private_long_term"},
+		{"segment short term", usecases.PricingCriteria{Age: 30, LoanAmount: money.MustParse("10000"), Installments: 48, Currency: "R$", Segment: "PRIVATE"}, "tier2"},
+	}
+
+	for _, testCase := range testCases {
+		rule, found := usecases.MatchPricingRule(rules, testCase.criteria)
+		assert.True(found, testCase.name)
+		assert.Equal(testCase.expected, rule.Name, testCase.name)
+	}
+
+	// no age tier covers the age and no policy rule matches
+	_, found := usecases.MatchPricingRule(rules, usecases.PricingCriteria{Age: 50, LoanAmount: money.MustParse("10000"), Installments: 12, Currency: "R$"})
+	assert.False(found)
+}
+
+func TestValidatePricingRules(t *testing.T) {
+	assert := assert.New(t)
+
+	assert.Nil(usecases.ValidatePricingRules(policyRules))
+	assert.Equal([]string{
+		"Pricing rule name is required",
+		"Pricing rule usd_loans is informed more than once",
+		"Pricing rule usd_loans interest rate is required above 0 and below 80 per year",
+		"Pricing rule usd_loans has a minimum above the maximum",
+	}, usecases.ValidatePricingRules([]entities.PricingRule{
+		{InterestRate: 2},
+		{Name: "usd_loans", InterestRate: 2},
+		{Name: "usd_loans", MinInstallments: 24, MaxInstallments: 12},
+	}))
+}
+
+func TestCalculateLoan_pricingRule(t *testing.T) {
+	assert := assert.New(t)
+	setupSimulation()
+	mockLoanConditions()
+	loanSimulationUsecase.PricingRules = policyRules
+
+	simulationRequest := dto.SimulationRequest_dto{
+		LoanAmount:   money.MustParse("10000"),
+		Installments: 12,
+		BithDate:     time.Date(1990, 1, 1, 0, 0, 0, 0, time.UTC),
+		Currency:     "U$",
+	}
+
+	result, err := loanSimulationUsecase.CalculateLoan(simulationRequest)
+
+	// the policy rule sets the rate, the age tier of the client is still reported
+	assert.NoError(err)
+	assert.Equal("usd_loans", result.PricingRuleName)
+	assert.Equal(entities.PricingRulePolicy, result.PricingRuleKind)
+	assert.Equal(6.0, result.FeeAmountPercentage)
+	assert.Equal("tier2", result.LoanConditionName)
+
+	simulationRequest.Currency = "R$"
+	result, err = loanSimulationUsecase.CalculateLoan(simulationRequest)
+	assert.NoError(err)
+	assert.Equal("01JH8Z5Q4E2V3W6X7Y8Z9A0B1D", result.PricingRuleID)
+	assert.Equal(entities.PricingRuleAgeTier, result.PricingRuleKind)
+	assert.Equal(3.0, result.FeeAmountPercentage)
+}
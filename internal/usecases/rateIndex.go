@@ -0,0 +1,141 @@
+package usecases
+
+import (
+	"errors"
+	"fmt"
+	"math/big"
+	"strings"
+	"time"
+
+	"github.com/Jonattas-21/loan-engine/internal/api/dto"
+	"github.com/Jonattas-21/loan-engine/internal/domain/entities"
+	"github.com/Jonattas-21/loan-engine/internal/domain/interfaces"
+	"github.com/Jonattas-21/loan-engine/package/money"
+)
+
+// IndexCurve projects the rate of a post-fixed loan: the annual index of each month plus the spread
+type IndexCurve struct {
+	Index       string
+	Values      []entities.IndexValue // sorted by month, each value holds until the next one
+	AssumedRate float64               // flat index for the whole term instead of the values, when above 0
+	Spread      float64
+}
+
+// IsPostFixed tells if the request is quoted over an index
+func IsPostFixed(simulationRequest dto.SimulationRequest_dto) bool {
+	return strings.TrimSpace(simulationRequest.RateIndex) != ""
+}
+
+// RateIndexName normalizes the requested index
+func RateIndexName(rateIndex string) string {
+	return strings.ToUpper(strings.TrimSpace(rateIndex))
+}
+
+// ValidateRateIndex checks the index of a post-fixed request, the registry must have it unless an index is assumed
+func (l *LoanSimulation_usecase) ValidateRateIndex(simulationRequest dto.SimulationRequest_dto) []string {
+	var errs []string
+
+	if !IsPostFixed(simulationRequest) {
+		if simulationRequest.AssumedIndexRate != 0 {
+			errs = append(errs, "Assumed index rate is allowed only with a rate index")
+		}
+		return errs
+	}
+
+	switch RateIndexName(simulationRequest.RateIndex) {
+	case entities.RateIndexCDI, entities.RateIndexIPCA, entities.RateIndexSELIC:
+		if l.RateIndexProvider == nil && simulationRequest.AssumedIndexRate == 0 {
+			errs = append(errs, fmt.Sprintf("Rate index %v is not available, an assumed index rate is required", RateIndexName(simulationRequest.RateIndex)))
+		}
+	default:
+		errs = append(errs, "Rate index must be CDI, IPCA or SELIC")
+	}
+	if simulationRequest.AssumedIndexRate < 0 {
+		errs = append(errs, "Assumed index rate must not be negative")
+	}
+
+	return errs
+}
+
+// IndexValuesFor reads the curve of the index of a post-fixed request, none for a fixed rate or an assumed index
+func (l *LoanSimulation_usecase) IndexValuesFor(simulationRequest dto.SimulationRequest_dto) ([]entities.IndexValue, error) {
+	if !IsPostFixed(simulationRequest) || simulationRequest.AssumedIndexRate > 0 || l.RateIndexProvider == nil {
+		return nil, nil
+	}
+
+	index := RateIndexName(simulationRequest.RateIndex)
+	values, err := l.RateIndexProvider.GetIndexValues(index)
+	if errors.Is(err, interfaces.ErrRateIndexNotFound) {
+		return nil, fmt.Errorf("rate index %v has no values", index)
+	}
+	if err != nil {
+		l.Logger.Errorln(fmt.Sprintf("[index:%v] Error getting rate index", index), err.Error())
+		return nil, fmt.Errorf("error getting rate index %v, %v", index, err.Error())
+	}
+	if IsStaleCurve(values, time.Now()) {
+		l.Logger.Warnln(fmt.Sprintf("[index:%v] Rate index has projected values for months already over, the registry must be refreshed", index))
+	}
+	return values, nil
+}
+
+// IsStaleCurve tells if a month already over is still projected, the values published since were not loaded.
+// The amounts over those months stay estimated until the registry is refreshed.
+func IsStaleCurve(values []entities.IndexValue, now time.Time) bool {
+	currentMonth := time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, time.UTC)
+	for _, value := range values {
+		if value.Projected && value.Month.Before(currentMonth) {
+			return true
+		}
+	}
+	return false
+}
+
+// IndexCurveFor is the curve of a post-fixed request with the spread of its pricing, nil for a fixed rate
+func IndexCurveFor(simulationRequest dto.SimulationRequest_dto, pricing Pricing, spread float64) *IndexCurve {
+	if !IsPostFixed(simulationRequest) {
+		return nil
+	}
+	return &IndexCurve{
+		Index:       RateIndexName(simulationRequest.RateIndex),
+		Values:      pricing.IndexValues,
+		AssumedRate: simulationRequest.AssumedIndexRate,
+		Spread:      spread,
+	}
+}
+
+// IndexAt is the annual index of the month of the date, the last value before it holds when there is none for the month.
+// The index is estimated when it is projected, assumed, carried past the last published value or carried back
+// from the first value to a date before the curve.
+func (c *IndexCurve) IndexAt(date time.Time) (float64, bool) {
+	if c.AssumedRate > 0 || len(c.Values) == 0 {
+		return c.AssumedRate, true
+	}
+
+	month := time.Date(date.Year(), date.Month(), 1, 0, 0, 0, 0, time.UTC)
+	if month.Before(c.Values[0].Month) {
+		return c.Values[0].Rate, true
+	}
+
+	value := c.Values[0]
+	var lastPublished time.Time
+	for _, candidate := range c.Values {
+		if !candidate.Projected {
+			lastPublished = candidate.Month
+		}
+		if !candidate.Month.After(month) {
+			value = candidate
+		}
+	}
+	return value.Rate, value.Projected || month.After(lastPublished)
+}
+
+// AnnualRateAt is the index of the date plus the spread, in annual percentage
+func (c *IndexCurve) AnnualRateAt(date time.Time) *big.Rat {
+	index, _ := c.IndexAt(date)
+	return new(big.Rat).Add(money.ExactRat(index), money.ExactRat(c.Spread))
+}
+
+// MonthlyRateAt is the rate of a month starting at the date, kept as an exact fraction
+func (c *IndexCurve) MonthlyRateAt(date time.Time) *big.Rat {
+	return new(big.Rat).Quo(c.AnnualRateAt(date), big.NewRat(12*100, 1))
+}
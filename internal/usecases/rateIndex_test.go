@@ -0,0 +1,151 @@
+package usecases_test
+
+import (
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/Jonattas-21/loan-engine/internal/api/dto"
+	"github.com/Jonattas-21/loan-engine/internal/domain/entities"
+	"github.com/Jonattas-21/loan-engine/internal/usecases"
+	internalMock "github.com/Jonattas-21/loan-engine/tests"
+	"github.com/stretchr/testify/assert"
+)
+
+func month(year int, m time.Month) time.Time {
+	return time.Date(year, m, 1, 0, 0, 0, 0, time.UTC)
+}
+
+// cdiCurve is published at 12% up to March and projected at 9% from April
+var cdiCurve = []entities.IndexValue{
+	{Month: month(2026, time.January), Rate: 12},
+	{Month: month(2026, time.March), Rate: 12},
+	{Month: month(2026, time.April), Rate: 9, Projected: true},
+}
+
+func postFixedRequest(rateIndex string, assumedIndexRate float64) dto.SimulationRequest_dto {
+	simulationRequest := ladderRequest()
+	simulationRequest.Installments = 12
+	simulationRequest.DisbursementDate = time.Date(2026, 1, 10, 0, 0, 0, 0, time.UTC)
+	simulationRequest.RateIndex = rateIndex
+	simulationRequest.AssumedIndexRate = assumedIndexRate
+	return simulationRequest
+}
+
+func TestIndexCurve_indexAt(t *testing.T) {
+	assert := assert.New(t)
+	curve := usecases.IndexCurve{Index: entities.RateIndexCDI, Values: cdiCurve, Spread: 3}
+
+	testCases := []struct {
+		date      time.Time
+		rate      float64
+		estimated bool
+	}{
+		{time.Date(2025, 12, 10, 0, 0, 0, 0, time.UTC), 12, true}, // before the curve, its first value is an estimate
+		{time.Date(2026, 2, 10, 0, 0, 0, 0, time.UTC), 12, false}, // the value of January holds
+		{time.Date(2026, 3, 31, 0, 0, 0, 0, time.UTC), 12, false},
+		{time.Date(2026, 4, 1, 0, 0, 0, 0, time.UTC), 9, true},
+		{time.Date(2030, 1, 10, 0, 0, 0, 0, time.UTC), 9, true},
+	}
+	for _, testCase := range testCases {
+		rate, estimated := curve.IndexAt(testCase.date)
+		assert.Equal(testCase.rate, rate, testCase.date)
+		assert.Equal(testCase.estimated, estimated, testCase.date)
+	}
+
+	// a published value carried past the end of the curve is an estimate too
+	published := usecases.IndexCurve{Values: cdiCurve[:2]}
+	rate, estimated := published.IndexAt(time.Date(2026, 6, 10, 0, 0, 0, 0, time.UTC))
+	assert.Equal(12.0, rate)
+	assert.True(estimated)
+
+	rate, _ = curve.AnnualRateAt(time.Date(2026, 4, 10, 0, 0, 0, 0, time.UTC)).Float64()
+	assert.Equal(12.0, rate)
+}
+
+func TestIsStaleCurve(t *testing.T) {
+	assert := assert.New(t)
+
+	// april is projected, the curve is stale once april is over
+	assert.False(usecases.IsStaleCurve(cdiCurve, time.Date(2026, 3, 31, 0, 0, 0, 0, time.UTC)))
+	assert.False(usecases.IsStaleCurve(cdiCurve, time.Date(2026, 4, 15, 0, 0, 0, 0, time.UTC)))
+	assert.True(usecases.IsStaleCurve(cdiCurve, time.Date(2026, 5, 1, 0, 0, 0, 0, time.UTC)))
+	assert.False(usecases.IsStaleCurve(cdiCurve[:2], time.Date(2027, 1, 1, 0, 0, 0, 0, time.UTC)))
+}
+
+func TestValidateRateIndex(t *testing.T) {
+	assert := assert.New(t)
+	setupSimulation()
+
+	assert.Empty(loanSimulationUsecase.ValidateRateIndex(postFixedRequest("", 0)))
+	assert.Empty(loanSimulationUsecase.ValidateRateIndex(postFixedRequest("ipca", 4.5)))
+	assert.Equal([]string{"Rate index CDI is not available, an assumed index rate is required"}, loanSimulationUsecase.ValidateRateIndex(postFixedRequest("CDI", 0)))
+	assert.Equal([]string{"Rate index must be CDI, IPCA or SELIC", "Assumed index rate must not be negative"}, loanSimulationUsecase.ValidateRateIndex(postFixedRequest("TR", -1)))
+	assert.Equal([]string{"Assumed index rate is allowed only with a rate index"}, loanSimulationUsecase.ValidateRateIndex(postFixedRequest("", 10)))
+
+	loanSimulationUsecase.RateIndexProvider = new(internalMock.MockRateIndexProvider)
+	assert.Empty(loanSimulationUsecase.ValidateRateIndex(postFixedRequest("CDI", 0)))
+}
+
+func TestCalculateLoan_assumedIndexRate(t *testing.T) {
+	assert := assert.New(t)
+	setupSimulation()
+	mockLoanConditions()
+
+	// CDI assumed at 12% plus the 3% of tier2 is the same loan as a fixed rate of 15%
+	postFixed, err := loanSimulationUsecase.CalculateLoan(postFixedRequest("cdi", 12))
+	assert.NoError(err)
+
+	loanSimulationUsecase.PricingRules = []entities.PricingRule{{Name: "fixed15", InterestRate: 15, Priority: 1}}
+	fixed, err := loanSimulationUsecase.CalculateLoan(postFixedRequest("", 0))
+	assert.NoError(err)
+
+	assert.Equal(fixed.AmountTobePaid, postFixed.AmountTobePaid)
+	assert.Equal(15.0, postFixed.FeeAmountPercentage)
+	assert.Equal("CDI", postFixed.RateIndex)
+	assert.Equal(3.0, postFixed.IndexSpread)
+	assert.True(postFixed.Estimated)
+	assert.False(fixed.Estimated)
+	for i, installment := range postFixed.Installments {
+		assert.Equal(fixed.Installments[i].InstallmentAmount, installment.InstallmentAmount)
+		assert.Equal(12.0, installment.IndexRate)
+		assert.True(installment.Estimated)
+	}
+}
+
+func TestCalculateLoan_indexCurve(t *testing.T) {
+	assert := assert.New(t)
+	setupSimulation()
+	mockLoanConditions()
+	rateIndexProvider := new(internalMock.MockRateIndexProvider)
+	rateIndexProvider.On("GetIndexValues", "CDI").Return(cdiCurve, nil)
+	loanSimulationUsecase.RateIndexProvider = rateIndexProvider
+
+	loanSimulation, err := loanSimulationUsecase.CalculateLoan(postFixedRequest("cdi", 0))
+	assert.NoError(err)
+	rateIndexProvider.AssertCalled(t, "GetIndexValues", "CDI")
+
+	// quoted at the index of the disbursement plus the spread
+	assert.Equal(15.0, loanSimulation.FeeAmountPercentage)
+	assert.Equal(3.0, loanSimulation.IndexSpread)
+	assert.True(loanSimulation.Estimated)
+
+	installments := loanSimulation.Installments
+	assert.Len(installments, 12)
+	for i, installment := range installments {
+		if i < 3 {
+			assert.Equal(12.0, installment.IndexRate, installment.InstallmentNumber)
+			assert.False(installment.Estimated, installment.InstallmentNumber)
+		} else {
+			assert.Equal(9.0, installment.IndexRate, installment.InstallmentNumber)
+			assert.True(installment.Estimated, installment.InstallmentNumber)
+		}
+	}
+
+	// the level installment is recalculated when the projected index falls, the last one absorbs the rounding
+	assert.Equal(installments[0].InstallmentAmount, installments[2].InstallmentAmount)
+	assert.Equal(-1, installments[3].InstallmentAmount.Cmp(installments[2].InstallmentAmount))
+	assert.Equal(installments[3].InstallmentAmount, installments[10].InstallmentAmount)
+	assert.True(installments[11].ClosingBalance.IsZero())
+	assert.Equal(installments[3].OpeningBalance.Mul(big.NewRat(1, 100), loanSimulationUsecase.RoundingMode), installments[3].InstallmentFeeAmount)
+}
@@ -0,0 +1,124 @@
+package usecases
+
+import (
+	"fmt"
+	"math/big"
+
+	"github.com/Jonattas-21/loan-engine/internal/api/dto"
+	"github.com/Jonattas-21/loan-engine/internal/domain/entities"
+	"github.com/Jonattas-21/loan-engine/package/money"
+)
+
+// RefinancingOffer is the simulation request of the new loan, paying off the balance of the external one
+func RefinancingOffer(refinancingRequest dto.RefinancingRequest_dto) dto.SimulationRequest_dto {
+	simulationRequest := refinancingRequest.Offer
+	simulationRequest.LoanAmount = refinancingRequest.OutstandingBalance
+	if simulationRequest.Installments <= 0 {
+		simulationRequest.Installments = refinancingRequest.RemainingInstallments
+	}
+	return simulationRequest
+}
+
+// ValidateRefinancingRequest checks the external loan and the request of the new one
+func (l *LoanSimulation_usecase) ValidateRefinancingRequest(refinancingRequest dto.RefinancingRequest_dto) []string {
+	errs := []string{}
+
+	if !refinancingRequest.OutstandingBalance.IsPositive() {
+		errs = append(errs, "Outstanding balance is required above 0")
+	}
+	if refinancingRequest.CurrentInterestRate < 0 {
+		errs = append(errs, "Current interest rate must not be negative")
+	}
+	if refinancingRequest.RemainingInstallments <= 0 {
+		errs = append(errs, "Remaining installments is required above 0")
+	}
+	if refinancingRequest.CurrentInstallment.IsNegative() {
+		errs = append(errs, "Current installment must not be negative")
+	}
+
+	offer := RefinancingOffer(refinancingRequest)
+	if l.SimulationModeName(offer.SimulationMode) == SimulationModeInstallment {
+		errs = append(errs, "Refinancing offers are not allowed in INSTALLMENT mode, the loan amount is the outstanding balance")
+	}
+	if IsOfferLadder(offer) {
+		errs = append(errs, "Refinancing offers are simulated over a single term")
+	}
+	if refinancingRequest.OutstandingBalance.IsPositive() {
+		errs = append(errs, l.ValidateSimulationRequest(offer)...)
+	}
+
+	if len(errs) > 0 {
+		return errs
+	}
+
+	return nil
+}
+
+// CompareRefinancing simulates the offer paying off the external loan with our rate and compares the cash flows:
+// the installments left of the current loan against the charges paid upfront and the installments of the offer.
+// The offer is saved like any other simulation.
+func (l *LoanSimulation_usecase) CompareRefinancing(refinancingRequest dto.RefinancingRequest_dto) (entities.RefinancingComparison, error) {
+	offer, err := l.CalculateLoan(RefinancingOffer(refinancingRequest))
+	if err != nil {
+		return entities.RefinancingComparison{}, fmt.Errorf("error calculating refinancing offer, %w", err)
+	}
+
+	err = l.LoanSimulationRepository.SaveItemCollection(offer)
+	if err != nil {
+		l.Logger.Errorln(fmt.Sprintf("[email:%v] Error saving refinancing offer", offer.Email), err.Error())
+		return entities.RefinancingComparison{}, fmt.Errorf("error saving refinancing offer, %v", err.Error())
+	}
+	l.cacheSimulationByID(offer)
+
+	currentInstallment := refinancingRequest.CurrentInstallment
+	if currentInstallment.IsZero() {
+		currentInstallment = l.currentInstallment(refinancingRequest)
+	}
+
+	comparison := entities.RefinancingComparison{
+		OutstandingBalance:    refinancingRequest.OutstandingBalance,
+		CurrentInterestRate:   refinancingRequest.CurrentInterestRate,
+		RemainingInstallments: refinancingRequest.RemainingInstallments,
+		CurrentInstallment:    currentInstallment,
+		CurrentTotal:          currentInstallment.Mul(big.NewRat(int64(refinancingRequest.RemainingInstallments), 1), l.RoundingMode),
+		NewInstallment:        MaxInstallment(offer.Installments),
+		NewTotal:              offer.AmountTobePaid.Add(offer.UpfrontAmount),
+		Offer:                 offer,
+	}
+	comparison.InstallmentSavings = comparison.CurrentInstallment.Sub(comparison.NewInstallment)
+	comparison.TotalSavings = comparison.CurrentTotal.Sub(comparison.NewTotal)
+
+	// month by month savings, starting from the upfront charges of the offer
+	savings := offer.UpfrontAmount.Neg()
+	months := len(offer.Installments)
+	if refinancingRequest.RemainingInstallments > months {
+		months = refinancingRequest.RemainingInstallments
+	}
+	for month := 1; month <= months; month++ {
+		if month <= refinancingRequest.RemainingInstallments {
+			savings = savings.Add(currentInstallment)
+		}
+		if month <= len(offer.Installments) {
+			savings = savings.Sub(offer.Installments[month-1].InstallmentAmount)
+		}
+
+		if savings.IsNegative() {
+			comparison.BreakEvenInstallment = 0
+		} else if comparison.BreakEvenInstallment == 0 {
+			comparison.BreakEvenInstallment = month
+		}
+	}
+
+	return comparison, nil
+}
+
+// currentInstallment is the installment of the external loan by PRICE, the balance over the installments left at the current rate
+func (l *LoanSimulation_usecase) currentInstallment(refinancingRequest dto.RefinancingRequest_dto) money.Money {
+	installments := (&PriceCalculator{}).CreateInstallments(AmortizationInput{
+		LoanAmount:          refinancingRequest.OutstandingBalance,
+		MonthlyInterestRate: new(big.Rat).Quo(money.ExactRat(refinancingRequest.CurrentInterestRate), big.NewRat(12*100, 1)),
+		TotalInstallments:   refinancingRequest.RemainingInstallments,
+		Rounding:            l.RoundingMode,
+	})
+	return installments[0].InstallmentAmount
+}
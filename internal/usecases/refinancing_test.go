@@ -0,0 +1,100 @@
+package usecases_test
+
+import (
+	"testing"
+
+	"github.com/Jonattas-21/loan-engine/internal/api/dto"
+	"github.com/Jonattas-21/loan-engine/internal/domain/entities"
+	"github.com/Jonattas-21/loan-engine/package/money"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+// refinancingRequest is an external loan of 10000 left in 12 installments, the offer is priced at the 3% per year of tier2
+func refinancingRequest(currentInterestRate float64) dto.RefinancingRequest_dto {
+	return dto.RefinancingRequest_dto{
+		OutstandingBalance:    money.MustParse("10000"),
+		CurrentInterestRate:   currentInterestRate,
+		RemainingInstallments: 12,
+		Offer:                 ladderRequest(),
+	}
+}
+
+func setupRefinancing() {
+	setupSimulation()
+	mockLoanConditions()
+	mockSimulationDatabaseRepo.On("SaveItemCollection", mock.Anything).Return(nil)
+	mockCacheRepo.On("Set", mock.Anything, mock.Anything, mock.Anything).Return(nil)
+}
+
+func TestValidateRefinancingRequest(t *testing.T) {
+	assert := assert.New(t)
+	setupSimulation()
+
+	assert.Nil(loanSimulationUsecase.ValidateRefinancingRequest(refinancingRequest(60)))
+
+	invalid := refinancingRequest(-1)
+	invalid.RemainingInstallments = 0
+	invalid.Offer.SimulationMode = "INSTALLMENT"
+	invalid.Offer.InstallmentAmount = money.MustParse("500")
+	assert.Equal([]string{
+		"Current interest rate must not be negative",
+		"Remaining installments is required above 0",
+		"Refinancing offers are not allowed in INSTALLMENT mode, the loan amount is the outstanding balance",
+		"Installments is required above 0",
+	}, loanSimulationUsecase.ValidateRefinancingRequest(invalid))
+}
+
+func TestCompareRefinancing_savings(t *testing.T) {
+	assert := assert.New(t)
+	setupRefinancing()
+
+	comparison, err := loanSimulationUsecase.CompareRefinancing(refinancingRequest(60))
+
+	assert.NoError(err)
+	// 5% a month by PRICE against the 3% per year of the offer, the financed IOF included
+	assert.Equal("1128.25", comparison.CurrentInstallment.String())
+	assert.Equal("13539.00", comparison.CurrentTotal.String())
+	assert.Equal("10000.00", comparison.Offer.LoanAmount.String())
+	assert.Equal(12, comparison.Offer.TotalInstallments)
+	assert.Equal(comparison.CurrentInstallment.Sub(comparison.NewInstallment), comparison.InstallmentSavings)
+	assert.Equal(comparison.CurrentTotal.Sub(comparison.NewTotal), comparison.TotalSavings)
+	assert.True(comparison.TotalSavings.IsPositive())
+	assert.Equal(1, comparison.BreakEvenInstallment)
+	mockSimulationDatabaseRepo.AssertCalled(t, "SaveItemCollection", comparison.Offer)
+}
+
+func TestCompareRefinancing_upfrontCharges(t *testing.T) {
+	assert := assert.New(t)
+	setupRefinancing()
+
+	// the upfront IOF is paid back by the smaller installments after some months
+	request := refinancingRequest(0)
+	request.CurrentInstallment = money.MustParse("880")
+	request.Offer.IOFPayment = entities.PaymentUpfront
+
+	comparison, err := loanSimulationUsecase.CompareRefinancing(request)
+
+	assert.NoError(err)
+	assert.Equal("880.00", comparison.CurrentInstallment.String())
+	assert.True(comparison.Offer.UpfrontAmount.IsPositive())
+	assert.Greater(comparison.BreakEvenInstallment, 1)
+
+	savings := comparison.Offer.UpfrontAmount.Neg()
+	for month := 1; month <= 12; month++ {
+		savings = savings.Add(comparison.CurrentInstallment).Sub(comparison.Offer.Installments[month-1].InstallmentAmount)
+		assert.Equal(month >= comparison.BreakEvenInstallment, !savings.IsNegative(), "month %v", month)
+	}
+}
+
+func TestCompareRefinancing_noSavings(t *testing.T) {
+	assert := assert.New(t)
+	setupRefinancing()
+
+	comparison, err := loanSimulationUsecase.CompareRefinancing(refinancingRequest(1))
+
+	assert.NoError(err)
+	assert.True(comparison.InstallmentSavings.IsNegative())
+	assert.True(comparison.TotalSavings.IsNegative())
+	assert.Equal(0, comparison.BreakEvenInstallment)
+}
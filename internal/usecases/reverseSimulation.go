@@ -0,0 +1,122 @@
+package usecases
+
+import (
+	"fmt"
+
+	"github.com/Jonattas-21/loan-engine/internal/api/dto"
+	"github.com/Jonattas-21/loan-engine/internal/domain/entities"
+	"github.com/Jonattas-21/loan-engine/package/money"
+)
+
+// What a simulation request solves for
+const (
+	SimulationModeAmount      = "AMOUNT"
+	SimulationModeInstallment = "INSTALLMENT"
+	SimulationModeTerm        = "TERM"
+
+	// product maximum term used when none is configured
+	defaultMaxInstallments = 360
+)
+
+// MaxInstallment is the highest installment of the schedule, the first one in SAC and any of them in PRICE
+func MaxInstallment(installments []entities.Installment) money.Money {
+	var maxInstallment money.Money
+	for _, installment := range installments {
+		maxInstallment = money.Max(maxInstallment, installment.InstallmentAmount)
+	}
+	return maxInstallment
+}
+
+// SolveLoanAmount finds the maximum loan amount whose installments all fit in the requested installment amount,
+// with the same charges, grace and dates of a regular simulation. The installments grow with the loan amount,
+// so the cents are searched by bisection, the loan amount times the term is always above the answer.
+func (l *LoanSimulation_usecase) SolveLoanAmount(simulationRequest dto.SimulationRequest_dto, pricing Pricing) (entities.LoanSimulation, error) {
+	targetInstallment := simulationRequest.InstallmentAmount
+	if !targetInstallment.IsPositive() || simulationRequest.Installments <= 0 {
+		return entities.LoanSimulation{}, fmt.Errorf("installment amount and installments are required to simulate by installment")
+	}
+
+	simulate := func(cents int64) (entities.LoanSimulation, bool, error) {
+		request := simulationRequest
+		request.LoanAmount = money.FromCents(cents)
+		loanSimulation, err := l.Simulate(request, pricing)
+		if err != nil {
+			return entities.LoanSimulation{}, false, err
+		}
+		return loanSimulation, MaxInstallment(loanSimulation.Installments).Cmp(targetInstallment) <= 0, nil
+	}
+
+	var best entities.LoanSimulation
+	low, high := int64(1), targetInstallment.Cents()*int64(simulationRequest.Installments)
+	for low <= high {
+		middle := low + (high-low)/2
+		loanSimulation, fits, err := simulate(middle)
+		if err != nil {
+			return entities.LoanSimulation{}, err
+		}
+
+		if fits {
+			best = loanSimulation
+			low = middle + 1
+		} else {
+			high = middle - 1
+		}
+	}
+
+	if best.LoanAmount.IsZero() {
+		return entities.LoanSimulation{}, fmt.Errorf("installment amount %v is too low for %v installments", targetInstallment, simulationRequest.Installments)
+	}
+
+	best.RequestedInstallment = targetInstallment
+	return best, nil
+}
+
+// SolveInstallments finds the smallest term whose installments all fit under the requested installment amount.
+// A longer term never raises the installments, so the terms are searched by bisection up to the product maximum.
+func (l *LoanSimulation_usecase) SolveInstallments(simulationRequest dto.SimulationRequest_dto, pricing Pricing) (entities.LoanSimulation, error) {
+	maxInstallment := simulationRequest.InstallmentAmount
+	if !maxInstallment.IsPositive() || !simulationRequest.LoanAmount.IsPositive() {
+		return entities.LoanSimulation{}, fmt.Errorf("loan amount and installment amount are required to simulate by term")
+	}
+
+	simulate := func(installments int) (entities.LoanSimulation, bool, error) {
+		request := simulationRequest
+		request.Installments = installments
+		loanSimulation, err := l.Simulate(request, pricing)
+		if err != nil {
+			return entities.LoanSimulation{}, false, err
+		}
+		return loanSimulation, MaxInstallment(loanSimulation.Installments).Cmp(maxInstallment) <= 0, nil
+	}
+
+	var best entities.LoanSimulation
+	low, high := 1, l.maxInstallments()
+	for low <= high {
+		middle := low + (high-low)/2
+		loanSimulation, fits, err := simulate(middle)
+		if err != nil {
+			return entities.LoanSimulation{}, err
+		}
+
+		if fits {
+			best = loanSimulation
+			high = middle - 1
+		} else {
+			low = middle + 1
+		}
+	}
+
+	if best.TotalInstallments == 0 {
+		return entities.LoanSimulation{}, fmt.Errorf("no term up to %v installments keeps the installments of %v under %v", l.maxInstallments(), simulationRequest.LoanAmount, maxInstallment)
+	}
+
+	best.RequestedInstallment = maxInstallment
+	return best, nil
+}
+
+func (l *LoanSimulation_usecase) maxInstallments() int {
+	if l.MaxInstallments > 0 {
+		return l.MaxInstallments
+	}
+	return defaultMaxInstallments
+}
@@ -0,0 +1,132 @@
+package usecases_test
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/Jonattas-21/loan-engine/internal/api/dto"
+	"github.com/Jonattas-21/loan-engine/internal/domain/entities"
+	"github.com/Jonattas-21/loan-engine/internal/usecases"
+	"github.com/Jonattas-21/loan-engine/package/money"
+	"github.com/stretchr/testify/assert"
+)
+
+func mockLoanConditions() {
+	loanConditions := []entities.LoanCondition{
+		{ID: "01JH8Z5Q4E2V3W6X7Y8Z9A0B1C", Name: "tier1", Version: 1, InterestRate: 5, MinAge: 18, MaxAge: 25},
+		{ID: "01JH8Z5Q4E2V3W6X7Y8Z9A0B1D", Name: "tier2", Version: 2, InterestRate: 3, MinAge: 26, MaxAge: 40},
+	}
+	jsonConditions, _ := json.Marshal(loanConditions)
+
+	mockConditionDatabaseRepo.On("GetItemsCollection", "loan_conditions").Return(loanConditions, nil)
+	mockCacheRepo.On("Get", "loan_conditions").Return(string(jsonConditions), nil)
+	mockCacheRepo.On("Set", "loan_conditions", jsonConditions, time.Minute*10).Return(nil)
+}
+
+func TestCalculateLoan_byInstallment(t *testing.T) {
+	assert := assert.New(t)
+	setupSimulation()
+	mockLoanConditions()
+
+	testCases := []string{"PRICE", "SAC"}
+	for _, amortizationSystem := range testCases {
+		simulationRequest := dto.SimulationRequest_dto{
+			Email:              "test@example.com",
+			Installments:       12,
+			BithDate:           time.Date(1990, 1, 1, 0, 0, 0, 0, time.UTC),
+			Currency:           "R$",
+			AmortizationSystem: amortizationSystem,
+			SimulationMode:     "installment",
+			InstallmentAmount:  money.MustParse("800"),
+		}
+
+		result, err := loanSimulationUsecase.CalculateLoan(simulationRequest)
+
+		assert.NoError(err)
+		assert.Equal("INSTALLMENT", result.SimulationMode)
+		assert.Equal("01JH8Z5Q4E2V3W6X7Y8Z9A0B1D", result.LoanConditionID)
+		assert.Equal(2, result.LoanConditionVersion)
+		assert.Equal("800.00", result.RequestedInstallment.String())
+		assert.Len(result.Installments, 12)
+		assert.LessOrEqual(usecases.MaxInstallment(result.Installments).Cmp(simulationRequest.InstallmentAmount), 0, amortizationSystem)
+
+		// one cent more does not fit anymore
+		simulationRequest.LoanAmount = result.LoanAmount.Add(money.FromCents(1))
+		simulationRequest.SimulationMode = ""
+		above, err := loanSimulationUsecase.CalculateLoan(simulationRequest)
+		assert.NoError(err)
+		assert.Positive(usecases.MaxInstallment(above.Installments).Cmp(money.MustParse("800")), amortizationSystem)
+	}
+}
+
+func TestValidateSimulationRequest_byInstallment(t *testing.T) {
+	assert := assert.New(t)
+	setupSimulation()
+
+	simulationRequest := dto.SimulationRequest_dto{
+		Installments:   12,
+		BithDate:       time.Date(1990, 1, 1, 0, 0, 0, 0, time.UTC),
+		Currency:       "R$",
+		SimulationMode: "INSTALLMENT",
+	}
+
+	// the loan amount is what is solved, only the installment is required
+	errors := loanSimulationUsecase.ValidateSimulationRequest(simulationRequest)
+	assert.Contains(errors, "Installment amount is required above 0 to simulate by installment")
+	assert.NotContains(errors, "Loan amount is required above 0")
+
+	simulationRequest.SimulationMode = "TOTAL"
+	assert.Contains(loanSimulationUsecase.ValidateSimulationRequest(simulationRequest), "Simulation mode must be AMOUNT, INSTALLMENT or TERM")
+}
+
+func TestCalculateLoan_byTerm(t *testing.T) {
+	assert := assert.New(t)
+	setupSimulation()
+	mockLoanConditions()
+
+	simulationRequest := dto.SimulationRequest_dto{
+		Email:             "test@example.com",
+		LoanAmount:        money.MustParse("10000"),
+		BithDate:          time.Date(1990, 1, 1, 0, 0, 0, 0, time.UTC),
+		Currency:          "R$",
+		SimulationMode:    "TERM",
+		InstallmentAmount: money.MustParse("800"),
+	}
+
+	result, err := loanSimulationUsecase.CalculateLoan(simulationRequest)
+
+	assert.NoError(err)
+	assert.Equal("TERM", result.SimulationMode)
+	assert.Equal(13, result.TotalInstallments)
+	assert.Len(result.Installments, 13)
+	assert.LessOrEqual(usecases.MaxInstallment(result.Installments).Cmp(simulationRequest.InstallmentAmount), 0)
+
+	// one installment less is above the maximum
+	simulationRequest.SimulationMode = ""
+	simulationRequest.Installments = result.TotalInstallments - 1
+	shorter, err := loanSimulationUsecase.CalculateLoan(simulationRequest)
+	assert.NoError(err)
+	assert.Positive(usecases.MaxInstallment(shorter.Installments).Cmp(simulationRequest.InstallmentAmount))
+}
+
+func TestCalculateLoan_byTermNotFound(t *testing.T) {
+	assert := assert.New(t)
+	setupSimulation()
+	mockLoanConditions()
+	loanSimulationUsecase.MaxInstallments = 12
+
+	simulationRequest := dto.SimulationRequest_dto{
+		LoanAmount:        money.MustParse("10000"),
+		BithDate:          time.Date(1990, 1, 1, 0, 0, 0, 0, time.UTC),
+		Currency:          "R$",
+		SimulationMode:    "TERM",
+		InstallmentAmount: money.MustParse("800"),
+	}
+
+	// the installments are not required, the term is what is solved
+	assert.Nil(loanSimulationUsecase.ValidateSimulationRequest(simulationRequest))
+
+	_, err := loanSimulationUsecase.CalculateLoan(simulationRequest)
+	assert.EqualError(err, "no term up to 12 installments keeps the installments of 10000.00 under 800.00")
+}
@@ -0,0 +1,281 @@
+package money
+
+import (
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"strconv"
+	"strings"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/bsontype"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// RoundingMode defines how a value with more than two decimals becomes cents
+type RoundingMode int
+
+const (
+	HalfEven RoundingMode = iota
+	HalfUp
+	Truncate
+)
+
+var roundingModeNames = map[RoundingMode]string{
+	HalfEven: "HALF_EVEN",
+	HalfUp:   "HALF_UP",
+	Truncate: "TRUNCATE",
+}
+
+func (r RoundingMode) String() string {
+	return roundingModeNames[r]
+}
+
+// ParseRoundingMode reads HALF_EVEN, HALF_UP or TRUNCATE, empty means HALF_EVEN
+func ParseRoundingMode(value string) (RoundingMode, error) {
+	value = strings.ToUpper(strings.TrimSpace(value))
+	if value == "" {
+		return HalfEven, nil
+	}
+	for mode, name := range roundingModeNames {
+		if name == value {
+			return mode, nil
+		}
+	}
+	return HalfEven, fmt.Errorf("rounding mode %v is not supported, use HALF_EVEN, HALF_UP or TRUNCATE", value)
+}
+
+var centsPerUnit = big.NewInt(100)
+
+// Money is an exact amount with two decimals, stored as cents to avoid float64 drift
+type Money struct {
+	cents int64
+}
+
+func Zero() Money {
+	return Money{}
+}
+
+func FromCents(cents int64) Money {
+	return Money{cents: cents}
+}
+
+// FromFloat converts using the shortest decimal representation of value, so 0.1 is read as 0.1 and not as its binary approximation
+func FromFloat(value float64, mode RoundingMode) Money {
+	return FromRat(ExactRat(value), mode)
+}
+
+// FromRat rounds an exact rational value to cents
+func FromRat(value *big.Rat, mode RoundingMode) Money {
+	scaled := new(big.Int).Mul(value.Num(), centsPerUnit)
+	return Money{cents: roundQuo(scaled, value.Denom(), mode).Int64()}
+}
+
+// Parse reads a decimal string like "1234.56", extra decimals are rounded half even
+func Parse(value string) (Money, error) {
+	rat, ok := new(big.Rat).SetString(strings.TrimSpace(value))
+	if !ok {
+		return Money{}, fmt.Errorf("invalid money value %q", value)
+	}
+	return FromRat(rat, HalfEven), nil
+}
+
+func MustParse(value string) Money {
+	m, err := Parse(value)
+	if err != nil {
+		panic(err)
+	}
+	return m
+}
+
+// ExactRat converts a float64 through its shortest decimal representation
+func ExactRat(value float64) *big.Rat {
+	rat, _ := new(big.Rat).SetString(strconv.FormatFloat(value, 'f', -1, 64))
+	return rat
+}
+
+func (m Money) Cents() int64 {
+	return m.cents
+}
+
+func (m Money) Rat() *big.Rat {
+	return new(big.Rat).SetFrac(big.NewInt(m.cents), centsPerUnit)
+}
+
+func (m Money) Float64() float64 {
+	return float64(m.cents) / 100
+}
+
+func (m Money) Add(other Money) Money {
+	return Money{cents: m.cents + other.cents}
+}
+
+func (m Money) Sub(other Money) Money {
+	return Money{cents: m.cents - other.cents}
+}
+
+func (m Money) Neg() Money {
+	return Money{cents: -m.cents}
+}
+
+// Mul multiplies by an exact factor, like an interest rate, and rounds the result to cents
+func (m Money) Mul(factor *big.Rat, mode RoundingMode) Money {
+	return m.MulFraction(factor.Num(), factor.Denom(), mode)
+}
+
+// MulFraction multiplies by numerator/denominator without reducing the fraction, useful for big powers like (1+r)^n
+func (m Money) MulFraction(numerator *big.Int, denominator *big.Int, mode RoundingMode) Money {
+	product := new(big.Int).Mul(big.NewInt(m.cents), numerator)
+	return Money{cents: roundQuo(product, denominator, mode).Int64()}
+}
+
+// Div splits the amount in n parts rounded to cents, the caller decides where the remainder goes
+func (m Money) Div(n int64, mode RoundingMode) Money {
+	return Money{cents: roundQuo(big.NewInt(m.cents), big.NewInt(n), mode).Int64()}
+}
+
+func (m Money) Cmp(other Money) int {
+	switch {
+	case m.cents < other.cents:
+		return -1
+	case m.cents > other.cents:
+		return 1
+	}
+	return 0
+}
+
+func (m Money) IsZero() bool {
+	return m.cents == 0
+}
+
+func (m Money) IsNegative() bool {
+	return m.cents < 0
+}
+
+func (m Money) IsPositive() bool {
+	return m.cents > 0
+}
+
+func Min(a Money, b Money) Money {
+	if a.cents < b.cents {
+		return a
+	}
+	return b
+}
+
+func Max(a Money, b Money) Money {
+	if a.cents > b.cents {
+		return a
+	}
+	return b
+}
+
+func Sum(values ...Money) Money {
+	var total Money
+	for _, value := range values {
+		total = total.Add(value)
+	}
+	return total
+}
+
+// String always prints two decimals, 1234.5 becomes "1234.50"
+func (m Money) String() string {
+	sign := ""
+	cents := m.cents
+	if cents < 0 {
+		sign = "-"
+		cents = -cents
+	}
+	return fmt.Sprintf("%s%d.%02d", sign, cents/100, cents%100)
+}
+
+// MarshalJSON writes the amount as a JSON number with two decimals, so the API keeps returning numbers
+func (m Money) MarshalJSON() ([]byte, error) {
+	return []byte(m.String()), nil
+}
+
+// UnmarshalJSON accepts both numbers and strings
+func (m *Money) UnmarshalJSON(data []byte) error {
+	value := strings.TrimSpace(string(data))
+	if value == "null" {
+		*m = Money{}
+		return nil
+	}
+	if strings.HasPrefix(value, `"`) {
+		var text string
+		if err := json.Unmarshal(data, &text); err != nil {
+			return err
+		}
+		value = text
+	}
+	parsed, err := Parse(value)
+	if err != nil {
+		return err
+	}
+	*m = parsed
+	return nil
+}
+
+// MarshalBSONValue persists the amount as Decimal128, so mongo keeps the exact value and can still sort and filter by it
+func (m Money) MarshalBSONValue() (bsontype.Type, []byte, error) {
+	decimal, err := primitive.ParseDecimal128(m.String())
+	if err != nil {
+		return 0, nil, err
+	}
+	return bson.MarshalValue(decimal)
+}
+
+// UnmarshalBSONValue reads Decimal128 and, for documents saved before the money type, doubles and integers
+func (m *Money) UnmarshalBSONValue(t bsontype.Type, data []byte) error {
+	raw := bson.RawValue{Type: t, Value: data}
+
+	switch t {
+	case bsontype.Decimal128:
+		parsed, err := Parse(raw.Decimal128().String())
+		if err != nil {
+			return err
+		}
+		*m = parsed
+	case bsontype.Double:
+		*m = FromFloat(raw.Double(), HalfEven)
+	case bsontype.Int32:
+		*m = FromCents(int64(raw.Int32()) * 100)
+	case bsontype.Int64:
+		*m = FromCents(raw.Int64() * 100)
+	case bsontype.String:
+		parsed, err := Parse(raw.StringValue())
+		if err != nil {
+			return err
+		}
+		*m = parsed
+	case bsontype.Null:
+		*m = Money{}
+	default:
+		return fmt.Errorf("cannot decode bson type %v into money", t)
+	}
+	return nil
+}
+
+// roundQuo divides numerator by a positive denominator and rounds to an integer following the rounding mode,
+// working on integers avoids normalizing big rationals on every installment
+func roundQuo(numerator *big.Int, denominator *big.Int, mode RoundingMode) *big.Int {
+	quotient, remainder := new(big.Int).QuoRem(numerator, denominator, new(big.Int))
+	if remainder.Sign() == 0 || mode == Truncate {
+		return quotient
+	}
+
+	// compare the remainder with half of the denominator
+	twiceRemainder := new(big.Int).Abs(remainder)
+	twiceRemainder.Lsh(twiceRemainder, 1)
+	half := twiceRemainder.Cmp(denominator)
+
+	roundAway := half > 0 || (half == 0 && (mode == HalfUp || quotient.Bit(0) == 1))
+	if roundAway {
+		if numerator.Sign() < 0 {
+			quotient.Sub(quotient, big.NewInt(1))
+		} else {
+			quotient.Add(quotient, big.NewInt(1))
+		}
+	}
+	return quotient
+}
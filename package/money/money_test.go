@@ -0,0 +1,109 @@
+package money_test
+
+import (
+	"encoding/json"
+	"math/big"
+	"testing"
+
+	"github.com/Jonattas-21/loan-engine/package/money"
+	"github.com/stretchr/testify/assert"
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+func TestFromFloat_rounding(t *testing.T) {
+	assert := assert.New(t)
+
+	testCases := []struct {
+		input    float64
+		mode     money.RoundingMode
+		expected string
+	}{
+		{123.456789, money.Truncate, "123.45"},
+		{987.654321, money.Truncate, "987.65"},
+		{1.999999, money.Truncate, "1.99"},
+		{0.123456, money.Truncate, "0.12"},
+		{100.0, money.Truncate, "100.00"},
+		{1.005, money.HalfUp, "1.01"},
+		{1.005, money.HalfEven, "1.00"},
+		{1.015, money.HalfEven, "1.02"},
+		{-1.005, money.HalfUp, "-1.01"},
+		{-1.005, money.HalfEven, "-1.00"},
+		{-1.009, money.Truncate, "-1.00"},
+		{0.1 + 0.2, money.HalfEven, "0.30"},
+	}
+
+	for _, tc := range testCases {
+		result := money.FromFloat(tc.input, tc.mode)
+		assert.Equal(tc.expected, result.String(), "Input: %v, Mode: %v", tc.input, tc.mode)
+	}
+}
+
+func TestMoney_arithmetic(t *testing.T) {
+	assert := assert.New(t)
+
+	a := money.MustParse("10.10")
+	b := money.MustParse("0.20")
+
+	assert.Equal("10.30", a.Add(b).String())
+	assert.Equal("9.90", a.Sub(b).String())
+	assert.Equal("-0.20", b.Neg().String())
+	assert.Equal("3.37", a.Div(3, money.HalfEven).String())
+	assert.Equal("0.25", money.MustParse("10000").Mul(big.NewRat(1, 40000), money.HalfEven).String())
+	assert.Equal("10.50", money.Sum(a, b, b).String())
+	assert.Equal(int64(1010), a.Cents())
+	assert.Equal(1, a.Cmp(b))
+	assert.Equal(b, money.Min(a, b))
+}
+
+func TestParseRoundingMode(t *testing.T) {
+	assert := assert.New(t)
+
+	mode, err := money.ParseRoundingMode("half_up")
+	assert.NoError(err)
+	assert.Equal(money.HalfUp, mode)
+
+	mode, err = money.ParseRoundingMode("")
+	assert.NoError(err)
+	assert.Equal(money.HalfEven, mode)
+
+	_, err = money.ParseRoundingMode("CEILING")
+	assert.Error(err)
+}
+
+func TestMoney_json(t *testing.T) {
+	assert := assert.New(t)
+
+	data, err := json.Marshal(struct {
+		Amount money.Money `json:"amount"`
+	}{Amount: money.MustParse("1234.5")})
+	assert.NoError(err)
+	assert.Equal(`{"amount":1234.50}`, string(data))
+
+	var fromNumber, fromString money.Money
+	assert.NoError(json.Unmarshal([]byte(`1234.56`), &fromNumber))
+	assert.NoError(json.Unmarshal([]byte(`"1234.56"`), &fromString))
+	assert.Equal(int64(123456), fromNumber.Cents())
+	assert.Equal(fromNumber, fromString)
+	assert.Error(json.Unmarshal([]byte(`"abc"`), &fromString))
+}
+
+func TestMoney_bson(t *testing.T) {
+	assert := assert.New(t)
+
+	type document struct {
+		Amount money.Money
+	}
+
+	data, err := bson.Marshal(document{Amount: money.MustParse("87.69")})
+	assert.NoError(err)
+	assert.Equal("87.69", bson.Raw(data).Lookup("amount").Decimal128().String())
+
+	var decoded document
+	assert.NoError(bson.Unmarshal(data, &decoded))
+	assert.Equal("87.69", decoded.Amount.String())
+
+	// documents saved with float64 before the money type
+	legacy, _ := bson.Marshal(bson.M{"amount": 10087.68})
+	assert.NoError(bson.Unmarshal(legacy, &decoded))
+	assert.Equal("10087.68", decoded.Amount.String())
+}
@@ -0,0 +1,43 @@
+// Package pagination encodes the cursors of a keyset pagination: the position after the last item of a page
+package pagination
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+)
+
+// ErrInvalidCursor is returned when a cursor was not made by Encode
+var ErrInvalidCursor = errors.New("invalid cursor")
+
+// Cursor is the sort field and value of the last item of a page, the id breaks the ties
+type Cursor struct {
+	Sort  string `json:"sort"`
+	Value string `json:"value"`
+	ID    string `json:"id"`
+}
+
+// Encode returns the cursor as an opaque url safe string
+func Encode(cursor Cursor) string {
+	jsonCursor, _ := json.Marshal(cursor)
+	return base64.RawURLEncoding.EncodeToString(jsonCursor)
+}
+
+// Decode reads a cursor made by Encode, it fails when the cursor has no sort field or id
+func Decode(encoded string) (Cursor, error) {
+	jsonCursor, err := base64.RawURLEncoding.DecodeString(encoded)
+	if err != nil {
+		return Cursor{}, fmt.Errorf("%w, %v", ErrInvalidCursor, err.Error())
+	}
+
+	var cursor Cursor
+	err = json.Unmarshal(jsonCursor, &cursor)
+	if err != nil {
+		return Cursor{}, fmt.Errorf("%w, %v", ErrInvalidCursor, err.Error())
+	}
+	if cursor.Sort == "" || cursor.ID == "" {
+		return Cursor{}, fmt.Errorf("%w, no sort or id", ErrInvalidCursor)
+	}
+	return cursor, nil
+}
@@ -0,0 +1,22 @@
+package pagination_test
+
+import (
+	"testing"
+
+	"github.com/Jonattas-21/loan-engine/package/pagination"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestEncodeDecode(t *testing.T) {
+	assert := assert.New(t)
+
+	cursor := pagination.Cursor{Sort: "loan_amount", Value: "1000.00", ID: "01JH8Z5Q4E2V3W6X7Y8Z9A0B1C"}
+	decoded, err := pagination.Decode(pagination.Encode(cursor))
+	assert.NoError(err)
+	assert.Equal(cursor, decoded)
+
+	_, err = pagination.Decode("not a cursor")
+	assert.ErrorIs(err, pagination.ErrInvalidCursor)
+	_, err = pagination.Decode(pagination.Encode(pagination.Cursor{Sort: "loan_amount", Value: "1000.00"}))
+	assert.ErrorIs(err, pagination.ErrInvalidCursor)
+}
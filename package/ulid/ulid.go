@@ -0,0 +1,46 @@
+// Package ulid generates ULIDs, 26 characters identifiers that sort by their creation time:
+// 48 bits of unix milliseconds followed by 80 random bits, in Crockford's base32.
+package ulid
+
+import (
+	"crypto/rand"
+	"fmt"
+	"time"
+)
+
+const encoding = "0123456789ABCDEFGHJKMNPQRSTVWXYZ"
+
+// New returns the ULID of a moment, two ULIDs of the same millisecond differ by the random part
+func New(t time.Time) (string, error) {
+	var id [16]byte
+	ms := uint64(t.UnixMilli())
+	for i := 5; i >= 0; i-- {
+		id[i] = byte(ms)
+		ms >>= 8
+	}
+
+	_, err := rand.Read(id[6:])
+	if err != nil {
+		return "", fmt.Errorf("error generating ulid, %v", err.Error())
+	}
+
+	return encode(id), nil
+}
+
+// encode writes the 128 bits as 26 base32 characters, the first one only carries 3 bits
+func encode(id [16]byte) string {
+	var out [26]byte
+	var buffer uint64
+	bits := 2 // 26*5 - 128 leading zero bits
+	position := 0
+	for _, b := range id {
+		buffer = buffer<<8 | uint64(b)
+		bits += 8
+		for bits >= 5 {
+			bits -= 5
+			out[position] = encoding[(buffer>>uint(bits))&31]
+			position++
+		}
+	}
+	return string(out[:])
+}
@@ -0,0 +1,31 @@
+package ulid_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/Jonattas-21/loan-engine/package/ulid"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNew(t *testing.T) {
+	assert := assert.New(t)
+	moment := time.Date(2025, 1, 10, 12, 30, 0, 123000000, time.UTC)
+
+	id, err := ulid.New(moment)
+	assert.NoError(err)
+	assert.Len(id, 26)
+
+	other, _ := ulid.New(moment)
+	assert.NotEqual(id, other)
+	assert.Equal(id[:10], other[:10])
+}
+
+func TestNew_sortsByTime(t *testing.T) {
+	assert := assert.New(t)
+	moment := time.Date(2025, 1, 10, 12, 30, 0, 0, time.UTC)
+
+	before, _ := ulid.New(moment)
+	after, _ := ulid.New(moment.Add(time.Millisecond))
+	assert.Less(before, after)
+}
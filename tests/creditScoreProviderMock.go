@@ -0,0 +1,14 @@
+package tests
+
+import (
+	"github.com/stretchr/testify/mock"
+)
+
+type MockCreditScoreProvider struct {
+	mock.Mock
+}
+
+func (m *MockCreditScoreProvider) GetCreditScore(email string) (int, error) {
+	args := m.Called(email)
+	return args.Int(0), args.Error(1)
+}
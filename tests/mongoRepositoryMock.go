@@ -1,6 +1,8 @@
 package tests
 
 import (
+	"github.com/Jonattas-21/loan-engine/internal/domain/entities"
+	"github.com/Jonattas-21/loan-engine/internal/domain/interfaces"
 	"github.com/stretchr/testify/mock"
 )
 
@@ -18,6 +20,36 @@ func (m *MockRepository[T]) GetItemsCollection(collection string) ([]T, error) {
 	return args.Get(0).([]T), args.Error(1)
 }
 
+func (m *MockRepository[T]) Find(query interfaces.Query) ([]T, error) {
+	args := m.Called(query)
+	return args.Get(0).([]T), args.Error(1)
+}
+
+func (m *MockRepository[T]) FindOne(filter interfaces.Filter) (T, error) {
+	args := m.Called(filter)
+	return args.Get(0).(T), args.Error(1)
+}
+
+func (m *MockRepository[T]) Count(filter interfaces.Filter) (int64, error) {
+	args := m.Called(filter)
+	return args.Get(0).(int64), args.Error(1)
+}
+
+func (m *MockRepository[T]) Upsert(filter interfaces.Filter, item T) error {
+	args := m.Called(filter, item)
+	return args.Error(0)
+}
+
+func (m *MockRepository[T]) Delete(filter interfaces.Filter) error {
+	args := m.Called(filter)
+	return args.Error(0)
+}
+
+func (m *MockRepository[T]) FindByID(id string) (T, error) {
+	args := m.Called(id)
+	return args.Get(0).(T), args.Error(1)
+}
+
 func (m *MockRepository[T]) UpdateItemCollection(name string, fields map[string]interface{}) error {
 	args := m.Called(name, fields)
 	return args.Error(0)
@@ -37,3 +69,17 @@ func (m *MockRepository[T]) TrunkCollection() error {
 	args := m.Called()
 	return args.Error(0)
 }
+
+type MockLoanSimulationRepository struct {
+	MockRepository[entities.LoanSimulation]
+}
+
+func (m *MockLoanSimulationRepository) SearchLoanSimulations(query interfaces.LoanSimulationQuery) (interfaces.LoanSimulationPage, error) {
+	args := m.Called(query)
+	return args.Get(0).(interfaces.LoanSimulationPage), args.Error(1)
+}
+
+func (m *MockLoanSimulationRepository) CreateIndexes() error {
+	args := m.Called()
+	return args.Error(0)
+}
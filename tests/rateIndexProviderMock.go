@@ -0,0 +1,15 @@
+package tests
+
+import (
+	"github.com/Jonattas-21/loan-engine/internal/domain/entities"
+	"github.com/stretchr/testify/mock"
+)
+
+type MockRateIndexProvider struct {
+	mock.Mock
+}
+
+func (m *MockRateIndexProvider) GetIndexValues(index string) ([]entities.IndexValue, error) {
+	args := m.Called(index)
+	return args.Get(0).([]entities.IndexValue), args.Error(1)
+}